@@ -3,10 +3,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/AnhCaooo/go-goods/log"
 	_ "github.com/AnhCaooo/stormbreaker/docs"
@@ -15,9 +17,13 @@ import (
 	"github.com/AnhCaooo/stormbreaker/internal/config"
 	"github.com/AnhCaooo/stormbreaker/internal/constants"
 	"github.com/AnhCaooo/stormbreaker/internal/db"
+	"github.com/AnhCaooo/stormbreaker/internal/electric"
+	"github.com/AnhCaooo/stormbreaker/internal/events"
+	"github.com/AnhCaooo/stormbreaker/internal/health"
 	"github.com/AnhCaooo/stormbreaker/internal/models"
 	"github.com/AnhCaooo/stormbreaker/internal/rabbitmq"
 	"github.com/AnhCaooo/stormbreaker/internal/scheduler"
+	"github.com/AnhCaooo/stormbreaker/internal/tracing"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -46,6 +52,17 @@ func main() {
 		logger.Fatal(constants.Server, zap.Error(err))
 	}
 
+	// Initialize tracing, spans are exported only when it is enabled
+	shutdownTracing, err := tracing.Setup(ctx, configuration.Tracing)
+	if err != nil {
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+	defer func() {
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("failed to flush spans", zap.Error(err))
+		}
+	}()
+
 	// Initialize cache
 	cache := cache.NewCache(logger)
 
@@ -75,8 +92,15 @@ func run(
 	var wg sync.WaitGroup
 	errChan := make(chan error, 3)
 	stopChan := make(chan struct{})
+	// Events hub which passes price and settings changes to open event streams
+	hub := events.NewHub(logger)
+	// Health monitor which answers readiness probes by checking the dependencies
+	startedAt := time.Now()
+	monitor := health.NewMonitor()
+	monitor.AddCheck("mongo", mongo.Ping)
+	monitor.AddCheck("upstream_prices", health.MaxAge(electric.LastFetchedAt, health.PRICE_FETCH_MAX_AGE, startedAt, time.Now))
 	// HTTP server
-	httpServer := api.NewHTTPServer(ctx, logger, config, cache, mongo)
+	httpServer := api.NewHTTPServer(ctx, logger, config, cache, mongo, hub, monitor)
 	httpServer.Start(1, errChan, &wg)
 
 	// RabbitMQ consumers
@@ -86,10 +110,17 @@ func run(
 	}
 	logger.Info("successfully connected to RabbitMQ")
 	rabbitMQ.StartConsumers(&wg, errChan, stopChan)
+	monitor.AddCheck("rabbitmq", func(ctx context.Context) error {
+		if !rabbitMQ.IsConnected() {
+			return errors.New("connection with RabbitMQ is closed")
+		}
+		return nil
+	})
 
 	// Scheduler worker
-	scheduler := scheduler.NewScheduler(ctx, logger, &config.MessageBroker, cache, mongo)
-	scheduler.StartJobs(&wg)
+	scheduler := scheduler.NewScheduler(ctx, logger, &config.MessageBroker, config.DefaultPriceSettings, cache, mongo, hub)
+	scheduler.StartJobs(&wg, stopChan)
+	monitor.AddCheck("scheduler", health.MaxAge(scheduler.LastHeartbeat, health.SCHEDULER_HEARTBEAT_MAX_AGE, startedAt, time.Now))
 
 	// Monitor all errors from errChan and log them
 	go func() {
@@ -101,11 +132,13 @@ func run(
 	// Wait for termination signal
 	<-stop
 	logger.Info("termination signal received")
-	// Signal all consumers to stop
+	// Report not ready first, so that load balancers stop sending new requests before server stops
+	monitor.ShutDown()
+	time.Sleep(health.SHUTDOWN_DRAIN_PERIOD)
+	// Signal all consumers and scheduling jobs to stop
 	close(stopChan)
 	httpServer.Stop()
 	rabbitMQ.CloseConnection()
-	scheduler.StopJobs()
 	// Wait for all goroutines to finish
 	wg.Wait()
 	// Signal all errors to stop
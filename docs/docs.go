@@ -18,9 +18,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/v1/market-price": {
-            "post": {
-                "description": "Fetch the market spot price of electric in Finland in any times",
+        "/admin/price-settings": {
+            "get": {
+                "description": "Lists the price settings of all users page by page, ordered by user and household. Only admins may use it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -28,51 +28,98 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "market-price"
+                    "admin"
                 ],
-                "summary": "Retrieves the market price",
+                "summary": "Lists the price settings of all users",
                 "parameters": [
                     {
-                        "description": "Criteria for getting market spot price",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PriceRequest"
-                        }
+                        "type": "string",
+                        "description": "only the settings of user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only the settings of household",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only the settings which include VAT or not",
+                        "name": "vat_included",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only the settings which have a monthly budget or not",
+                        "name": "has_budget",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "only the settings whose margin (c/kWh) is at least this",
+                        "name": "min_margin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "only the settings whose margin (c/kWh) is at most this",
+                        "name": "max_margin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "page number, from 1 up to 10000",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "amount of settings per page, at most 200",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PriceResponse"
+                            "$ref": "#/definitions/models.PriceSettingsPage"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid filters or page",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "401": {
                         "description": "Unauthenticated/Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden: user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "description": "Various reasons: failed to read settings from db, etc.",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             }
         },
-        "/v1/market-price/today-tomorrow": {
-            "get": {
-                "description": "Returns the exchange price for today and tomorrow.\nIf tomorrow price is not available yet, return empty struct.\nThen client needs to show readable information to indicate that data is not available yet.",
+        "/admin/price-settings/{household_id}": {
+            "delete": {
+                "description": "Deletes the price settings of any household, regardless of who owns it. Only admins may use it.\nThe household gets the default price settings from configuration on its next access.",
                 "consumes": [
                     "application/json"
                 ],
@@ -80,34 +127,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "market-price"
+                    "admin"
+                ],
+                "summary": "Deletes the price settings of household",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household",
+                        "name": "household_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Retrieves the market price for today and tomorrow",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TodayTomorrowPrice"
+                            "type": "string"
                         }
                     },
                     "401": {
                         "description": "Unauthenticated/Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden: user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Settings not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "description": "Various reasons: failed to delete settings from db, etc.",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             }
         },
-        "/v1/price-settings": {
+        "/admin/summary": {
             "get": {
-                "description": "retrieves the price settings for specific user by identify through 'access token'.",
+                "description": "Counts the users and households which have price settings, and the distribution of margins and monthly budgets.\nOnly admins may use it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -115,38 +183,99 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "price-settings"
+                    "admin"
                 ],
-                "summary": "Retrieves the price settings for specific user",
+                "summary": "Summarizes the users and their price settings",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PriceSettings"
+                            "$ref": "#/definitions/models.PriceSettingsSummary"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden: user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to read settings from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{user_id}/price-settings": {
+            "get": {
+                "description": "Retrieves the price settings of every household which user owns. Only admins may use it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Retrieves the price settings of user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the user",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PriceSettings"
+                            }
                         }
                     },
                     "401": {
                         "description": "Unauthenticated/Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden: user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "User has no settings",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "description": "Various reasons: failed to read settings from db, etc.",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/graphql": {
             "post": {
-                "description": "Creates a new price settings for new user by identify through 'access token'.",
+                "description": "Executes the GraphQL query over households, price settings, today's and tomorrow's prices,\ntheir statistics and the cheapest and most expensive periods, so that client fetches them in one round trip.\nUser only sees the households which user owns or is a member of, by identify through 'access token'.\nThe schema is in ` + "`" + `internal/api/handlers/schema.graphql` + "`" + `. Like GraphQL servers do, the errors of query\nare returned in ` + "`" + `errors` + "`" + ` of the response with status 200, and ` + "`" + `extensions.code` + "`" + ` is the code of problem details.\nQueries are limited to depth 6 and 8192 bytes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -154,61 +283,91 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "price-settings"
+                    "graphql"
                 ],
-                "summary": "Creates a new price settings for user",
+                "summary": "Executes GraphQL query",
                 "parameters": [
                     {
-                        "description": "user price settings",
+                        "description": "GraphQL query",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.PriceSettings"
+                            "$ref": "#/definitions/models.GraphQLRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "` + "`" + `data` + "`" + ` and ` + "`" + `errors` + "`" + ` of the query",
                         "schema": {
-                            "type": "string"
+                            "type": "object"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid request or too long query",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "401": {
                         "description": "Unauthenticated/Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
-                    },
-                    "404": {
-                        "description": "Settings not found",
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Reports that the service is running and able to answer. It does not check the dependencies, so that\nthe service is not restarted when only database or message broker is down. No access token is needed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/health.CheckResult"
                         }
-                    },
-                    "409": {
-                        "description": "Settings exist already",
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Reports whether the service is ready to serve requests, with the result of every dependency check:\ndatabase ping, RabbitMQ connection, freshness of the last successful price fetch from 3rd party and heartbeat of scheduler.\nThe service is not ready while it is shutting down. No access token is needed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Ready",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/health.Report"
                         }
                     },
-                    "500": {
-                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                    "503": {
+                        "description": "Not ready or shutting down",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/health.Report"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes the price settings for specific user by identify through 'access token'.",
+            }
+        },
+        "/v1/calendar-feed": {
+            "post": {
+                "description": "Creates the iCalendar feed of cheapest and most expensive periods of today and tomorrow for user by identify through 'access token'.\nThe household is selected through query ` + "`" + `household_id` + "`" + `. User subscribes to the returned ` + "`" + `url` + "`" + ` in calendar application (ex: Google Calendar, Outlook).\nThe token is only returned once. Creating the feed again replaces the token, so the previous URL stops working.",
                 "consumes": [
                     "application/json"
                 ],
@@ -216,44 +375,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "price-settings"
+                    "calendar"
+                ],
+                "summary": "Creates the calendar feed of household for user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. Default household of user when empty.",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "length of periods",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CalendarFeedRequest"
+                        }
+                    }
                 ],
-                "summary": "Deletes the price settings for specific user",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.CalendarFeedResponse"
                         }
                     },
                     "400": {
                         "description": "Invalid request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "401": {
                         "description": "Unauthenticated/Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "404": {
-                        "description": "Settings not found",
+                        "description": "Household not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "description": "Various reasons: failed to write calendar feed to db, etc.",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             },
-            "patch": {
-                "description": "Updates the price settings for specific user by identify through 'access token'.",
+            "delete": {
+                "description": "Deletes the iCalendar feed of household for user by identify through 'access token', so that its URL stops working.\nThe household is selected through query ` + "`" + `household_id` + "`" + `.",
                 "consumes": [
                     "application/json"
                 ],
@@ -261,18 +437,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "price-settings"
+                    "calendar"
                 ],
-                "summary": "Updates the price settings for specific user",
+                "summary": "Deletes the calendar feed of household for user",
                 "parameters": [
                     {
-                        "description": "user price settings",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PriceSettings"
-                        }
+                        "type": "string",
+                        "description": "id of the household. Default household of user when empty.",
+                        "name": "household_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -282,87 +455,2527 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "401": {
                         "description": "Unauthenticated/Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "404": {
-                        "description": "Settings not found",
+                        "description": "Calendar feed not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     },
                     "500": {
-                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "description": "Various reasons: failed to delete calendar feed from db, etc.",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problem.Details"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.DailyPrice": {
-            "type": "object",
-            "properties": {
-                "available": {
-                    "type": "boolean"
-                },
-                "prices": {
-                    "$ref": "#/definitions/models.PriceSeries"
+        },
+        "/v1/calendar.ics": {
+            "get": {
+                "description": "Retrieves the iCalendar feed with the cheapest and most expensive periods of today and tomorrow, with price settings of household applied.\nCalendar applications cannot send 'access token', so the feed is identified by the secret token from ` + "`" + `POST /v1/calendar-feed` + "`" + `.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Retrieves the iCalendar feed of cheap and expensive periods",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "secret token of the feed",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Calendar feed not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to fetch prices, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
                 }
             }
         },
-        "models.Data": {
-            "type": "object",
-            "properties": {
-                "includeVat": {
-                    "description": "IncludeVat is legacy property that return string value and value \"0\" means no VAT included and string \"1\" is included",
-                    "type": "string",
-                    "enum": [
-                        "0",
-                        "1"
-                    ],
-                    "example": "1"
-                },
-                "isToday": {
-                    "description": "IsToday indicates whether the current time is today or not",
-                    "type": "boolean",
-                    "example": false
+        "/v1/cost": {
+            "get": {
+                "description": "Joins the stored consumption of user with spot prices, user's margin, VAT and electricity tax.\nReturns the cost per slot, per day and per month, along with the consumption-weighted average price\nthat user actually paid versus the plain average price.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cost"
+                ],
+                "summary": "Calculates the actual cost of electricity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date in format YYYY-MM-DD",
+                        "name": "starttime",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in format YYYY-MM-DD",
+                        "name": "endtime",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings or consumption from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/cost/projection": {
+            "get": {
+                "description": "Builds a projection of the current month's bill from consumption to date, typical remaining consumption\nand known (today, tomorrow) or forecast prices. The projection is compared to the monthly budget in user's price settings.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cost"
+                ],
+                "summary": "Projects the electricity bill for the current month",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BillProjection"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings or consumption from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/household-invitations": {
+            "get": {
+                "description": "Retrieves the invitations to households of other users which user by identify through 'access token' has not accepted yet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Retrieves the pending household invitations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.HouseholdMember"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to read invitations from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/household-invitations/{id}/accept": {
+            "post": {
+                "description": "User by identify through 'access token' accepts the invitation to household and becomes its member.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Accepts a household invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to write membership to db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/households": {
+            "get": {
+                "description": "Retrieves all households (homes, metering points) for specific user by identify through 'access token'.\nEvery user has a default household with the same id as the user, which comes first.\nThe households which other users shared with user come last, with the role of user in them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Retrieves the households for specific user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Household"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to read households from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new household (ex: summer cottage) for user by identify through 'access token'.\nThe household gets its own price settings, consumption and price alerts, which are selected through query ` + "`" + `household_id` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Creates a new household for user",
+                "parameters": [
+                    {
+                        "description": "household",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Household"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Household"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to write household to db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/households/{id}": {
+            "get": {
+                "description": "Retrieves a household by id which user owns or is a member of, by identify through 'access token'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Retrieves a household for specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Household"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Household not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces a household by id for user by identify through 'access token'. The default household cannot be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Updates a household for user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "household",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Household"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Household not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to write household to db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a household by id for user by identify through 'access token', together with its price settings,\nconsumption and price alerts. The default household cannot be deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Deletes a household for user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Household not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to delete household from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/households/{id}/members": {
+            "get": {
+                "description": "Retrieves the members and pending invitations of household which user owns or is a member of.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Retrieves the members of household",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.HouseholdMember"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Household not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to read members from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "The owner of household invites another user (Supabase user id) with role 'viewer' or 'editor'.\nThe invited user becomes a member after accepting the invitation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Invites a user to household",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "invited user and role",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdMember"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Household not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "409": {
+                        "description": "User is invited already",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to write invitation to db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/households/{id}/members/{user_id}": {
+            "put": {
+                "description": "The owner of household changes the role of member (or invited user) to 'viewer' or 'editor'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Changes the role of household member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the member",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new role of the member",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdMember"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Household or member not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to write member to db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "The owner of household removes a member or withdraws an invitation.\nA member can remove themselves to leave the household or to decline the invitation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "households"
+                ],
+                "summary": "Removes a member from household",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the member",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Household or member not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to delete member from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/market-price": {
+            "post": {
+                "description": "Fetch the market spot price of electric in Finland in any times.\nThe price settings which were in force at each slot are applied: (spot price + margin) * VAT factor, VAT only when included.\nThe prices are returned as CSV or XLSX spreadsheet instead of JSON when requested through ` + "`" + `Accept` + "`" + ` header.\nPrices of the days which have passed do not change, so their response gets ` + "`" + `Cache-Control` + "`" + ` and ` + "`" + `ETag` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "market-price"
+                ],
+                "summary": "Retrieves the market price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Criteria for getting market spot price",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "how long client may reuse the prices, only when all requested days have passed. Ex: private, max-age=86400"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the prices, only when all requested days have passed"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/market-price/stream": {
+            "get": {
+                "description": "Keeps a Server-Sent Events stream open and pushes these events of household, with its price settings applied:\n` + "`" + `price` + "`" + ` with the current price when the stream opens and whenever a new price slot (hour or quarter) begins,\n` + "`" + `tomorrow_available` + "`" + ` with tomorrow's prices once they are published,\n` + "`" + `settings_changed` + "`" + ` with the new price settings after they were changed (followed by ` + "`" + `price` + "`" + ` with the current price).\nThe data of every event is JSON. Comment lines are sent regularly to keep the connection open.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "market-price"
+                ],
+                "summary": "Streams live price updates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "stream of events",
+                        "schema": {
+                            "$ref": "#/definitions/models.CurrentPrice"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Household not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: cannot fetch price from 3rd party, streaming is not supported, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/market-price/today-tomorrow": {
+            "get": {
+                "description": "Returns the exchange price for today and tomorrow.\nIf tomorrow price is not available yet, return empty struct.\nThen client needs to show readable information to indicate that data is not available yet.\nThe prices are returned as CSV or XLSX spreadsheet instead of JSON when requested through ` + "`" + `Accept` + "`" + ` header.\nThe response can be reused until the prices change (14:00 when tomorrow's prices are published, or the end of day).\nSend the ` + "`" + `ETag` + "`" + ` through ` + "`" + `If-None-Match` + "`" + ` to get ` + "`" + `304 Not Modified` + "`" + ` when the prices have not changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "market-price"
+                ],
+                "summary": "Retrieves the market price for today and tomorrow",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `ETag` + "`" + ` of the prices which client has already",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `Last-Modified` + "`" + ` of the prices which client has already",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TodayTomorrowPrice"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "how long client may reuse the prices. Ex: private, max-age=3600"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the prices"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "time when the prices last changed"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Prices have not changed"
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/me": {
+            "delete": {
+                "description": "Erases everything the service holds about specific user by identify through 'access token':\nall households of user together with their data and members, price alerts, household memberships and notification preferences.\nOnce erased, ` + "`" + `user.data_erased` + "`" + ` event is queued and published in the background, which retries it when RabbitMQ is not available.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Erases all data of specific user",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to erase data from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/me/export": {
+            "get": {
+                "description": "Returns everything the service holds about specific user by identify through 'access token' as a downloadable JSON document:\nhouseholds, household members, price settings, settings history and consumption of owned households, price alerts and notification preferences.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Exports all data of specific user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserDataExport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to read data from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notification-preferences": {
+            "get": {
+                "description": "Retrieves the notification preferences for specific user by identify through 'access token'.\nIf user has not stored any preferences yet, the default preferences are returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification-preferences"
+                ],
+                "summary": "Retrieves the notification preferences for specific user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreferences"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Creates or replaces the notification preferences for specific user by identify through 'access token'.\nFields which are omitted keep their stored values, or the default values when user has not stored preferences yet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification-preferences"
+                ],
+                "summary": "Updates the notification preferences for specific user",
+                "parameters": [
+                    {
+                        "description": "user notification preferences",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreferences"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to write preferences to db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/price-alerts": {
+            "get": {
+                "description": "Retrieves all price alert rules for specific user by identify through 'access token'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price-alerts"
+                ],
+                "summary": "Retrieves the price alert rules for specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PriceAlert"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to read alerts from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new price alert rule for user by identify through 'access token'.\nRule types: 'above' (any slot tomorrow exceeds threshold), 'below' (any slot tomorrow is below threshold)\nand 'cheapest_hours' (the cheapest ` + "`" + `hours` + "`" + ` slots tomorrow which are below threshold).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price-alerts"
+                ],
+                "summary": "Creates a new price alert rule for user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "price alert rule",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceAlert"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceAlert"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to write alert to db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/price-alerts/{id}": {
+            "get": {
+                "description": "Retrieves a price alert rule by id for specific user by identify through 'access token'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price-alerts"
+                ],
+                "summary": "Retrieves a price alert rule for specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the price alert rule",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceAlert"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Alert not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces a price alert rule by id for user by identify through 'access token'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price-alerts"
+                ],
+                "summary": "Updates a price alert rule for user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the price alert rule",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "price alert rule",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceAlert"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Alert not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to write alert to db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a price alert rule by id for user by identify through 'access token'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price-alerts"
+                ],
+                "summary": "Deletes a price alert rule for user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the price alert rule",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Alert not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to delete alert from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/price-settings": {
+            "get": {
+                "description": "retrieves the price settings for specific user by identify through 'access token'.\nHousehold which has no price settings yet gets the default price settings from configuration.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price-settings"
+                ],
+                "summary": "Retrieves the price settings for specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceSettings"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the settings, to send back through ` + "`" + `If-Match` + "`" + ` when changing them. Missing for default price settings"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new price settings for new user by identify through 'access token'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price-settings"
+                ],
+                "summary": "Creates a new price settings for user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "user price settings",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceSettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Settings not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "409": {
+                        "description": "Settings exist already",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes the price settings for specific user by identify through 'access token'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price-settings"
+                ],
+                "summary": "Deletes the price settings for specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Settings not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates the price settings for specific user by identify through 'access token'.\nThe request body is a JSON Merge Patch (RFC 7396): only the fields which are present are changed.\nFields cannot be removed with ` + "`" + `null` + "`" + `, send a value instead (ex: ` + "`" + `\"monthly_budget\": 0` + "`" + ` removes the budget).\nSend the ` + "`" + `ETag` + "`" + ` of the settings which were read through ` + "`" + `If-Match` + "`" + `, so that changes from other devices are not overwritten.\nSettings which were stored before they had versions have no ` + "`" + `ETag` + "`" + `, send ` + "`" + `*` + "`" + ` for them.",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price-settings"
+                ],
+                "summary": "Updates the price settings for specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `ETag` + "`" + ` of the settings which client changes. Ex: \\",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "fields of price settings to change",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceSettingsPatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the changed settings"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "404": {
+                        "description": "Settings not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "412": {
+                        "description": "Settings were changed by another request after the version in ` + "`" + `If-Match` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "415": {
+                        "description": "Request body is not ` + "`" + `application/merge-patch+json` + "`" + `",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "428": {
+                        "description": "Request has no ` + "`" + `If-Match` + "`" + ` header",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/price-settings/history": {
+            "get": {
+                "description": "Lists every change of the price settings for specific user by identify through 'access token',\nordered by the time from which it is in force.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price-settings"
+                ],
+                "summary": "Lists the changes of price settings for specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PriceSettingsHistory"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: failed to read settings history from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/market-price": {
+            "post": {
+                "description": "Fetch the market spot price of electric in Finland in any times.\nThe price settings which were in force at each slot are applied.\nUnlike v1, the slots have RFC 3339 start and end with the offset of Finnish time, and every series has its unit, currency and resolution.\nThe prices are returned as CSV or XLSX spreadsheet instead of JSON when requested through ` + "`" + `Accept` + "`" + ` header.\nPrices of the days which have passed do not change, so their response gets ` + "`" + `Cache-Control` + "`" + ` and ` + "`" + `ETag` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "market-price"
+                ],
+                "summary": "Retrieves the market price (v2)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Criteria for getting market spot price",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceRequestV2"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MarketPriceV2"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "how long client may reuse the prices, only when all requested days have passed. Ex: private, max-age=86400"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the prices, only when all requested days have passed"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/market-price/today-tomorrow": {
+            "get": {
+                "description": "Returns the exchange price for today and tomorrow. Tomorrow's prices are not available before they are published.\nUnlike v1, the slots have RFC 3339 start and end with the offset of Finnish time, and every series has its unit, currency and resolution.\nThe prices are returned as CSV or XLSX spreadsheet instead of JSON when requested through ` + "`" + `Accept` + "`" + ` header.\nThe response can be reused until the prices change (14:00 when tomorrow's prices are published, or the end of day).\nSend the ` + "`" + `ETag` + "`" + ` through ` + "`" + `If-None-Match` + "`" + ` to get ` + "`" + `304 Not Modified` + "`" + ` when the prices have not changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "market-price"
+                ],
+                "summary": "Retrieves the market price for today and tomorrow (v2)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the household. The default household of user when empty",
+                        "name": "household_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `ETag` + "`" + ` of the prices which client has already",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `Last-Modified` + "`" + ` of the prices which client has already",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TodayTomorrowPriceV2"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "how long client may reuse the prices. Ex: private, max-age=3600"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the prices"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "time when the prices last changed"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Prices have not changed"
+                    },
+                    "401": {
+                        "description": "Unauthenticated/Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    },
+                    "500": {
+                        "description": "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc.",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Details"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "health.CheckResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "reason why the dependency does not work",
+                    "type": "string",
+                    "example": "failed to ping database"
+                },
+                "status": {
+                    "description": "status of the dependency",
+                    "type": "string",
+                    "enum": [
+                        "up",
+                        "down"
+                    ],
+                    "example": "up"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "description": "result of every dependency check by its name",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.CheckResult"
+                    }
+                },
+                "status": {
+                    "description": "readiness of the service",
+                    "type": "string",
+                    "enum": [
+                        "ready",
+                        "not_ready",
+                        "shutting_down"
+                    ],
+                    "example": "ready"
+                }
+            }
+        },
+        "models.BillProjection": {
+            "type": "object",
+            "properties": {
+                "budget_exceeded": {
+                    "description": "indicates whether the projected cost crosses the monthly budget",
+                    "type": "boolean",
+                    "example": true
+                },
+                "consumption_to_date": {
+                    "description": "consumed energy in kWh so far",
+                    "type": "number",
+                    "example": 212.5
+                },
+                "cost_to_date": {
+                    "description": "cost in EUR so far",
+                    "type": "number",
+                    "example": 14.12
+                },
+                "currency": {
+                    "description": "currency of all costs",
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "month": {
+                    "description": "Month is in format \"YYYY-MM\"",
+                    "type": "string",
+                    "example": "2024-12"
+                },
+                "monthly_budget": {
+                    "description": "budget in EUR from user's price settings. Value 0 means no budget is set.",
+                    "type": "number",
+                    "example": 25
+                },
+                "projected_consumption": {
+                    "description": "expected consumed energy in kWh at the end of the month",
+                    "type": "number",
+                    "example": 430.1
+                },
+                "projected_cost": {
+                    "description": "expected cost in EUR at the end of the month",
+                    "type": "number",
+                    "example": 28.93
+                }
+            }
+        },
+        "models.CalendarFeed": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "time when the token was created",
+                    "type": "string",
+                    "example": "2024-12-09T12:00:00Z"
+                },
+                "hours": {
+                    "description": "length (hours) of the cheapest and most expensive periods",
+                    "type": "integer",
+                    "example": 3
+                },
+                "household_id": {
+                    "description": "id of the household whose prices are in the feed",
+                    "type": "string",
+                    "example": "123456789"
+                },
+                "user_id": {
+                    "description": "id of the user who subscribed to the feed",
+                    "type": "string",
+                    "example": "123456789"
+                }
+            }
+        },
+        "models.CalendarFeedRequest": {
+            "type": "object",
+            "properties": {
+                "hours": {
+                    "description": "length (hours) of the cheapest and most expensive periods. Value 0 means the default (3 hours).",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.CalendarFeedResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "time when the token was created",
+                    "type": "string",
+                    "example": "2024-12-09T12:00:00Z"
+                },
+                "hours": {
+                    "description": "length (hours) of the cheapest and most expensive periods",
+                    "type": "integer",
+                    "example": 3
+                },
+                "household_id": {
+                    "description": "id of the household whose prices are in the feed",
+                    "type": "string",
+                    "example": "123456789"
+                },
+                "token": {
+                    "description": "secret token of the feed",
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "url": {
+                    "description": "URL which user subscribes to in calendar application. Relative to the host of API when public URL of service is not configured",
+                    "type": "string",
+                    "example": "https://localhost:5001/v1/calendar.ics?token=9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "user_id": {
+                    "description": "id of the user who subscribed to the feed",
+                    "type": "string",
+                    "example": "123456789"
+                }
+            }
+        },
+        "models.Consumption": {
+            "type": "object",
+            "properties": {
+                "consumption": {
+                    "description": "consumed energy in kWh",
+                    "type": "number",
+                    "example": 1.25
+                },
+                "household_id": {
+                    "description": "id of the household (metering point) where the electricity was consumed",
+                    "type": "string",
+                    "example": "123456789"
+                },
+                "time": {
+                    "description": "start of the consumption slot in UTC",
+                    "type": "string",
+                    "example": "2024-12-08T22:00:00Z"
+                },
+                "user_id": {
+                    "description": "id of the user who owns the metering data",
+                    "type": "string",
+                    "example": "123456789"
+                }
+            }
+        },
+        "models.CostResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "currency of all costs",
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CostSummary"
+                    }
+                },
+                "endtime": {
+                    "description": "EndDate is in format \"YYYY-MM-DD\"",
+                    "type": "string",
+                    "example": "2024-12-31"
+                },
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CostSummary"
+                    }
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CostSlot"
+                    }
+                },
+                "starttime": {
+                    "description": "StartDate is in format \"YYYY-MM-DD\"",
+                    "type": "string",
+                    "example": "2024-12-01"
+                },
+                "total": {
+                    "$ref": "#/definitions/models.CostSummary"
+                },
+                "unit": {
+                    "description": "unit of all prices",
+                    "type": "string",
+                    "example": "c/kWh"
+                }
+            }
+        },
+        "models.CostSlot": {
+            "type": "object",
+            "properties": {
+                "consumption": {
+                    "description": "consumed energy in kWh",
+                    "type": "number",
+                    "example": 1.25
+                },
+                "cost": {
+                    "description": "cost of the slot in EUR",
+                    "type": "number",
+                    "example": 0.0668
+                },
+                "price": {
+                    "description": "price of the slot with margin, VAT (if included) and electricity tax in c/kWh",
+                    "type": "number",
+                    "example": 5.34
+                },
+                "time": {
+                    "description": "the local time (Finnish time)",
+                    "type": "string",
+                    "example": "2024-12-09 00:00:00"
+                },
+                "time_utc": {
+                    "description": "timestamp in UTC format",
+                    "type": "string",
+                    "example": "2024-12-08 22:00:00"
+                }
+            }
+        },
+        "models.CostSummary": {
+            "type": "object",
+            "properties": {
+                "average_price": {
+                    "description": "plain average of all slot prices in the period in c/kWh",
+                    "type": "number",
+                    "example": 7.12
+                },
+                "consumption": {
+                    "description": "consumed energy in kWh",
+                    "type": "number",
+                    "example": 412.5
+                },
+                "cost": {
+                    "description": "cost in EUR",
+                    "type": "number",
+                    "example": 27.41
+                },
+                "period": {
+                    "description": "Period is \"YYYY-MM-DD\" for a day, \"YYYY-MM\" for a month and empty for the whole range",
+                    "type": "string",
+                    "example": "2024-12"
+                },
+                "weighted_average_price": {
+                    "description": "consumption-weighted average price that user actually paid in c/kWh",
+                    "type": "number",
+                    "example": 6.64
+                }
+            }
+        },
+        "models.CurrentPrice": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "description": "price of the slot with price settings of user applied",
+                    "type": "number",
+                    "example": 2.47
+                },
+                "time": {
+                    "description": "start of the slot in Finnish time",
+                    "type": "string",
+                    "example": "2024-12-09 00:00:00"
+                },
+                "time_utc": {
+                    "description": "start of the slot in UTC",
+                    "type": "string",
+                    "example": "2024-12-08 22:00:00"
+                },
+                "unit": {
+                    "description": "unit of electric price",
+                    "type": "string",
+                    "example": "c/kWh"
+                }
+            }
+        },
+        "models.DailyPrice": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "prices": {
+                    "$ref": "#/definitions/models.PriceSeries"
+                }
+            }
+        },
+        "models.DailyPriceV2": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "tomorrow's prices are available after they are published around 14:00",
+                    "type": "boolean",
+                    "example": true
+                },
+                "date": {
+                    "description": "the day in Finnish time",
+                    "type": "string",
+                    "example": "2024-12-09"
+                },
+                "prices": {
+                    "$ref": "#/definitions/models.PriceSeriesV2"
+                }
+            }
+        },
+        "models.Data": {
+            "type": "object",
+            "properties": {
+                "includeVat": {
+                    "description": "IncludeVat is legacy property that return string value and value \"0\" means no VAT included and string \"1\" is included",
+                    "type": "string",
+                    "enum": [
+                        "0",
+                        "1"
+                    ],
+                    "example": "1"
+                },
+                "isToday": {
+                    "description": "IsToday indicates whether the current time is today or not",
+                    "type": "boolean",
+                    "example": false
+                },
+                "orig_time": {
+                    "description": "the current time where server is located",
+                    "type": "string",
+                    "example": "2024-12-09 00:00:00"
+                },
+                "price": {
+                    "description": "the price of specified time range",
+                    "type": "number",
+                    "example": 2.47
+                },
+                "time": {
+                    "description": "the current time.",
+                    "type": "string",
+                    "example": "2024-12-09 00:00:00"
+                },
+                "time_utc": {
+                    "description": "timestamp in UTC format",
+                    "type": "string",
+                    "example": "2024-12-08 22:00:00"
+                },
+                "vat_factor": {
+                    "description": "amount of VAT that applies to electric price.",
+                    "type": "number",
+                    "example": 1.255
+                }
+            }
+        },
+        "models.DistributionBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "amount of price settings in the range",
+                    "type": "integer",
+                    "example": 42
+                },
+                "label": {
+                    "description": "range of values. Ex: \"0.5-1\" is from 0.5 up to (but not including) 1",
+                    "type": "string",
+                    "example": "0.5-1"
+                }
+            }
+        },
+        "models.GraphQLRequest": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "description": "operation to execute when the document has several",
+                    "type": "string"
+                },
+                "query": {
+                    "description": "GraphQL document",
+                    "type": "string",
+                    "example": "{ household { priceSettings { margin } todayTomorrow { today { stats { average } } } } }"
+                },
+                "variables": {
+                    "description": "values of the variables in the document",
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "models.Household": {
+            "type": "object",
+            "properties": {
+                "dso": {
+                    "description": "distribution system operator of the household",
+                    "type": "string",
+                    "example": "Caruna"
+                },
+                "id": {
+                    "description": "id of the household. The id of the default household is the id of the user.",
+                    "type": "string",
+                    "example": "6759a8f1c2a4b5e3f1d2c3b4"
+                },
+                "is_default": {
+                    "description": "indicates whether it is the default household of user",
+                    "type": "boolean",
+                    "example": false
+                },
+                "metering_point_id": {
+                    "description": "id of the metering point (GSRN) of the household",
+                    "type": "string",
+                    "example": "643007574000123456"
+                },
+                "name": {
+                    "description": "name of the household which user chose",
+                    "type": "string",
+                    "example": "Summer cottage"
+                },
+                "role": {
+                    "description": "role of the requesting user in the household",
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "editor",
+                        "viewer"
+                    ],
+                    "example": "owner"
+                },
+                "user_id": {
+                    "description": "id of the user who owns the household. The clients (web, mobile) does not need to provide ` + "`" + `user_id` + "`" + ` because the service will read through ` + "`" + `access_token` + "`" + `.",
+                    "type": "string",
+                    "example": "123456789"
+                }
+            }
+        },
+        "models.HouseholdMember": {
+            "type": "object",
+            "properties": {
+                "household_id": {
+                    "description": "id of the shared household",
+                    "type": "string",
+                    "example": "6759a8f1c2a4b5e3f1d2c3b4"
+                },
+                "invited_at": {
+                    "description": "time when the owner invited the user",
+                    "type": "string",
+                    "example": "2024-12-09T12:00:00Z"
+                },
+                "owner_id": {
+                    "description": "id of the user who owns the household and invited the member",
+                    "type": "string",
+                    "example": "123456789"
+                },
+                "role": {
+                    "description": "role of the member in the household",
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor"
+                    ],
+                    "example": "viewer"
+                },
+                "status": {
+                    "description": "status of the invitation. The clients (web, mobile) does not need to provide it.",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "accepted"
+                    ],
+                    "example": "pending"
+                },
+                "user_id": {
+                    "description": "id of the invited user (Supabase user id)",
+                    "type": "string",
+                    "example": "987654321"
+                }
+            }
+        },
+        "models.MarketPriceV2": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "description": "grouping of prices which client requested",
+                    "type": "string",
+                    "enum": [
+                        "hour",
+                        "day",
+                        "week",
+                        "month",
+                        "year"
+                    ],
+                    "example": "hour"
+                },
+                "series": {
+                    "description": "prices of the range, followed by the prices of last year when client requested comparison",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceSeriesV2"
+                    }
+                }
+            }
+        },
+        "models.NotificationPreferences": {
+            "type": "object",
+            "properties": {
+                "delivery_time": {
+                    "description": "preferred local (Finnish) delivery time in format \"HH:MM\". Empty value means as soon as prices are available",
+                    "type": "string",
+                    "example": "18:30"
+                },
+                "language": {
+                    "description": "language of the message",
+                    "type": "string",
+                    "enum": [
+                        "en",
+                        "fi",
+                        "sv"
+                    ],
+                    "example": "fi"
+                },
+                "quiet_hours": {
+                    "description": "period when no message is delivered",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.QuietHours"
+                        }
+                    ]
+                },
+                "tomorrow_prices": {
+                    "description": "indicates whether user wants the daily tomorrow-prices message at all",
+                    "type": "boolean",
+                    "example": true
+                },
+                "user_id": {
+                    "description": "id of the user. The clients (web, mobile) does not need to provide ` + "`" + `user_id` + "`" + ` because the service will read through ` + "`" + `access_token` + "`" + `.",
+                    "type": "string",
+                    "example": "123456789"
+                }
+            }
+        },
+        "models.PriceAlert": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "indicates whether the rule is evaluated or not",
+                    "type": "boolean",
+                    "example": true
+                },
+                "hours": {
+                    "description": "amount of cheapest hours. Only required for type 'cheapest_hours'",
+                    "type": "integer",
+                    "example": 3
+                },
+                "household_id": {
+                    "description": "id of the household whose prices are evaluated. The clients (web, mobile) select the household through query ` + "`" + `household_id` + "`" + `.",
+                    "type": "string",
+                    "example": "123456789"
                 },
-                "orig_time": {
-                    "description": "the current time where server is located",
+                "id": {
+                    "description": "id of the alert rule",
                     "type": "string",
-                    "example": "2024-12-09 00:00:00"
+                    "example": "6759a8f1c2a4b5e3f1d2c3b4"
                 },
-                "price": {
-                    "description": "the price of specified time range",
+                "threshold": {
+                    "description": "price threshold in c/kWh (user's price settings applied)",
                     "type": "number",
-                    "example": 2.47
+                    "example": 15
                 },
-                "time": {
-                    "description": "the current time.",
+                "type": {
+                    "description": "type of the rule",
                     "type": "string",
-                    "example": "2024-12-09 00:00:00"
+                    "enum": [
+                        "above",
+                        "below",
+                        "cheapest_hours"
+                    ],
+                    "example": "above"
                 },
-                "time_utc": {
-                    "description": "timestamp in UTC format",
+                "user_id": {
+                    "description": "id of the user. The clients (web, mobile) does not need to provide ` + "`" + `user_id` + "`" + ` because the service will read through ` + "`" + `access_token` + "`" + `.",
                     "type": "string",
-                    "example": "2024-12-08 22:00:00"
-                },
-                "vat_factor": {
-                    "description": "amount of VAT that applies to electric price.",
-                    "type": "number",
-                    "example": 1.255
+                    "example": "123456789"
                 }
             }
         },
@@ -416,6 +3029,38 @@ const docTemplate = `{
                 }
             }
         },
+        "models.PriceRequestV2": {
+            "type": "object",
+            "properties": {
+                "compare_to_last_year": {
+                    "description": "also returns the prices of the same range last year",
+                    "type": "boolean",
+                    "example": false
+                },
+                "end_date": {
+                    "description": "last day of range in format \"YYYY-MM-DD\"",
+                    "type": "string",
+                    "example": "2024-12-31"
+                },
+                "group": {
+                    "description": "grouping of prices",
+                    "type": "string",
+                    "enum": [
+                        "hour",
+                        "day",
+                        "week",
+                        "month",
+                        "year"
+                    ],
+                    "example": "hour"
+                },
+                "start_date": {
+                    "description": "first day of range in format \"YYYY-MM-DD\"",
+                    "type": "string",
+                    "example": "2024-12-11"
+                }
+            }
+        },
         "models.PriceResponse": {
             "type": "object",
             "properties": {
@@ -443,7 +3088,148 @@ const docTemplate = `{
                 }
             }
         },
+        "models.PriceSeriesV2": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "currency of price",
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceSlotV2"
+                    }
+                },
+                "resolution": {
+                    "description": "length of each slot (ISO 8601 duration)",
+                    "type": "string",
+                    "enum": [
+                        "PT15M",
+                        "PT1H",
+                        "P1D",
+                        "P1W",
+                        "P1M",
+                        "P1Y"
+                    ],
+                    "example": "PT1H"
+                },
+                "unit": {
+                    "description": "unit of price",
+                    "type": "string",
+                    "example": "c/kWh"
+                }
+            }
+        },
         "models.PriceSettings": {
+            "type": "object",
+            "properties": {
+                "household_id": {
+                    "description": "id of the household which the settings belong to. The clients (web, mobile) select the household through query ` + "`" + `household_id` + "`" + `; empty value means the default household.",
+                    "type": "string",
+                    "example": "123456789"
+                },
+                "margin": {
+                    "description": "amount of margin applied to price stats",
+                    "type": "number",
+                    "example": 0.59
+                },
+                "monthly_budget": {
+                    "description": "amount of money (EUR) user plans to spend on electricity per month. Value 0 means no budget is set.",
+                    "type": "number",
+                    "example": 50
+                },
+                "user_id": {
+                    "description": "id of the user. When sends as request, the clients (web, mobile) does not need to provide ` + "`" + `user_id` + "`" + ` because the service will read through ` + "`" + `access_token` + "`" + `.",
+                    "type": "string",
+                    "example": "123456789"
+                },
+                "vat_included": {
+                    "description": "indicates whether tax is included to price stats or not",
+                    "type": "boolean",
+                    "example": true
+                },
+                "version": {
+                    "description": "increases on every change of the settings. It is returned as ` + "`" + `ETag` + "`" + ` so that clients can send it back through ` + "`" + `If-Match` + "`" + `. The clients (web, mobile) does not need to provide it.",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.PriceSettingsHistory": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "description": "settings were deleted, so the default price settings are in force from this time",
+                    "type": "boolean",
+                    "example": false
+                },
+                "effective_from": {
+                    "description": "time from which the settings are in force",
+                    "type": "string",
+                    "example": "2024-12-09T12:00:00Z"
+                },
+                "household_id": {
+                    "description": "id of the household which the settings belong to. The clients (web, mobile) select the household through query ` + "`" + `household_id` + "`" + `; empty value means the default household.",
+                    "type": "string",
+                    "example": "123456789"
+                },
+                "margin": {
+                    "description": "amount of margin applied to price stats",
+                    "type": "number",
+                    "example": 0.59
+                },
+                "monthly_budget": {
+                    "description": "amount of money (EUR) user plans to spend on electricity per month. Value 0 means no budget is set.",
+                    "type": "number",
+                    "example": 50
+                },
+                "user_id": {
+                    "description": "id of the user. When sends as request, the clients (web, mobile) does not need to provide ` + "`" + `user_id` + "`" + ` because the service will read through ` + "`" + `access_token` + "`" + `.",
+                    "type": "string",
+                    "example": "123456789"
+                },
+                "vat_included": {
+                    "description": "indicates whether tax is included to price stats or not",
+                    "type": "boolean",
+                    "example": true
+                },
+                "version": {
+                    "description": "increases on every change of the settings. It is returned as ` + "`" + `ETag` + "`" + ` so that clients can send it back through ` + "`" + `If-Match` + "`" + `. The clients (web, mobile) does not need to provide it.",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.PriceSettingsPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "price settings of the page",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceSettings"
+                    }
+                },
+                "page": {
+                    "description": "page number, starts from 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "description": "amount of settings per page",
+                    "type": "integer",
+                    "example": 50
+                },
+                "total": {
+                    "description": "amount of settings which match the filters",
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "models.PriceSettingsPatch": {
             "type": "object",
             "properties": {
                 "margin": {
@@ -451,8 +3237,13 @@ const docTemplate = `{
                     "type": "number",
                     "example": 0.59
                 },
+                "monthly_budget": {
+                    "description": "amount of money (EUR) user plans to spend on electricity per month. Value 0 removes the budget.",
+                    "type": "number",
+                    "example": 50
+                },
                 "user_id": {
-                    "description": "id of the user",
+                    "description": "id of the user. The clients (web, mobile) does not need to provide ` + "`" + `user_id` + "`" + ` because the service will read through ` + "`" + `access_token` + "`" + `.",
                     "type": "string",
                     "example": "123456789"
                 },
@@ -463,6 +3254,98 @@ const docTemplate = `{
                 }
             }
         },
+        "models.PriceSettingsSummary": {
+            "type": "object",
+            "properties": {
+                "average_budget": {
+                    "description": "average monthly budget (EUR) of the settings which have a budget",
+                    "type": "number",
+                    "example": 75.5
+                },
+                "average_margin": {
+                    "description": "average margin (c/kWh) of the settings which have a margin",
+                    "type": "number",
+                    "example": 0.62
+                },
+                "budget_distribution": {
+                    "description": "amount of price settings by monthly budget (EUR), \"none\" when no budget is set",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DistributionBucket"
+                    }
+                },
+                "households": {
+                    "description": "amount of households which have price settings",
+                    "type": "integer",
+                    "example": 120
+                },
+                "margin_distribution": {
+                    "description": "amount of price settings by margin (c/kWh), \"none\" when no margin is set. The first range starts at the smallest margin when it is negative",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DistributionBucket"
+                    }
+                },
+                "users": {
+                    "description": "amount of users who have price settings",
+                    "type": "integer",
+                    "example": 100
+                },
+                "vat_included": {
+                    "description": "amount of price settings which include VAT",
+                    "type": "integer",
+                    "example": 110
+                },
+                "with_budget": {
+                    "description": "amount of price settings which have a monthly budget",
+                    "type": "integer",
+                    "example": 30
+                }
+            }
+        },
+        "models.PriceSlotV2": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "description": "end of the slot (exclusive) in Finnish time (RFC 3339)",
+                    "type": "string",
+                    "example": "2024-12-09T01:00:00+02:00"
+                },
+                "price": {
+                    "description": "price of the slot with price settings of user applied",
+                    "type": "number",
+                    "example": 2.47
+                },
+                "start": {
+                    "description": "start of the slot in Finnish time (RFC 3339)",
+                    "type": "string",
+                    "example": "2024-12-09T00:00:00+02:00"
+                },
+                "vat_factor": {
+                    "description": "VAT which applies to the price",
+                    "type": "number",
+                    "example": 1.255
+                },
+                "vat_included": {
+                    "description": "indicates whether VAT is included to the price",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.QuietHours": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string",
+                    "example": "07:00"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "22:00"
+                }
+            }
+        },
         "models.TodayTomorrowPrice": {
             "type": "object",
             "properties": {
@@ -473,6 +3356,129 @@ const docTemplate = `{
                     "$ref": "#/definitions/models.DailyPrice"
                 }
             }
+        },
+        "models.TodayTomorrowPriceV2": {
+            "type": "object",
+            "properties": {
+                "today": {
+                    "$ref": "#/definitions/models.DailyPriceV2"
+                },
+                "tomorrow": {
+                    "$ref": "#/definitions/models.DailyPriceV2"
+                }
+            }
+        },
+        "models.UserDataExport": {
+            "type": "object",
+            "properties": {
+                "calendar_feeds": {
+                    "description": "calendar feeds which user subscribed to, without their tokens",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CalendarFeed"
+                    }
+                },
+                "consumption": {
+                    "description": "consumption of households which user owns",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Consumption"
+                    }
+                },
+                "exported_at": {
+                    "description": "time when the export was created",
+                    "type": "string",
+                    "example": "2024-12-09T12:00:00Z"
+                },
+                "household_members": {
+                    "description": "invitations and memberships which user sent or received",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HouseholdMember"
+                    }
+                },
+                "households": {
+                    "description": "households which user owns or is a member of",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Household"
+                    }
+                },
+                "notification_preferences": {
+                    "description": "notification preferences of user, if user set them",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NotificationPreferences"
+                        }
+                    ]
+                },
+                "price_alerts": {
+                    "description": "price alerts which user defined",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceAlert"
+                    }
+                },
+                "price_settings": {
+                    "description": "price settings of households which user owns",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceSettings"
+                    }
+                },
+                "price_settings_history": {
+                    "description": "changes of price settings of households which user owns",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceSettingsHistory"
+                    }
+                },
+                "user_id": {
+                    "description": "id of the user who requested the export",
+                    "type": "string",
+                    "example": "123456789"
+                }
+            }
+        },
+        "problem.Details": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "stable machine-readable error code",
+                    "type": "string",
+                    "example": "not_found"
+                },
+                "detail": {
+                    "description": "message which is safe to show to user",
+                    "type": "string",
+                    "example": "failed to get household: no matched household was found"
+                },
+                "instance": {
+                    "description": "path of the request",
+                    "type": "string",
+                    "example": "/v1/households/6759a8f1c2a4b5e3f1d2c3b4"
+                },
+                "request_id": {
+                    "description": "id of the request, also returned through ` + "`" + `X-Request-ID` + "`" + ` header",
+                    "type": "string",
+                    "example": "4f9c1e0b7a6d5c3b2a1f0e9d8c7b6a5f"
+                },
+                "status": {
+                    "description": "HTTP status code",
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "description": "short summary of the HTTP status",
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "description": "type of the problem. Always \"about:blank\", so ` + "`" + `code` + "`" + ` identifies the problem",
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
         }
     }
 }`
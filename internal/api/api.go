@@ -3,24 +3,31 @@ package api
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"sync"
 	"time"
 
 	httpSwagger "github.com/swaggo/http-swagger" // http-swagger middleware
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/AnhCaooo/stormbreaker/internal/api/handlers"
 	"github.com/AnhCaooo/stormbreaker/internal/api/middleware"
 	"github.com/AnhCaooo/stormbreaker/internal/api/routes"
 	"github.com/AnhCaooo/stormbreaker/internal/cache"
 	"github.com/AnhCaooo/stormbreaker/internal/db"
+	"github.com/AnhCaooo/stormbreaker/internal/events"
+	"github.com/AnhCaooo/stormbreaker/internal/health"
+	"github.com/AnhCaooo/stormbreaker/internal/metrics"
 	"github.com/AnhCaooo/stormbreaker/internal/models"
+	stormbreakerv1 "github.com/AnhCaooo/stormbreaker/proto/stormbreaker/v1"
 	"github.com/gorilla/mux"
 )
 
 // API represents the main structure for the API server.
-// It holds the configuration, context, logger, MongoDB connection,
+// It holds the configuration, context, logger, MongoDB connection, events hub, health monitor,
 // worker ID, HTTP server, and a wait group for managing goroutines.
 type API struct {
 	config   *models.Config
@@ -28,8 +35,12 @@ type API struct {
 	logger   *zap.Logger
 	mongo    *db.Mongo
 	cache    *cache.Cache
+	events   *events.Hub
+	health   *health.Monitor
 	workerID int
 	server   *http.Server
+	rpc      *grpc.Server
+	metrics  *http.Server
 	wg       *sync.WaitGroup
 }
 
@@ -40,6 +51,8 @@ func NewHTTPServer(
 	config *models.Config,
 	cache *cache.Cache,
 	mongo *db.Mongo,
+	hub *events.Hub,
+	monitor *health.Monitor,
 ) *API {
 	return &API{
 		ctx:    ctx,
@@ -47,19 +60,30 @@ func NewHTTPServer(
 		logger: logger,
 		mongo:  mongo,
 		cache:  cache,
+		events: hub,
+		health: monitor,
 	}
 }
 
 // Start initializes and starts the API server in a separate goroutine for a given worker.
 // It sets up the server configuration, assigns the worker ID, and starts the server in a new goroutine.
+// When gRPC port is configured, the gRPC server is started too, sharing the middleware and handlers of HTTP server.
+// gRPC is served over TLS when certificate and key are configured. Metrics are served on their own port when it is configured.
 // If the server encounters an error, it sends the error to the provided error channel.
 func (a *API) Start(workerID int, errChan chan<- error, wg *sync.WaitGroup) {
 	a.workerID = workerID
 	a.wg = wg
+	// Initialize Middleware
+	middleware := middleware.NewMiddleware(a.logger, a.config, a.workerID)
+	// Initialize Handler
+	apiHandler := handlers.NewHandler(a.logger, a.cache, a.mongo, a.config, a.events, a.health, a.workerID)
+
 	a.server = &http.Server{
 		Addr:    fmt.Sprintf(":%s", a.config.Server.Port),
-		Handler: a.newMuxRouter(),
+		Handler: middleware.CORS(a.newMuxRouter(middleware, apiHandler)),
 	}
+	// end the open event streams, otherwise shutdown would wait for them
+	a.server.RegisterOnShutdown(a.events.Close)
 
 	a.wg.Add(1)
 	go func() {
@@ -69,6 +93,77 @@ func (a *API) Start(workerID int, errChan chan<- error, wg *sync.WaitGroup) {
 		}
 	}()
 
+	if a.config.Metrics.Port != "" {
+		a.startMetrics(middleware, errChan)
+	}
+
+	if a.config.Server.GrpcPort == "" {
+		return
+	}
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			middleware.UnaryTracing,
+			middleware.UnaryMetrics,
+			middleware.UnaryRateLimitIP,
+			middleware.UnaryAuthenticate,
+			middleware.UnaryRateLimit,
+		),
+		grpc.ChainStreamInterceptor(
+			middleware.StreamTracing,
+			middleware.StreamMetrics,
+			middleware.StreamRateLimitIP,
+			middleware.StreamAuthenticate,
+			middleware.StreamRateLimit,
+		),
+	}
+	if a.config.Server.GrpcCertFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(a.config.Server.GrpcCertFile, a.config.Server.GrpcKeyFile)
+		if err != nil {
+			errChan <- fmt.Errorf("[worker_%d] error in gRPC worker: failed to load TLS certificate: %s", a.workerID, err.Error())
+			return
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+	a.rpc = grpc.NewServer(options...)
+	stormbreakerv1.RegisterStormbreakerServer(a.rpc, handlers.NewRPCServer(apiHandler))
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.logger.Info(
+			fmt.Sprintf("[worker_%d] gRPC server starting...", a.workerID),
+			zap.String("port", a.config.Server.GrpcPort),
+			zap.Bool("tls", a.config.Server.GrpcCertFile != ""),
+		)
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%s", a.config.Server.GrpcPort))
+		if err != nil {
+			errChan <- fmt.Errorf("[worker_%d] error in gRPC worker: %s", a.workerID, err.Error())
+			return
+		}
+		if err := a.rpc.Serve(listener); err != nil && err != grpc.ErrServerStopped {
+			errChan <- fmt.Errorf("[worker_%d] error in gRPC worker: %s", a.workerID, err.Error())
+		}
+	}()
+}
+
+// startMetrics serves the metrics for Prometheus on their own port, so that clients of API cannot reach them
+func (a *API) startMetrics(middleware *middleware.Middleware, errChan chan<- error) {
+	r := mux.NewRouter()
+	r.Use(middleware.RequestID, middleware.MetricsToken)
+	r.Handle("/metrics", metrics.Handler()).Methods("GET")
+	a.metrics = &http.Server{
+		Addr:    fmt.Sprintf(":%s", a.config.Metrics.Port),
+		Handler: r,
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.logger.Info(fmt.Sprintf("[worker_%d] Metrics server starting...", a.workerID), zap.String("port", a.config.Metrics.Port))
+		if err := a.metrics.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errChan <- fmt.Errorf("[worker_%d] error in metrics worker: %s", a.workerID, err.Error())
+		}
+	}()
 }
 
 // Shutdown the server gracefully
@@ -82,39 +177,76 @@ func (a *API) Stop() {
 	if err := a.server.Shutdown(shutdownCtx); err != nil {
 		a.logger.Fatal(fmt.Sprintf("[worker_%d] Server forced to shutdown", a.workerID), zap.Error(err))
 	}
+	if a.rpc != nil {
+		a.stopRPC(shutdownCtx)
+	}
+	if a.metrics != nil {
+		if err := a.metrics.Shutdown(shutdownCtx); err != nil {
+			a.logger.Error(fmt.Sprintf("[worker_%d] Metrics server forced to shutdown", a.workerID), zap.Error(err))
+		}
+	}
 
 	a.logger.Info(fmt.Sprintf("[worker_%d] HTTP server stopped", a.workerID))
 }
 
-// todo: Proxy, CORS?
-// newMuxRouter is responsible for all the top-level HTTP stuff that
-// applies to all endpoints, like cache, database, CORS, auth middleware, and logging
-func (a *API) newMuxRouter() *mux.Router {
-	// Initialize Middleware
-	middleware := middleware.NewMiddleware(a.logger, a.config, a.workerID)
-	// Initialize Handler
-	apiHandler := handlers.NewHandler(a.logger, a.cache, a.mongo, a.workerID)
-	// Initialize Endpoints pool
-	endpoints := routes.InitializeEndpoints(apiHandler)
+// stopRPC lets the gRPC server finish the calls in progress, and stops it forcefully when they do not finish before `ctx` ends.
+// Price subscriptions ended already when HTTP server closed the events hub.
+func (a *API) stopRPC(ctx context.Context) {
+	a.logger.Info(fmt.Sprintf("[worker_%d] Stopping down gRPC server...", a.workerID), zap.String("port", a.config.Server.GrpcPort))
+	stopped := make(chan struct{})
+	go func() {
+		a.rpc.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		a.logger.Warn(fmt.Sprintf("[worker_%d] gRPC server forced to stop", a.workerID))
+		a.rpc.Stop()
+	}
+}
 
+// newMuxRouter is responsible for all the top-level HTTP stuff that
+// applies to all endpoints, like cache, database, auth middleware, rate limits and logging.
+// CORS wraps the whole router in Start, because preflight requests do not match any route.
+func (a *API) newMuxRouter(middleware *middleware.Middleware, apiHandler *handlers.Handler) *mux.Router {
 	r := mux.NewRouter()
-	// Apply middlewares
+	// Apply middlewares which every route has
 	middlewares := []func(http.Handler) http.Handler{
+		middleware.RequestID,
+		middleware.Tracing,
+		middleware.Metrics,
 		middleware.Logger,
-		middleware.Authenticate,
+		middleware.RateLimitIP,
 	}
 	for _, mw := range middlewares {
 		r.Use(mw)
 	}
 
+	// public routes do not require access token (ex: health probes of orchestrator, API documentation and calendar feed)
+	public := r.NewRoute().Subrouter()
 	// swagger endpoint for API documentation
-	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
-	// Apply endpoint handlers
-	for _, endpoint := range endpoints {
-		r.HandleFunc(endpoint.Path, endpoint.Handler).Methods(endpoint.Method)
+	public.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+	for _, endpoint := range routes.InitializePublicEndpoints(apiHandler) {
+		public.HandleFunc(endpoint.Path, endpoint.Handler).Methods(endpoint.Method)
+	}
+
+	// the other routes require access token of user
+	protected := r.NewRoute().Subrouter()
+	protected.Use(middleware.Authenticate, middleware.RateLimit)
+	for _, endpoint := range routes.InitializeEndpoints(apiHandler) {
+		protected.HandleFunc(endpoint.Path, endpoint.Handler).Methods(endpoint.Method)
+	}
+
+	// admin API, the middlewares of router run before the admin check
+	admin := protected.PathPrefix(routes.ADMIN_PATH_PREFIX).Subrouter()
+	admin.Use(middleware.Authorize)
+	for _, endpoint := range routes.InitializeAdminEndpoints(apiHandler) {
+		admin.HandleFunc(endpoint.Path, endpoint.Handler).Methods(endpoint.Method)
 	}
 
-	r.MethodNotAllowedHandler = http.HandlerFunc(apiHandler.NotAllowed)
-	r.NotFoundHandler = http.HandlerFunc(apiHandler.NotFound)
+	// mux does not apply middlewares to unmatched routes, so the id of request is added here
+	r.MethodNotAllowedHandler = middleware.RequestID(http.HandlerFunc(apiHandler.NotAllowed))
+	r.NotFoundHandler = middleware.RequestID(http.HandlerFunc(apiHandler.NotFound))
 	return r
 }
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// ListPriceSettings lists the price settings of all users
+//
+//	@Summary		Lists the price settings of all users
+//	@Description	Lists the price settings of all users page by page, ordered by user and household. Only admins may use it.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			user_id			query		string	false	"only the settings of user"
+//	@Param			household_id	query		string	false	"only the settings of household"
+//	@Param			vat_included	query		bool	false	"only the settings which include VAT or not"
+//	@Param			has_budget		query		bool	false	"only the settings which have a monthly budget or not"
+//	@Param			min_margin		query		number	false	"only the settings whose margin (c/kWh) is at least this"
+//	@Param			max_margin		query		number	false	"only the settings whose margin (c/kWh) is at most this"
+//	@Param			page			query		int		false	"page number, from 1 up to 10000"	default(1)
+//	@Param			page_size		query		int		false	"amount of settings per page, at most 200"	default(50)
+//	@Success		200	{object}	models.PriceSettingsPage
+//	@Failure		400	{object}	problem.Details "Invalid filters or page"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403	{object}	problem.Details "Forbidden: user is not admin"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to read settings from db, etc."
+//	@Router			/admin/price-settings [get]
+func (h Handler) ListPriceSettings(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	query, err := helpers.ParsePriceSettingsQuery(r.URL.Query())
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, statusCode, err := h.mongo.ListPriceSettings(query)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, page); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// GetUserPriceSettings retrieves the price settings of every household which user owns
+//
+//	@Summary		Retrieves the price settings of user
+//	@Description	Retrieves the price settings of every household which user owns. Only admins may use it.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			user_id	path		string	true	"id of the user"
+//	@Success		200	{array}		models.PriceSettings
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403	{object}	problem.Details "Forbidden: user is not admin"
+//	@Failure		404	{object}	problem.Details "User has no settings"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to read settings from db, etc."
+//	@Router			/admin/users/{user_id}/price-settings [get]
+func (h Handler) GetUserPriceSettings(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	settings, statusCode, err := h.mongo.GetPriceSettingsOfUser(mux.Vars(r)["user_id"])
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, settings); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// ForceDeletePriceSettings deletes the price settings of any household
+//
+//	@Summary		Deletes the price settings of household
+//	@Description	Deletes the price settings of any household, regardless of who owns it. Only admins may use it.
+//	@Description	The household gets the default price settings from configuration on its next access.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			household_id	path		string	true	"id of the household"
+//	@Success		200	{object}	string
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403	{object}	problem.Details "Forbidden: user is not admin"
+//	@Failure		404	{object}	problem.Details "Settings not found"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to delete settings from db, etc."
+//	@Router			/admin/price-settings/{household_id} [delete]
+func (h Handler) ForceDeletePriceSettings(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	adminId, _ := r.Context().Value(constants.UserIdKey).(string)
+	householdID := mux.Vars(r)["household_id"]
+
+	statusCode, err := h.mongo.DeletePriceSettings(householdID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+	h.logger.Info(
+		fmt.Sprintf("[worker_%d] admin deleted price settings", h.workerID),
+		zap.String("admin_id", adminId),
+		zap.String("household_id", householdID),
+	)
+
+	response := map[string]string{
+		"message": "Operation completed successfully",
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, response); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body:", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.settingsChanged(householdID)
+}
+
+// GetPriceSettingsSummary summarizes the users and their price settings
+//
+//	@Summary		Summarizes the users and their price settings
+//	@Description	Counts the users and households which have price settings, and the distribution of margins and monthly budgets.
+//	@Description	Only admins may use it.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.PriceSettingsSummary
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403	{object}	problem.Details "Forbidden: user is not admin"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to read settings from db, etc."
+//	@Router			/admin/summary [get]
+func (h Handler) GetPriceSettingsSummary(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	summary, statusCode, err := h.mongo.SummarizePriceSettings()
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, summary); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
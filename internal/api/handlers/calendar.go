@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.uber.org/zap"
+)
+
+// CALENDAR_CONTENT_TYPE is the media type of iCalendar feed (RFC 5545)
+const CALENDAR_CONTENT_TYPE string = "text/calendar; charset=utf-8"
+
+// CreateCalendarFeed creates the calendar feed of household for user
+//
+//	@Summary		Creates the calendar feed of household for user
+//	@Description	Creates the iCalendar feed of cheapest and most expensive periods of today and tomorrow for user by identify through 'access token'.
+//	@Description	The household is selected through query `household_id`. User subscribes to the returned `url` in calendar application (ex: Google Calendar, Outlook).
+//	@Description	The token is only returned once. Creating the feed again replaces the token, so the previous URL stops working.
+//	@Tags			calendar
+//	@Accept			json
+//	@Produce		json
+//	@Param			household_id	query		string						false	"id of the household. Default household of user when empty."
+//	@Param			payload			body		models.CalendarFeedRequest	true	"length of periods"
+//	@Success		201				{object}	models.CalendarFeedResponse
+//	@Failure		400				{object}	problem.Details "Invalid request"
+//	@Failure		401				{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		404				{object}	problem.Details "Household not found"
+//	@Failure		500				{object}	problem.Details "Various reasons: failed to write calendar feed to db, etc."
+//	@Router			/v1/calendar-feed [post]
+func (h Handler) CreateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.householdFromRequest(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	reqBody, err := encode.DecodeRequest[models.CalendarFeedRequest](r)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	hours, err := helpers.ValidateCalendarHours(reqBody.Hours)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, tokenHash, err := helpers.NewCalendarToken()
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	feed := models.CalendarFeed{
+		TokenHash:   tokenHash,
+		UserID:      userId,
+		HouseholdID: household.ID,
+		Hours:       hours,
+		CreatedAt:   time.Now().UTC(),
+	}
+	statusCode, err = h.mongo.UpsertCalendarFeed(feed)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	response := models.CalendarFeedResponse{
+		CalendarFeed: feed,
+		Token:        token,
+		URL:          helpers.CalendarFeedURL(h.config.Server.PublicURL, token),
+	}
+	if err := encode.EncodeResponse(w, statusCode, response); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// DeleteCalendarFeed deletes the calendar feed of household for user
+//
+//	@Summary		Deletes the calendar feed of household for user
+//	@Description	Deletes the iCalendar feed of household for user by identify through 'access token', so that its URL stops working.
+//	@Description	The household is selected through query `household_id`.
+//	@Tags			calendar
+//	@Accept			json
+//	@Produce		json
+//	@Param			household_id	query		string	false	"id of the household. Default household of user when empty."
+//	@Success		200				{object}	string
+//	@Failure		401				{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		404				{object}	problem.Details "Calendar feed not found"
+//	@Failure		500				{object}	problem.Details "Various reasons: failed to delete calendar feed from db, etc."
+//	@Router			/v1/calendar-feed [delete]
+func (h Handler) DeleteCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	householdID := r.URL.Query().Get("household_id")
+	if householdID == "" {
+		householdID = userId
+	}
+	statusCode, err := h.mongo.DeleteCalendarFeed(userId, householdID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Operation completed successfully",
+	}
+	if err := encode.EncodeResponse(w, statusCode, response); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// GetCalendar returns the iCalendar feed which the token identifies
+//
+//	@Summary		Retrieves the iCalendar feed of cheap and expensive periods
+//	@Description	Retrieves the iCalendar feed with the cheapest and most expensive periods of today and tomorrow, with price settings of household applied.
+//	@Description	Calendar applications cannot send 'access token', so the feed is identified by the secret token from `POST /v1/calendar-feed`.
+//	@Tags			calendar
+//	@Produce		text/calendar
+//	@Param			token	query		string	true	"secret token of the feed"
+//	@Success		200		{string}	string
+//	@Failure		404		{object}	problem.Details "Calendar feed not found"
+//	@Failure		500		{object}	problem.Details "Various reasons: failed to fetch prices, etc."
+//	@Router			/v1/calendar.ics [get]
+func (h Handler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		problem.WriteCode(w, r, http.StatusNotFound, problem.CALENDAR_FEED_NOT_FOUND, "calendar feed not found")
+		return
+	}
+
+	tokenHash := helpers.HashCalendarToken(token)
+	feed, statusCode, err := h.mongo.GetCalendarFeed(tokenHash)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	// the feed stops working once user loses access to household
+	household, statusCode, err := h.mongo.GetAccessibleHousehold(feed.UserID, feed.HouseholdID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	prices, _, err := h.loadTodayTomorrowPrices(household)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// UIDs of events are derived from the hash, so they stay the same between refreshes of the same feed
+	calendar, err := helpers.BuildCalendar(tokenHash[:16], time.Now().UTC(), feed.Hours, prices.Today, prices.Tomorrow)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", CALENDAR_CONTENT_TYPE)
+	w.Header().Set("Content-Disposition", `inline; filename="stormbreaker.ics"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(calendar)); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to write calendar", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		return
+	}
+}
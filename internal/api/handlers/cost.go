@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"go.uber.org/zap"
+)
+
+// GetCost calculates the actual cost of electricity for user in any date range
+//
+//	@Summary		Calculates the actual cost of electricity
+//	@Description	Joins the stored consumption of user with spot prices, user's margin, VAT and electricity tax.
+//	@Description	Returns the cost per slot, per day and per month, along with the consumption-weighted average price
+//	@Description	that user actually paid versus the plain average price.
+//	@Tags			cost
+//	@Accept			json
+//	@Produce		json
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
+//	@Param			starttime	query		string	true	"Start date in format YYYY-MM-DD"
+//	@Param			endtime		query		string	true	"End date in format YYYY-MM-DD"
+//	@Success		200			{object}	models.CostResponse
+//	@Failure		400			{object}	problem.Details "Invalid request"
+//	@Failure		401			{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500			{object}	problem.Details "Various reasons: cannot fetch price from 3rd party, failed to read settings or consumption from db, etc."
+//	@Router			/v1/cost [get]
+func (h Handler) GetCost(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userID, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	startDate := r.URL.Query().Get("starttime")
+	endDate := r.URL.Query().Get("endtime")
+	if startDate == "" || endDate == "" {
+		err := fmt.Errorf("query parameters `starttime` and `endtime` are required")
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	household, statusCode, err := h.householdFromRequest(r, userID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	settings, _, err := h.LoadPriceSettings(household)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	electric := h.newElectric(household, settings)
+	cost, statusCode, err := electric.CalculateCost(startDate, endDate)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, cost); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.logger.Info(fmt.Sprintf("[worker_%d] calculated cost of electricity successfully", h.workerID))
+}
+
+// GetBillProjection projects the electricity bill of user for the current month
+//
+//	@Summary		Projects the electricity bill for the current month
+//	@Description	Builds a projection of the current month's bill from consumption to date, typical remaining consumption
+//	@Description	and known (today, tomorrow) or forecast prices. The projection is compared to the monthly budget in user's price settings.
+//	@Tags			cost
+//	@Accept			json
+//	@Produce		json
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
+//	@Success		200	{object}	models.BillProjection
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500	{object}	problem.Details "Various reasons: cannot fetch price from 3rd party, failed to read settings or consumption from db, etc."
+//	@Router			/v1/cost/projection [get]
+func (h Handler) GetBillProjection(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userID, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.householdFromRequest(r, userID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	settings, _, err := h.LoadPriceSettings(household)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	electric := h.newElectric(household, settings)
+	projection, statusCode, err := electric.ProjectMonthlyBill()
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, projection); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.logger.Info(fmt.Sprintf("[worker_%d] projected monthly bill successfully", h.workerID))
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+)
+
+// graphqlSchema is the schema of GraphQL API
+//
+//go:embed schema.graphql
+var graphqlSchema string
+
+const (
+	// deepest field of schema is at depth 5 (ex: households.todayTomorrow.today.slots.price)
+	GRAPHQL_MAX_DEPTH int = 6
+	// amount of resolvers which run in parallel for one query, so that one query cannot hold all connections to database
+	GRAPHQL_MAX_PARALLELISM int = 5
+	// length (bytes) of query document, which bounds the amount of fields (ex: aliases of households) in one query
+	GRAPHQL_MAX_QUERY_LENGTH int = 8192
+)
+
+// newGraphQLSchema parses the schema of GraphQL API with the resolvers which share the dependencies of handler.
+// Queries which are nested deeper than `GRAPHQL_MAX_DEPTH` are rejected before any resolver runs.
+func newGraphQLSchema(handler Handler) *graphql.Schema {
+	return graphql.MustParseSchema(
+		graphqlSchema,
+		&graphqlResolver{handler: handler},
+		graphql.MaxDepth(GRAPHQL_MAX_DEPTH),
+		graphql.MaxParallelism(GRAPHQL_MAX_PARALLELISM),
+	)
+}
+
+// GraphQL executes the GraphQL query of user
+//
+//	@Summary		Executes GraphQL query
+//	@Description	Executes the GraphQL query over households, price settings, today's and tomorrow's prices,
+//	@Description	their statistics and the cheapest and most expensive periods, so that client fetches them in one round trip.
+//	@Description	User only sees the households which user owns or is a member of, by identify through 'access token'.
+//	@Description	The schema is in `internal/api/handlers/schema.graphql`. Like GraphQL servers do, the errors of query
+//	@Description	are returned in `errors` of the response with status 200, and `extensions.code` is the code of problem details.
+//	@Description	Queries are limited to depth 6 and 8192 bytes.
+//	@Tags			graphql
+//	@Accept			json
+//	@Produce		json
+//	@Param			payload	body		models.GraphQLRequest	true	"GraphQL query"
+//	@Success		200	{object}	object	"`data` and `errors` of the query"
+//	@Failure		400	{object}	problem.Details "Invalid request or too long query"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Router			/graphql [post]
+func (h Handler) GraphQL(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	if _, ok := r.Context().Value(constants.UserIdKey).(string); !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	reqBody, err := encode.DecodeRequest[models.GraphQLRequest](r)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if reqBody.Query == "" {
+		problem.Write(w, r, http.StatusBadRequest, "`query` should not be empty")
+		return
+	}
+	if len(reqBody.Query) > GRAPHQL_MAX_QUERY_LENGTH {
+		problem.Write(w, r, http.StatusBadRequest, fmt.Sprintf("`query` should not be longer than %d bytes", GRAPHQL_MAX_QUERY_LENGTH))
+		return
+	}
+
+	response := h.graphql.Exec(r.Context(), reqBody.Query, reqBody.OperationName, reqBody.Variables)
+	if err := encode.EncodeResponse(w, http.StatusOK, response); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// graphqlError is the error of resolver. Its code is the same as in problem details.
+type graphqlError struct {
+	message   string
+	code      string
+	requestID string
+}
+
+func (e *graphqlError) Error() string {
+	return e.message
+}
+
+// Extensions is added to the error in response, so that clients can branch on the code and find the details in logs
+func (e *graphqlError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code, "request_id": e.requestID}
+}
+
+// graphqlError logs the error and converts it with its HTTP status code to the error of resolver.
+// Like problem details, the message of server errors is not returned to client.
+func (h Handler) graphqlError(ctx context.Context, statusCode int, err error) error {
+	requestID, _ := ctx.Value(constants.RequestIdKey).(string)
+	if statusCode >= http.StatusInternalServerError || statusCode == 0 {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		return &graphqlError{message: problem.INTERNAL_ERROR_MESSAGE, code: problem.INTERNAL_ERROR, requestID: requestID}
+	}
+	h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+	return &graphqlError{message: err.Error(), code: problemCode(statusCode, err), requestID: requestID}
+}
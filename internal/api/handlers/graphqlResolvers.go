@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// graphqlResolver resolves the root query of GraphQL API. The id of user is added to the context by Authenticate middleware.
+type graphqlResolver struct {
+	handler Handler
+}
+
+// Households resolves all households of user
+func (r *graphqlResolver) Households(ctx context.Context) ([]*householdResolver, error) {
+	h := r.handler.withContext(ctx)
+	userID, ok := ctx.Value(constants.UserIdKey).(string)
+	if !ok {
+		return nil, h.graphqlError(ctx, http.StatusUnauthorized, fmt.Errorf("User ID not found in context"))
+	}
+
+	households, statusCode, err := h.mongo.GetHouseholds(userID)
+	if err != nil {
+		return nil, h.graphqlError(ctx, statusCode, err)
+	}
+	resolvers := make([]*householdResolver, 0, len(households))
+	for i := range households {
+		resolvers = append(resolvers, &householdResolver{handler: h, household: &households[i]})
+	}
+	return resolvers, nil
+}
+
+// Household resolves the household by id, or the default household of user when id is omitted
+func (r *graphqlResolver) Household(ctx context.Context, args struct{ ID *graphql.ID }) (*householdResolver, error) {
+	h := r.handler.withContext(ctx)
+	userID, ok := ctx.Value(constants.UserIdKey).(string)
+	if !ok {
+		return nil, h.graphqlError(ctx, http.StatusUnauthorized, fmt.Errorf("User ID not found in context"))
+	}
+
+	householdID := ""
+	if args.ID != nil {
+		householdID = string(*args.ID)
+	}
+	household, statusCode, err := h.accessibleHousehold(userID, householdID)
+	if err != nil {
+		return nil, h.graphqlError(ctx, statusCode, err)
+	}
+	return &householdResolver{handler: h, household: household}, nil
+}
+
+// householdResolver resolves the household and the data which belongs to it.
+// Today's and tomorrow's prices are loaded once, because several fields of query may need them.
+type householdResolver struct {
+	handler    Handler
+	household  *models.Household
+	pricesOnce sync.Once
+	prices     *models.TodayTomorrowPrice
+	pricesErr  error
+}
+
+func (r *householdResolver) ID() graphql.ID {
+	return graphql.ID(r.household.ID)
+}
+
+func (r *householdResolver) Name() string {
+	return r.household.Name
+}
+
+func (r *householdResolver) MeteringPointID() *string {
+	return optionalString(r.household.MeteringPointID)
+}
+
+func (r *householdResolver) DSO() *string {
+	return optionalString(r.household.DSO)
+}
+
+func (r *householdResolver) IsDefault() bool {
+	return r.household.IsDefault
+}
+
+func (r *householdResolver) Role() string {
+	return r.household.Role
+}
+
+// PriceSettings resolves the price settings of household
+func (r *householdResolver) PriceSettings(ctx context.Context) (*priceSettingsResolver, error) {
+	settings, statusCode, err := r.handler.LoadPriceSettings(r.household)
+	if err != nil {
+		return nil, r.handler.graphqlError(ctx, statusCode, err)
+	}
+	return &priceSettingsResolver{settings: settings}, nil
+}
+
+// TodayTomorrow resolves today's and tomorrow's prices with price settings of household applied
+func (r *householdResolver) TodayTomorrow(ctx context.Context) (*todayTomorrowResolver, error) {
+	prices, err := r.loadPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &todayTomorrowResolver{handler: r.handler, prices: prices}, nil
+}
+
+// CurrentPrice resolves the price of the slot which is in force at the moment
+func (r *householdResolver) CurrentPrice(ctx context.Context) (*currentPriceResolver, error) {
+	prices, err := r.loadPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := helpers.CurrentPrice(prices, time.Now().UTC())
+	if err != nil {
+		return nil, r.handler.graphqlError(ctx, http.StatusInternalServerError, err)
+	}
+	if current == nil {
+		return nil, nil
+	}
+	return &currentPriceResolver{price: current}, nil
+}
+
+// loadPrices loads today's and tomorrow's prices of household on the first call and returns the same result afterwards
+func (r *householdResolver) loadPrices(ctx context.Context) (*models.TodayTomorrowPrice, error) {
+	r.pricesOnce.Do(func() {
+		r.prices, _, r.pricesErr = r.handler.loadTodayTomorrowPrices(r.household)
+	})
+	if r.pricesErr != nil {
+		return nil, r.handler.graphqlError(ctx, http.StatusInternalServerError, r.pricesErr)
+	}
+	return r.prices, nil
+}
+
+type priceSettingsResolver struct {
+	settings *models.PriceSettings
+}
+
+func (r *priceSettingsResolver) HouseholdID() graphql.ID {
+	return graphql.ID(r.settings.HouseholdID)
+}
+
+func (r *priceSettingsResolver) VatIncluded() bool {
+	return r.settings.VatIncluded
+}
+
+func (r *priceSettingsResolver) Margin() float64 {
+	return r.settings.Marginal
+}
+
+func (r *priceSettingsResolver) MonthlyBudget() float64 {
+	return r.settings.MonthlyBudget
+}
+
+func (r *priceSettingsResolver) Version() int32 {
+	return int32(r.settings.Version)
+}
+
+type todayTomorrowResolver struct {
+	handler Handler
+	prices  *models.TodayTomorrowPrice
+}
+
+func (r *todayTomorrowResolver) Today() *dailyPriceResolver {
+	return &dailyPriceResolver{handler: r.handler, daily: r.prices.Today}
+}
+
+func (r *todayTomorrowResolver) Tomorrow() *dailyPriceResolver {
+	return &dailyPriceResolver{handler: r.handler, daily: r.prices.Tomorrow}
+}
+
+type dailyPriceResolver struct {
+	handler Handler
+	daily   models.DailyPrice
+}
+
+func (r *dailyPriceResolver) Available() bool {
+	return r.daily.Available
+}
+
+func (r *dailyPriceResolver) Unit() string {
+	return r.daily.Prices.Name
+}
+
+func (r *dailyPriceResolver) Slots() []*priceSlotResolver {
+	slots := make([]*priceSlotResolver, 0, len(r.daily.Prices.Data))
+	for _, data := range r.daily.Prices.Data {
+		slots = append(slots, &priceSlotResolver{data: data})
+	}
+	return slots
+}
+
+func (r *dailyPriceResolver) Stats() *priceStatsResolver {
+	stats := helpers.PriceStatistics(r.daily.Prices)
+	if stats == nil {
+		return nil
+	}
+	return &priceStatsResolver{stats: stats}
+}
+
+// windowArgs are the arguments of the cheapest and most expensive periods
+type windowArgs struct {
+	Hours int32
+}
+
+func (r *dailyPriceResolver) CheapestWindow(ctx context.Context, args windowArgs) (*priceWindowResolver, error) {
+	return r.window(ctx, args, helpers.CheapestWindow)
+}
+
+func (r *dailyPriceResolver) MostExpensiveWindow(ctx context.Context, args windowArgs) (*priceWindowResolver, error) {
+	return r.window(ctx, args, helpers.MostExpensiveWindow)
+}
+
+// window finds the period of `args.Hours` with `find`. Nil is returned when prices of the day are not available.
+func (r *dailyPriceResolver) window(
+	ctx context.Context,
+	args windowArgs,
+	find func(models.PriceSeries, int) (*models.PriceWindow, error),
+) (*priceWindowResolver, error) {
+	if args.Hours < 1 || args.Hours > int32(models.MAX_CALENDAR_HOURS) {
+		return nil, r.handler.graphqlError(ctx, http.StatusBadRequest, fmt.Errorf("`hours` should be between 1 and %d", models.MAX_CALENDAR_HOURS))
+	}
+	if !r.daily.Available {
+		return nil, nil
+	}
+	window, err := find(r.daily.Prices, int(args.Hours))
+	if err != nil {
+		return nil, r.handler.graphqlError(ctx, http.StatusInternalServerError, err)
+	}
+	if window == nil {
+		return nil, nil
+	}
+	return &priceWindowResolver{window: window}, nil
+}
+
+type priceSlotResolver struct {
+	data models.Data
+}
+
+func (r *priceSlotResolver) Time() string {
+	return r.data.Time
+}
+
+func (r *priceSlotResolver) TimeUTC() string {
+	return r.data.TimeUTC
+}
+
+func (r *priceSlotResolver) Price() float64 {
+	return r.data.Price
+}
+
+func (r *priceSlotResolver) VatFactor() float64 {
+	return r.data.VatFactor
+}
+
+func (r *priceSlotResolver) IncludeVat() bool {
+	return r.data.IncludeVat == "1"
+}
+
+type priceStatsResolver struct {
+	stats *models.PriceStats
+}
+
+func (r *priceStatsResolver) Min() float64 {
+	return r.stats.Min
+}
+
+func (r *priceStatsResolver) Max() float64 {
+	return r.stats.Max
+}
+
+func (r *priceStatsResolver) Average() float64 {
+	return r.stats.Average
+}
+
+func (r *priceStatsResolver) Unit() string {
+	return r.stats.Unit
+}
+
+type priceWindowResolver struct {
+	window *models.PriceWindow
+}
+
+func (r *priceWindowResolver) Start() graphql.Time {
+	return graphql.Time{Time: r.window.Start}
+}
+
+func (r *priceWindowResolver) End() graphql.Time {
+	return graphql.Time{Time: r.window.End}
+}
+
+func (r *priceWindowResolver) AveragePrice() float64 {
+	return r.window.AveragePrice
+}
+
+func (r *priceWindowResolver) Unit() string {
+	return r.window.Unit
+}
+
+type currentPriceResolver struct {
+	price *models.CurrentPrice
+}
+
+func (r *currentPriceResolver) Time() string {
+	return r.price.Time
+}
+
+func (r *currentPriceResolver) TimeUTC() string {
+	return r.price.TimeUTC
+}
+
+func (r *currentPriceResolver) Price() float64 {
+	return r.price.Price
+}
+
+func (r *currentPriceResolver) Unit() string {
+	return r.price.Unit
+}
+
+// optionalString returns nil for empty value, so that GraphQL returns null for fields which are not set
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
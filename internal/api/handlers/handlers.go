@@ -2,19 +2,32 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
 	"github.com/AnhCaooo/stormbreaker/internal/cache"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
 	"github.com/AnhCaooo/stormbreaker/internal/db"
+	"github.com/AnhCaooo/stormbreaker/internal/events"
+	"github.com/AnhCaooo/stormbreaker/internal/health"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/graph-gophers/graphql-go"
 	"go.uber.org/zap"
 )
 
 // Handler represents a struct that contains dependencies for handling API requests.
 type Handler struct {
+	// ctx is the context of request which handler serves, set by withContext
+	ctx      context.Context
 	logger   *zap.Logger
 	cache    *cache.Cache
 	mongo    *db.Mongo
+	config   *models.Config
+	events   *events.Hub
+	health   *health.Monitor
+	graphql  *graphql.Schema
 	workerID int
 }
 
@@ -23,32 +36,55 @@ func NewHandler(
 	logger *zap.Logger,
 	cache *cache.Cache,
 	mongo *db.Mongo,
+	config *models.Config,
+	hub *events.Hub,
+	monitor *health.Monitor,
 	workerID int,
 ) *Handler {
 	if mongo == nil {
 		logger.Warn(fmt.Sprintf("[worker_%d] mongoDB client is nil, using mock or no-op database", workerID))
 	}
 
-	return &Handler{
+	handler := &Handler{
+		ctx:      context.Background(),
 		logger:   logger,
 		cache:    cache,
 		mongo:    mongo,
+		config:   config,
+		events:   hub,
+		health:   monitor,
 		workerID: workerID,
 	}
+	handler.graphql = newGraphQLSchema(*handler)
+	return handler
+}
+
+// withContext returns the handler which serves the request of context: its logs (also the logs of database) carry the id of request,
+// and the spans of database and 3rd party belong to the span of request
+func (h Handler) withContext(ctx context.Context) Handler {
+	h.ctx = ctx
+	h.mongo = h.mongo.WithContext(ctx)
+	requestID, ok := ctx.Value(constants.RequestIdKey).(string)
+	if !ok {
+		return h
+	}
+	h.logger = h.logger.With(zap.String(constants.RequestIdField, requestID))
+	h.mongo = h.mongo.WithLogger(h.logger)
+	return h
 }
 
 // return response when request url is not found
 func (h Handler) NotFound(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotFound)
+	h = h.withContext(r.Context())
 	h.logger.Info(fmt.Sprintf("[worker_%d] undefined endpoint", h.workerID), zap.String("method", r.Method), zap.String("endpoint", r.URL.Path))
-	w.Write([]byte("404 - Not found"))
+	problem.Write(w, r, http.StatusNotFound, "The requested endpoint does not exist.")
 }
 
 // return response when request method is not allowed
 func (h Handler) NotAllowed(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusMethodNotAllowed)
+	h = h.withContext(r.Context())
 	h.logger.Info(fmt.Sprintf("[worker_%d] method not allowed", h.workerID), zap.String("method", r.Method), zap.String("endpoint", r.URL.Path))
-	w.Write([]byte("405 - Method not allowed"))
+	problem.Write(w, r, http.StatusMethodNotAllowed, fmt.Sprintf("The endpoint does not support method %s.", r.Method))
 }
 
 // Ping the connection to the server
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/health"
+	"go.uber.org/zap"
+)
+
+// Liveness reports that the service is running
+//
+//	@Summary		Liveness probe
+//	@Description	Reports that the service is running and able to answer. It does not check the dependencies, so that
+//	@Description	the service is not restarted when only database or message broker is down. No access token is needed.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	health.CheckResult
+//	@Router			/healthz [get]
+func (h Handler) Liveness(w http.ResponseWriter, r *http.Request) {
+	if err := encode.EncodeResponse(w, http.StatusOK, health.CheckResult{Status: health.STATUS_UP}); err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server), zap.Error(err))
+	}
+}
+
+// Readiness reports whether the service is ready to serve requests
+//
+//	@Summary		Readiness probe
+//	@Description	Reports whether the service is ready to serve requests, with the result of every dependency check:
+//	@Description	database ping, RabbitMQ connection, freshness of the last successful price fetch from 3rd party and heartbeat of scheduler.
+//	@Description	The service is not ready while it is shutting down. No access token is needed.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	health.Report	"Ready"
+//	@Failure		503	{object}	health.Report	"Not ready or shutting down"
+//	@Router			/readyz [get]
+func (h Handler) Readiness(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	report := h.health.Readiness(r.Context())
+	statusCode := http.StatusOK
+	if report.Status != health.STATUS_READY {
+		statusCode = http.StatusServiceUnavailable
+		h.logger.Warn(fmt.Sprintf("[worker_%d] service is not ready", h.workerID), zap.Any("checks", report.Checks), zap.String("status", report.Status))
+	}
+	if err := encode.EncodeResponse(w, statusCode, report); err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server), zap.Error(err))
+	}
+}
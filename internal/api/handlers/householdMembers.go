@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// GetHouseholdMembers retrieves the members and pending invitations of household
+//
+//	@Summary		Retrieves the members of household
+//	@Description	Retrieves the members and pending invitations of household which user owns or is a member of.
+//	@Tags			households
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"id of the household"
+//	@Success		200	{array}		models.HouseholdMember
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		404	{object}	problem.Details "Household not found"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to read members from db, etc."
+//	@Router			/v1/households/{id}/members [get]
+func (h Handler) GetHouseholdMembers(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.mongo.GetAccessibleHousehold(userId, mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	members, statusCode, err := h.mongo.GetHouseholdMembers(household.ID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, members); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// InviteHouseholdMember invites another user to household with a role
+//
+//	@Summary		Invites a user to household
+//	@Description	The owner of household invites another user (Supabase user id) with role 'viewer' or 'editor'.
+//	@Description	The invited user becomes a member after accepting the invitation.
+//	@Tags			households
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"id of the household"
+//	@Param			payload	body		models.HouseholdMember	true	"invited user and role"
+//	@Success		201		{object}	models.HouseholdMember
+//	@Failure		400		{object}	problem.Details "Invalid request"
+//	@Failure		401		{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403		{object}	problem.Details "Forbidden"
+//	@Failure		404		{object}	problem.Details "Household not found"
+//	@Failure		409		{object}	problem.Details "User is invited already"
+//	@Failure		500		{object}	problem.Details "Various reasons: failed to write invitation to db, etc."
+//	@Router			/v1/households/{id}/members [post]
+func (h Handler) InviteHouseholdMember(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.ownedHousehold(userId, mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	reqBody, err := encode.DecodeRequest[models.HouseholdMember](r)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Patch household and owner from access token to member struct
+	reqBody.HouseholdID = household.ID
+	reqBody.OwnerID = household.UserID
+	if err := helpers.ValidateHouseholdMember(&reqBody); err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	member, statusCode, err := h.mongo.InviteHouseholdMember(reqBody)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, member); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// UpdateHouseholdMember changes the role of member in household
+//
+//	@Summary		Changes the role of household member
+//	@Description	The owner of household changes the role of member (or invited user) to 'viewer' or 'editor'.
+//	@Tags			households
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"id of the household"
+//	@Param			user_id	path		string					true	"id of the member"
+//	@Param			payload	body		models.HouseholdMember	true	"new role of the member"
+//	@Success		200		{object}	string
+//	@Failure		400		{object}	problem.Details "Invalid request"
+//	@Failure		401		{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403		{object}	problem.Details "Forbidden"
+//	@Failure		404		{object}	problem.Details "Household or member not found"
+//	@Failure		500		{object}	problem.Details "Various reasons: failed to write member to db, etc."
+//	@Router			/v1/households/{id}/members/{user_id} [put]
+func (h Handler) UpdateHouseholdMember(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.ownedHousehold(userId, mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	reqBody, err := encode.DecodeRequest[models.HouseholdMember](r)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := helpers.ValidateHouseholdRole(reqBody.Role); err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	statusCode, err = h.mongo.UpdateHouseholdMemberRole(household.ID, mux.Vars(r)["user_id"], reqBody.Role)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Operation completed successfully",
+	}
+	if err := encode.EncodeResponse(w, statusCode, response); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// DeleteHouseholdMember removes a member (or invitation) from household
+//
+//	@Summary		Removes a member from household
+//	@Description	The owner of household removes a member or withdraws an invitation.
+//	@Description	A member can remove themselves to leave the household or to decline the invitation.
+//	@Tags			households
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"id of the household"
+//	@Param			user_id	path		string	true	"id of the member"
+//	@Success		200		{object}	string
+//	@Failure		401		{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403		{object}	problem.Details "Forbidden"
+//	@Failure		404		{object}	problem.Details "Household or member not found"
+//	@Failure		500		{object}	problem.Details "Various reasons: failed to delete member from db, etc."
+//	@Router			/v1/households/{id}/members/{user_id} [delete]
+func (h Handler) DeleteHouseholdMember(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	householdID, memberID := mux.Vars(r)["id"], mux.Vars(r)["user_id"]
+	// members can always leave, only the owner can remove others
+	if memberID != userId {
+		if _, statusCode, err := h.ownedHousehold(userId, householdID); err != nil {
+			h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+			writeProblem(w, r, statusCode, err)
+			return
+		}
+	}
+
+	statusCode, err := h.mongo.DeleteHouseholdMember(householdID, memberID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Operation completed successfully",
+	}
+	if err := encode.EncodeResponse(w, statusCode, response); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// GetHouseholdInvitations retrieves the household invitations which user has not accepted yet
+//
+//	@Summary		Retrieves the pending household invitations
+//	@Description	Retrieves the invitations to households of other users which user by identify through 'access token' has not accepted yet.
+//	@Tags			households
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}		models.HouseholdMember
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to read invitations from db, etc."
+//	@Router			/v1/household-invitations [get]
+func (h Handler) GetHouseholdInvitations(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	invitations, statusCode, err := h.mongo.GetHouseholdInvitations(userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, invitations); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// AcceptHouseholdInvitation makes user a member of household which user was invited to
+//
+//	@Summary		Accepts a household invitation
+//	@Description	User by identify through 'access token' accepts the invitation to household and becomes its member.
+//	@Tags			households
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"id of the household"
+//	@Success		200	{object}	string
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		404	{object}	problem.Details "Invitation not found"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to write membership to db, etc."
+//	@Router			/v1/household-invitations/{id}/accept [post]
+func (h Handler) AcceptHouseholdInvitation(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	statusCode, err := h.mongo.AcceptHouseholdInvitation(userId, mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Operation completed successfully",
+	}
+	if err := encode.EncodeResponse(w, statusCode, response); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// ownedHousehold returns the household if user is its owner. Members get forbidden, others get not found.
+func (h Handler) ownedHousehold(userId, householdID string) (*models.Household, int, error) {
+	household, statusCode, err := h.mongo.GetAccessibleHousehold(userId, householdID)
+	if err != nil {
+		return nil, statusCode, err
+	}
+	if household.Role != models.HOUSEHOLD_ROLE_OWNER {
+		return nil, http.StatusForbidden, fmt.Errorf("only the owner can manage members of household %s", household.ID)
+	}
+	return household, http.StatusOK, nil
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// GetHouseholds retrieves all households of specific user
+//
+//	@Summary		Retrieves the households for specific user
+//	@Description	Retrieves all households (homes, metering points) for specific user by identify through 'access token'.
+//	@Description	Every user has a default household with the same id as the user, which comes first.
+//	@Description	The households which other users shared with user come last, with the role of user in them.
+//	@Tags			households
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}		models.Household
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to read households from db, etc."
+//	@Router			/v1/households [get]
+func (h Handler) GetHouseholds(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	households, statusCode, err := h.mongo.GetHouseholds(userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, households); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// GetHousehold retrieves a single household of specific user
+//
+//	@Summary		Retrieves a household for specific user
+//	@Description	Retrieves a household by id which user owns or is a member of, by identify through 'access token'.
+//	@Tags			households
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"id of the household"
+//	@Success		200	{object}	models.Household
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		404	{object}	problem.Details "Household not found"
+//	@Router			/v1/households/{id} [get]
+func (h Handler) GetHousehold(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.mongo.GetAccessibleHousehold(userId, mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, household); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// CreateHousehold creates a new household for user
+//
+//	@Summary		Creates a new household for user
+//	@Description	Creates a new household (ex: summer cottage) for user by identify through 'access token'.
+//	@Description	The household gets its own price settings, consumption and price alerts, which are selected through query `household_id`.
+//	@Tags			households
+//	@Accept			json
+//	@Produce		json
+//	@Param			payload	body		models.Household	true	"household"
+//	@Success		201		{object}	models.Household
+//	@Failure		400		{object}	problem.Details "Invalid request"
+//	@Failure		401		{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403		{object}	problem.Details "Forbidden"
+//	@Failure		500		{object}	problem.Details "Various reasons: failed to write household to db, etc."
+//	@Router			/v1/households [post]
+func (h Handler) CreateHousehold(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	reqBody, statusCode, err := h.decodeHousehold(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	household, statusCode, err := h.mongo.InsertHousehold(*reqBody)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, household); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// UpdateHousehold replaces a household of user
+//
+//	@Summary		Updates a household for user
+//	@Description	Replaces a household by id for user by identify through 'access token'. The default household cannot be changed.
+//	@Tags			households
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"id of the household"
+//	@Param			payload	body		models.Household	true	"household"
+//	@Success		200		{object}	string
+//	@Failure		400		{object}	problem.Details "Invalid request"
+//	@Failure		401		{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403		{object}	problem.Details "Forbidden"
+//	@Failure		404		{object}	problem.Details "Household not found"
+//	@Failure		500		{object}	problem.Details "Various reasons: failed to write household to db, etc."
+//	@Router			/v1/households/{id} [put]
+func (h Handler) UpdateHousehold(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	reqBody, statusCode, err := h.decodeHousehold(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	statusCode, err = h.mongo.UpdateHousehold(mux.Vars(r)["id"], *reqBody)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Operation completed successfully",
+	}
+	if err := encode.EncodeResponse(w, statusCode, response); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// DeleteHousehold deletes a household of user together with its price settings, consumption and price alerts
+//
+//	@Summary		Deletes a household for user
+//	@Description	Deletes a household by id for user by identify through 'access token', together with its price settings,
+//	@Description	consumption and price alerts. The default household cannot be deleted.
+//	@Tags			households
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"id of the household"
+//	@Success		200	{object}	string
+//	@Failure		400	{object}	problem.Details "Invalid request"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		404	{object}	problem.Details "Household not found"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to delete household from db, etc."
+//	@Router			/v1/households/{id} [delete]
+func (h Handler) DeleteHousehold(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	householdID := mux.Vars(r)["id"]
+	statusCode, err := h.mongo.DeleteHousehold(userId, householdID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Operation completed successfully",
+	}
+	if err := encode.EncodeResponse(w, statusCode, response); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.clearHouseholdCache(householdID)
+}
+
+// householdFromRequest returns the household which the request selects through query `household_id`,
+// with the role of user in it. Without selector, the default household of user (same id as user) is returned.
+// A household which user neither owns nor is a member of is reported as not found.
+func (h Handler) householdFromRequest(r *http.Request, userId string) (household *models.Household, statusCode int, err error) {
+	return h.accessibleHousehold(userId, r.URL.Query().Get("household_id"))
+}
+
+// editableHouseholdFromRequest returns the household which the request selects through query `household_id`,
+// if user is allowed to change its price settings and price alerts (owner or editor).
+func (h Handler) editableHouseholdFromRequest(r *http.Request, userId string) (household *models.Household, statusCode int, err error) {
+	return h.editableHousehold(userId, r.URL.Query().Get("household_id"))
+}
+
+// accessibleHousehold returns the household by id with the role of user in it. Empty id selects the default household of user.
+func (h Handler) accessibleHousehold(userId, householdID string) (household *models.Household, statusCode int, err error) {
+	if householdID == "" {
+		householdID = userId
+	}
+	return h.mongo.GetAccessibleHousehold(userId, householdID)
+}
+
+// editableHousehold returns the household by id, if user is allowed to change its price settings and price alerts (owner or editor)
+func (h Handler) editableHousehold(userId, householdID string) (household *models.Household, statusCode int, err error) {
+	household, statusCode, err = h.accessibleHousehold(userId, householdID)
+	if err != nil {
+		return nil, statusCode, err
+	}
+	if !helpers.CanEditHousehold(household.Role) {
+		return nil, http.StatusForbidden, fmt.Errorf("role '%s' is not allowed to change household %s", household.Role, household.ID)
+	}
+	return household, http.StatusOK, nil
+}
+
+// decodeHousehold decodes and validates the household from request body, then patches userID from access token to it
+func (h Handler) decodeHousehold(r *http.Request, userId string) (*models.Household, int, error) {
+	reqBody, err := encode.DecodeRequest[models.Household](r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	if reqBody.UserID != "" && reqBody.UserID != userId {
+		return nil, http.StatusForbidden, fmt.Errorf("given `user_id` %s is different from `user_id` in `access_token`", reqBody.UserID)
+	}
+	if reqBody.Name == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("household should have a name")
+	}
+
+	reqBody.UserID = userId
+	return &reqBody, http.StatusOK, nil
+}
@@ -1,72 +1,101 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
 	"github.com/AnhCaooo/stormbreaker/internal/cache"
 	"github.com/AnhCaooo/stormbreaker/internal/constants"
 	"github.com/AnhCaooo/stormbreaker/internal/electric"
+	"github.com/AnhCaooo/stormbreaker/internal/export"
 	"github.com/AnhCaooo/stormbreaker/internal/helpers"
 	"github.com/AnhCaooo/stormbreaker/internal/models"
 	"go.uber.org/zap"
 )
 
-// PostMarketPrice fetches the market spot price of electric in Finland in any times
+// PostMarketPrice fetches the market spot price of electric in Finland in any times.
+// The price settings which were in force at each slot are applied.
 //
 //	@Summary		Retrieves the market price
-//	@Description	Fetch the market spot price of electric in Finland in any times
+//	@Description	Fetch the market spot price of electric in Finland in any times.
+//	@Description	The price settings which were in force at each slot are applied: (spot price + margin) * VAT factor, VAT only when included.
+//	@Description	The prices are returned as CSV or XLSX spreadsheet instead of JSON when requested through `Accept` header.
+//	@Description	Prices of the days which have passed do not change, so their response gets `Cache-Control` and `ETag`.
 //	@Tags			market-price
 //	@Accept			json
-//	@Produce		json
+//	@Produce		json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
 //	@Param			payload	body		models.PriceRequest	true	"Criteria for getting market spot price"
 //	@Success		200	{object}	models.PriceResponse
-//	@Failure		400	{string}	string "Invalid request"
-//	@Failure		401	{string}	string "Unauthenticated/Unauthorized"
-//	@Failure		500	{string}	string "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
+//	@Header			200	{string}	ETag			"version of the prices, only when all requested days have passed"
+//	@Header			200	{string}	Cache-Control	"how long client may reuse the prices, only when all requested days have passed. Ex: private, max-age=86400"
+//	@Failure		400	{object}	problem.Details "Invalid request"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500	{object}	problem.Details "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
 //	@Router			/v1/market-price [post]
 func (h Handler) PostMarketPrice(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
 	userID, ok := r.Context().Value(constants.UserIdKey).(string)
 	if !ok {
-		http.Error(w, "User ID not found in context", http.StatusUnauthorized)
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
 		return
 	}
 
-	reqBody, err := encode.DecodeRequest[models.PriceRequest](r)
+	household, statusCode, err := h.householdFromRequest(r, userID)
 	if err != nil {
 		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, statusCode, err)
 		return
 	}
 
-	settings, _, err := h.LoadPriceSettings(userID)
+	reqBody, err := encode.DecodeRequest[models.PriceRequest](r)
 	if err != nil {
-		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
-	electric := electric.NewElectric(h.logger, h.mongo, userID, settings)
-	externalData, statusCode, err := electric.FetchSpotPrice(&reqBody)
+
+	externalData, statusCode, err := h.loadMarketPrices(household, &reqBody)
 	if err != nil {
 		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
-		http.Error(w, err.Error(), statusCode)
+		writeProblem(w, r, statusCode, err)
 		return
 	}
 
-	if err := encode.EncodeResponse(w, statusCode, externalData); err != nil {
+	if err := h.writePrices(w, r, "market-price", externalData, marketPricesFreshness(reqBody), export.MarketPriceSeries(externalData.Data.Series)...); err != nil {
 		h.logger.Error(
 			fmt.Sprintf("[worker_%d] %s failed to encode data from external source", h.workerID, constants.Server),
 			zap.Error(err),
 		)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	h.logger.Info(fmt.Sprintf("[worker_%d] got market price of electric successfully", h.workerID))
 }
 
+// newElectric returns the client of electric prices for household with its price settings.
+// The default price settings are applied while household had no stored price settings.
+func (h Handler) newElectric(household *models.Household, settings *models.PriceSettings) *electric.Electric {
+	defaults := h.config.DefaultPriceSettings.For(household.UserID, household.ID)
+	return electric.NewElectric(h.ctx, h.logger, h.mongo, household.ID, settings, &defaults)
+}
+
+// loadMarketPrices fetches the market prices in any time range. The price settings which were in force at each slot are applied.
+func (h Handler) loadMarketPrices(household *models.Household, request *models.PriceRequest) (*models.PriceResponse, int, error) {
+	settings, _, err := h.LoadPriceSettings(household)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	electric := h.newElectric(household, settings)
+	return electric.FetchSpotPrice(request)
+}
+
 // GetTodayTomorrowPrice returns the exchange price for today and tomorrow.
 // If tomorrow's price is not available yet, return empty struct.
 // Then client (Web, mobile) needs to show readable information to indicate that data is not available yet.
@@ -75,79 +104,100 @@ func (h Handler) PostMarketPrice(w http.ResponseWriter, r *http.Request) {
 //	@Description	Returns the exchange price for today and tomorrow.
 //	@Description	If tomorrow price is not available yet, return empty struct.
 //	@Description	Then client needs to show readable information to indicate that data is not available yet.
+//	@Description	The prices are returned as CSV or XLSX spreadsheet instead of JSON when requested through `Accept` header.
+//	@Description	The response can be reused until the prices change (14:00 when tomorrow's prices are published, or the end of day).
+//	@Description	Send the `ETag` through `If-None-Match` to get `304 Not Modified` when the prices have not changed.
 //	@Tags			market-price
 //	@Accept			json
-//	@Produce		json
+//	@Produce		json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			household_id		query		string	false	"id of the household. The default household of user when empty"
+//	@Param			If-None-Match		header		string	false	"`ETag` of the prices which client has already"
+//	@Param			If-Modified-Since	header		string	false	"`Last-Modified` of the prices which client has already"
 //	@Success		200	{object}	models.TodayTomorrowPrice
-//	@Failure		401	{string}	string "Unauthenticated/Unauthorized"
-//	@Failure		500	{string}	string "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
+//	@Header			200	{string}	ETag			"entity tag of the prices"
+//	@Header			200	{string}	Last-Modified	"time when the prices last changed"
+//	@Header			200	{string}	Cache-Control	"how long client may reuse the prices. Ex: private, max-age=3600"
+//	@Success		304	"Prices have not changed"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500	{object}	problem.Details "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
 //	@Router			/v1/market-price/today-tomorrow [get]
 func (h Handler) GetTodayTomorrowPrice(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
 	userID, ok := r.Context().Value(constants.UserIdKey).(string)
 	if !ok {
-		http.Error(w, "User ID not found in context", http.StatusUnauthorized)
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.householdFromRequest(r, userID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
 		return
 	}
 
-	settings, _, err := h.LoadPriceSettings(userID)
+	todayTomorrowPrices, freshness, err := h.loadTodayTomorrowPrices(household)
 	if err != nil {
 		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
+	if err := h.writePrices(w, r, "today-tomorrow-price", todayTomorrowPrices, &freshness,
+		export.Series{Name: export.SERIES_TODAY, Prices: todayTomorrowPrices.Today.Prices},
+		export.Series{Name: export.SERIES_TOMORROW, Prices: todayTomorrowPrices.Tomorrow.Prices},
+	); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response data", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// loadTodayTomorrowPrices returns today's and tomorrow's prices with the price settings of household applied,
+// together with their validity for HTTP caching.
+// The plain prices in cache are used first, then the cached prices of household. If neither is available,
+// the prices are fetched from external source and cached for household.
+func (h Handler) loadTodayTomorrowPrices(household *models.Household) (*models.TodayTomorrowPrice, helpers.Freshness, error) {
+	settings, _, err := h.LoadPriceSettings(household)
+	if err != nil {
+		return nil, helpers.Freshness{}, err
+	}
 
 	// Load plain price and do mapping with price settings
-	cachePlainPrice, isValid := h.cache.Get(cache.PlainTodayTomorrowPricesKey)
+	cachePlainPrice, isValid := h.cache.Lookup(cache.PlainTodayTomorrowPricesKey)
 	if isValid {
-		pricesMessage, err := helpers.MapInterfaceToStruct[models.NewPricesMessage](cachePlainPrice)
+		pricesMessage, err := helpers.MapInterfaceToStruct[models.NewPricesMessage](cachePlainPrice.Value)
 		if err != nil {
-			h.logger.Error(fmt.Sprintf("[worker_%d] [cache] failed to cast cache data to NewPricesMessage", h.workerID))
-			http.Error(w, "Failed to cast cache data to NewPricesMessage", http.StatusInternalServerError)
-			return
+			return nil, helpers.Freshness{}, fmt.Errorf("[cache] failed to cast cache data to NewPricesMessage: %s", err.Error())
 		}
 
 		// map the price settings with plain current spot price
 		todayTomorrowPrices := helpers.MapPriceSettingsWithTodayTomorrowSpotPrice(settings, &pricesMessage.Data)
-		h.logger.Debug(fmt.Sprintf("[worker_%d] [cache] mapped price settings with plain prices", h.workerID))
-		if err := encode.EncodeResponse(w, http.StatusOK, todayTomorrowPrices); err != nil {
-			h.logger.Error(
-				fmt.Sprintf("[worker_%d] %s failed to encode cache data", h.workerID, constants.Server),
-				zap.Error(err),
-			)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
 		h.logger.Info(fmt.Sprintf("[worker_%d] [cache] get today and tomorrow's exchange price successfully from plain cache prices and price settings", h.workerID))
-		return
+		return todayTomorrowPrices, h.todayTomorrowFreshness(household.ID, todayTomorrowPrices, cachePlainPrice.CreatedAt, cachePlainPrice.Expiration), nil
 	}
 
-	// If plain price is not available, then try to load specific user's spot prices
-	cachePriceKey := fmt.Sprintf("%s_%s", userID, cache.UserTodayTomorrowPricesKey)
-	cachePrice, exists := h.cache.Get(cachePriceKey)
+	// If plain price is not available, then try to load specific household's spot prices
+	cachePriceKey := fmt.Sprintf("%s_%s", household.ID, cache.UserTodayTomorrowPricesKey)
+	cachePrice, exists := h.cache.Lookup(cachePriceKey)
 	if exists {
-		if err := encode.EncodeResponse(w, http.StatusOK, cachePrice); err != nil {
-			h.logger.Error(
-				fmt.Sprintf("[worker_%d] %s failed to encode cache data", h.workerID, constants.Server),
-				zap.Error(err),
-			)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		todayTomorrowPrices, err := helpers.MapInterfaceToStruct[models.TodayTomorrowPrice](cachePrice.Value)
+		if err != nil {
+			return nil, helpers.Freshness{}, fmt.Errorf("[cache] failed to cast cache data to TodayTomorrowPrice: %s", err.Error())
 		}
 		h.logger.Info(fmt.Sprintf("[worker_%d] [cache] get today and tomorrow's exchange price successfully from specific user's cache ", h.workerID))
-		return
+		return todayTomorrowPrices, h.todayTomorrowFreshness(household.ID, todayTomorrowPrices, cachePrice.CreatedAt, cachePrice.Expiration), nil
 	}
 
 	// If both plain and specific user's spot prices are not available, then fetch from external source
-	electric := electric.NewElectric(h.logger, h.mongo, userID, settings)
-	todayTomorrowResponse, err := electric.FetchCurrentSpotPrice(w)
+	electric := h.newElectric(household, settings)
+	todayTomorrowResponse, err := electric.FetchCurrentSpotPrice()
 	if err != nil {
-		h.logger.Error(
-			fmt.Sprintf("[worker_%d] %s failed to fetch today and/or tomorrow spot price from external source", h.workerID, constants.Server),
-			zap.Error(err),
-		)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return nil, helpers.Freshness{}, fmt.Errorf("failed to fetch today and/or tomorrow spot price from external source: %s", err.Error())
 	}
+	freshness := h.todayTomorrowFreshness(household.ID, todayTomorrowResponse, time.Now().UTC(), time.Time{})
 
 	// Cache response to improve performance
 	// if tomorrow price is available already, then cache until 23:59
@@ -158,10 +208,10 @@ func (h Handler) GetTodayTomorrowPrice(w http.ResponseWriter, r *http.Request) {
 				fmt.Sprintf("[worker_%d] %s failed to set expired time for caching", h.workerID, constants.Server),
 				zap.Error(err),
 			)
-			return
+			return todayTomorrowResponse, freshness, nil
 		}
 		h.cache.SetExpiredAtTime(cachePriceKey, &todayTomorrowResponse, expiredTime)
-		return
+		return todayTomorrowResponse, freshness, nil
 	}
 	// if tomorrow price is not available and sending request time is before 14:00, then cache until 14:00
 	expiredTime, err := helpers.SetTime(14, 00)
@@ -170,9 +220,83 @@ func (h Handler) GetTodayTomorrowPrice(w http.ResponseWriter, r *http.Request) {
 			fmt.Sprintf("[worker_%d] %s failed to set expired time for caching", h.workerID, constants.Server),
 			zap.Error(err),
 		)
-		return
+		return todayTomorrowResponse, freshness, nil
 	}
 	if time.Now().Before(expiredTime) {
 		h.cache.SetExpiredAtTime(cachePriceKey, &todayTomorrowResponse, expiredTime)
 	}
+	return todayTomorrowResponse, freshness, nil
+}
+
+// todayTomorrowFreshness returns the validity of today's and tomorrow's prices which were cached at `cachedAt` until `cachedUntil`.
+// The prices also change when price settings of household change, so they are not older than the cached price settings.
+// Zero `cachedUntil` means that the prices were not cached.
+func (h Handler) todayTomorrowFreshness(householdID string, prices *models.TodayTomorrowPrice, cachedAt, cachedUntil time.Time) helpers.Freshness {
+	freshness := helpers.Freshness{LastModified: cachedAt, Expires: cachedUntil}
+	settings, exists := h.cache.Lookup(fmt.Sprintf("%s_%s", householdID, cache.UserPriceSettingsKey))
+	if exists && settings.CreatedAt.After(freshness.LastModified) {
+		freshness.LastModified = settings.CreatedAt
+	}
+
+	now := time.Now().UTC()
+	expires, err := helpers.TodayTomorrowPricesExpiry(now, prices.Tomorrow.Available)
+	if err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to set expired time for HTTP caching", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		expires = now
+	}
+	if freshness.Expires.IsZero() || expires.Before(freshness.Expires) {
+		freshness.Expires = expires
+	}
+	return freshness
+}
+
+// marketPricesFreshness returns the validity of market prices of the requested days,
+// or nil when they may still change so that no caching headers are written
+func marketPricesFreshness(request models.PriceRequest) *helpers.Freshness {
+	freshness, cacheable := helpers.MarketPricesFreshness(request.EndDate, time.Now())
+	if !cacheable {
+		return nil
+	}
+	return &freshness
+}
+
+// writePrices writes the prices in the format which client prefers through `Accept` header.
+// JSON body is written by default, while CSV and XLSX contain the rows of price series and are downloaded as `fileName`.
+// When freshness is given, the response gets HTTP caching headers and `304 Not Modified` is written
+// if client has the same response already (only for GET).
+func (h Handler) writePrices(w http.ResponseWriter, r *http.Request, fileName string, body interface{}, freshness *helpers.Freshness, series ...export.Series) error {
+	format := export.Negotiate(r.Header.Get("Accept"))
+	var buffer bytes.Buffer
+	if format == export.JSON {
+		if err := json.NewEncoder(&buffer).Encode(body); err != nil {
+			return fmt.Errorf("encode json: %s", err.Error())
+		}
+	} else {
+		if err := export.Write(&buffer, format, series...); err != nil {
+			return err
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s.%s", fileName, format)))
+	}
+
+	// prices depend on price settings of user and on the format which client accepts
+	w.Header().Set("Vary", "Authorization, Accept")
+	w.Header().Set("Content-Type", format.ContentType())
+	if freshness != nil {
+		etag := helpers.ContentETag(buffer.Bytes())
+		helpers.SetCacheHeaders(w.Header(), etag, *freshness, time.Now())
+		// conditional POST requests are not answered with 304 (RFC 9110)
+		isSafe := r.Method == http.MethodGet || r.Method == http.MethodHead
+		if isSafe && helpers.IsNotModified(r.Header, etag, freshness.LastModified) {
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Disposition")
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(buffer.Bytes())
+	return err
 }
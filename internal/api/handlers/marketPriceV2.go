@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/export"
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.uber.org/zap"
+)
+
+// PostMarketPriceV2 fetches the market spot price of electric in Finland in any times, in the models of API v2.
+//
+//	@Summary		Retrieves the market price (v2)
+//	@Description	Fetch the market spot price of electric in Finland in any times.
+//	@Description	The price settings which were in force at each slot are applied.
+//	@Description	Unlike v1, the slots have RFC 3339 start and end with the offset of Finnish time, and every series has its unit, currency and resolution.
+//	@Description	The prices are returned as CSV or XLSX spreadsheet instead of JSON when requested through `Accept` header.
+//	@Description	Prices of the days which have passed do not change, so their response gets `Cache-Control` and `ETag`.
+//	@Tags			market-price
+//	@Accept			json
+//	@Produce		json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
+//	@Param			payload	body		models.PriceRequestV2	true	"Criteria for getting market spot price"
+//	@Success		200	{object}	models.MarketPriceV2
+//	@Header			200	{string}	ETag			"version of the prices, only when all requested days have passed"
+//	@Header			200	{string}	Cache-Control	"how long client may reuse the prices, only when all requested days have passed. Ex: private, max-age=86400"
+//	@Failure		400	{object}	problem.Details "Invalid request"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500	{object}	problem.Details "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
+//	@Router			/v2/market-price [post]
+func (h Handler) PostMarketPriceV2(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userID, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.householdFromRequest(r, userID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	reqBody, err := encode.DecodeRequest[models.PriceRequestV2](r)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	priceRequest := helpers.FromPriceRequestV2(reqBody)
+	externalData, statusCode, err := h.loadMarketPrices(household, &priceRequest)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	marketPrice, err := helpers.ToMarketPriceV2(externalData)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := h.writePrices(w, r, "market-price", marketPrice, marketPricesFreshness(priceRequest), export.MarketPriceSeries(externalData.Data.Series)...); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode data from external source", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.logger.Info(fmt.Sprintf("[worker_%d] got market price of electric successfully", h.workerID))
+}
+
+// GetTodayTomorrowPriceV2 returns the exchange price for today and tomorrow, in the models of API v2.
+//
+//	@Summary		Retrieves the market price for today and tomorrow (v2)
+//	@Description	Returns the exchange price for today and tomorrow. Tomorrow's prices are not available before they are published.
+//	@Description	Unlike v1, the slots have RFC 3339 start and end with the offset of Finnish time, and every series has its unit, currency and resolution.
+//	@Description	The prices are returned as CSV or XLSX spreadsheet instead of JSON when requested through `Accept` header.
+//	@Description	The response can be reused until the prices change (14:00 when tomorrow's prices are published, or the end of day).
+//	@Description	Send the `ETag` through `If-None-Match` to get `304 Not Modified` when the prices have not changed.
+//	@Tags			market-price
+//	@Accept			json
+//	@Produce		json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			household_id		query		string	false	"id of the household. The default household of user when empty"
+//	@Param			If-None-Match		header		string	false	"`ETag` of the prices which client has already"
+//	@Param			If-Modified-Since	header		string	false	"`Last-Modified` of the prices which client has already"
+//	@Success		200	{object}	models.TodayTomorrowPriceV2
+//	@Header			200	{string}	ETag			"entity tag of the prices"
+//	@Header			200	{string}	Last-Modified	"time when the prices last changed"
+//	@Header			200	{string}	Cache-Control	"how long client may reuse the prices. Ex: private, max-age=3600"
+//	@Success		304	"Prices have not changed"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500	{object}	problem.Details "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
+//	@Router			/v2/market-price/today-tomorrow [get]
+func (h Handler) GetTodayTomorrowPriceV2(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userID, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.householdFromRequest(r, userID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	todayTomorrowPrices, freshness, err := h.loadTodayTomorrowPrices(household)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	todayTomorrowPricesV2, err := helpers.ToTodayTomorrowPriceV2(todayTomorrowPrices, time.Now())
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := h.writePrices(w, r, "today-tomorrow-price", todayTomorrowPricesV2, &freshness,
+		export.Series{Name: export.SERIES_TODAY, Prices: todayTomorrowPrices.Today.Prices},
+		export.Series{Name: export.SERIES_TOMORROW, Prices: todayTomorrowPrices.Tomorrow.Prices},
+	); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response data", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/AnhCaooo/stormbreaker/internal/rabbitmq"
+	"go.uber.org/zap"
+)
+
+// ExportUserData returns everything the service holds about specific user
+//
+//	@Summary		Exports all data of specific user
+//	@Description	Returns everything the service holds about specific user by identify through 'access token' as a downloadable JSON document:
+//	@Description	households, household members, price settings, settings history and consumption of owned households, price alerts and notification preferences.
+//	@Tags			me
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.UserDataExport
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to read data from db, etc."
+//	@Router			/v1/me/export [get]
+func (h Handler) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	export, statusCode, err := h.mongo.ExportUserData(userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	fileName := fmt.Sprintf("stormbreaker-export-%s.json", export.ExportedAt.Format("2006-01-02"))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	if err := encode.EncodeResponse(w, statusCode, export); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.logger.Info(fmt.Sprintf("[worker_%d] exported user data successfully", h.workerID))
+}
+
+// EraseUserData erases everything the service holds about specific user
+//
+//	@Summary		Erases all data of specific user
+//	@Description	Erases everything the service holds about specific user by identify through 'access token':
+//	@Description	all households of user together with their data and members, price alerts, household memberships and notification preferences.
+//	@Description	Once erased, `user.data_erased` event is queued and published in the background, which retries it when RabbitMQ is not available.
+//	@Tags			me
+//	@Accept			json
+//	@Produce		json
+//	@Success		204
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to erase data from db, etc."
+//	@Router			/v1/me [delete]
+func (h Handler) EraseUserData(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	households, statusCode, err := h.mongo.GetHouseholds(userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	statusCode, err = h.mongo.EraseUserData(userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+	for _, household := range households {
+		if household.Role == models.HOUSEHOLD_ROLE_OWNER {
+			h.clearHouseholdCache(household.ID)
+		}
+	}
+
+	// the erasure is committed at this point, so failing to queue its confirmation does not fail the request
+	if err = h.queueUserDataErased(userId); err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// queueUserDataErased queues the confirmation that all data of user was erased. The message is published
+// by the dispatching job of scheduler, which retries it when RabbitMQ is not available, with the id and trace of request.
+func (h Handler) queueUserDataErased(userId string) error {
+	message, err := json.Marshal(models.UserDataErasedMessage{UserID: userId, ErasedAt: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("data was erased but failed to encode confirmation event: %s", err.Error())
+	}
+
+	// the message is published later, so it keeps the id and trace of the request
+	requestID, traceContext := rabbitmq.SaveMessageContext(h.ctx)
+	if err := h.mongo.ScheduleNotification(models.ScheduledNotification{
+		UserID:       userId,
+		Exchange:     rabbitmq.USER_NOTIFICATIONS_EXCHANGE,
+		RoutingKey:   rabbitmq.USER_DATA_ERASED_KEY,
+		Body:         message,
+		DeliverAt:    time.Now(),
+		RequestID:    requestID,
+		TraceContext: traceContext,
+	}); err != nil {
+		return fmt.Errorf("data was erased but failed to queue confirmation event: %s", err.Error())
+	}
+	return nil
+}
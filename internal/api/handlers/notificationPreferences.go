@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"go.uber.org/zap"
+)
+
+// GetNotificationPreferences retrieves the notification preferences for specific user.
+// If user has not stored any preferences yet, the default preferences are returned.
+//
+//	@Summary		Retrieves the notification preferences for specific user
+//	@Description	Retrieves the notification preferences for specific user by identify through 'access token'.
+//	@Description	If user has not stored any preferences yet, the default preferences are returned.
+//	@Tags			notification-preferences
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.NotificationPreferences
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Router			/v1/notification-preferences [get]
+func (h Handler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	preferences, statusCode, err := h.mongo.GetNotificationPreferences(userId)
+	if err != nil {
+		if statusCode != http.StatusNotFound {
+			h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+			writeProblem(w, r, statusCode, err)
+			return
+		}
+		preferences = helpers.DefaultNotificationPreferences(userId)
+	}
+
+	if err := encode.EncodeResponse(w, http.StatusOK, preferences); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// PutNotificationPreferences creates or replaces the notification preferences for specific user.
+// Fields which are omitted from the body keep their stored or default values.
+//
+//	@Summary		Updates the notification preferences for specific user
+//	@Description	Creates or replaces the notification preferences for specific user by identify through 'access token'.
+//	@Description	Fields which are omitted keep their stored values, or the default values when user has not stored preferences yet.
+//	@Tags			notification-preferences
+//	@Accept			json
+//	@Produce		json
+//	@Param			payload	body		models.NotificationPreferences	true	"user notification preferences"
+//	@Success		200		{object}	string
+//	@Failure		400		{object}	problem.Details "Invalid request"
+//	@Failure		401		{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403		{object}	problem.Details "Forbidden"
+//	@Failure		500		{object}	problem.Details "Various reasons: failed to write preferences to db, etc."
+//	@Router			/v1/notification-preferences [put]
+func (h Handler) PutNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	// omitted fields keep their stored values, or the default ones when user has not stored preferences yet
+	current, statusCode, err := h.mongo.GetNotificationPreferences(userId)
+	if err != nil {
+		if statusCode != http.StatusNotFound {
+			h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+			writeProblem(w, r, statusCode, err)
+			return
+		}
+		current = helpers.DefaultNotificationPreferences(userId)
+	}
+
+	reqBody, err := helpers.MergeNotificationPreferences(current, r.Body)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if reqBody.UserID != "" && reqBody.UserID != userId {
+		err = fmt.Errorf("given `user_id` %s is different from `user_id` in `access_token`", reqBody.UserID)
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err := helpers.ValidateNotificationPreferences(reqBody); err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Patch userID from accessToken to notification preferences struct
+	reqBody.UserID = userId
+	statusCode, err = h.mongo.UpsertNotificationPreferences(*reqBody)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Operation completed successfully",
+	}
+	if err := encode.EncodeResponse(w, statusCode, response); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
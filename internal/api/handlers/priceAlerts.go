@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// GetPriceAlerts retrieves all price alert rules of specific user
+//
+//	@Summary		Retrieves the price alert rules for specific user
+//	@Description	Retrieves all price alert rules for specific user by identify through 'access token'.
+//	@Tags			price-alerts
+//	@Accept			json
+//	@Produce		json
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
+//	@Success		200	{array}		models.PriceAlert
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to read alerts from db, etc."
+//	@Router			/v1/price-alerts [get]
+func (h Handler) GetPriceAlerts(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.householdFromRequest(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	alerts, statusCode, err := h.mongo.GetPriceAlerts(household.ID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, alerts); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// GetPriceAlert retrieves a single price alert rule of specific user
+//
+//	@Summary		Retrieves a price alert rule for specific user
+//	@Description	Retrieves a price alert rule by id for specific user by identify through 'access token'.
+//	@Tags			price-alerts
+//	@Accept			json
+//	@Produce		json
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
+//	@Param			id	path		string	true	"id of the price alert rule"
+//	@Success		200	{object}	models.PriceAlert
+//	@Failure		400	{object}	problem.Details "Invalid request"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		404	{object}	problem.Details "Alert not found"
+//	@Router			/v1/price-alerts/{id} [get]
+func (h Handler) GetPriceAlert(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.householdFromRequest(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	alert, statusCode, err := h.mongo.GetPriceAlert(household.ID, mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, alert); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// CreatePriceAlert creates a new price alert rule for user
+//
+//	@Summary		Creates a new price alert rule for user
+//	@Description	Creates a new price alert rule for user by identify through 'access token'.
+//	@Description	Rule types: 'above' (any slot tomorrow exceeds threshold), 'below' (any slot tomorrow is below threshold)
+//	@Description	and 'cheapest_hours' (the cheapest `hours` slots tomorrow which are below threshold).
+//	@Tags			price-alerts
+//	@Accept			json
+//	@Produce		json
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
+//	@Param			payload	body		models.PriceAlert	true	"price alert rule"
+//	@Success		201		{object}	models.PriceAlert
+//	@Failure		400		{object}	problem.Details "Invalid request"
+//	@Failure		401		{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403		{object}	problem.Details "Forbidden"
+//	@Failure		500		{object}	problem.Details "Various reasons: failed to write alert to db, etc."
+//	@Router			/v1/price-alerts [post]
+func (h Handler) CreatePriceAlert(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.editableHouseholdFromRequest(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	reqBody, statusCode, err := h.decodePriceAlert(r, userId, household.ID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	alert, statusCode, err := h.mongo.InsertPriceAlert(*reqBody)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, alert); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// UpdatePriceAlert replaces a price alert rule of user
+//
+//	@Summary		Updates a price alert rule for user
+//	@Description	Replaces a price alert rule by id for user by identify through 'access token'.
+//	@Tags			price-alerts
+//	@Accept			json
+//	@Produce		json
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
+//	@Param			id		path		string				true	"id of the price alert rule"
+//	@Param			payload	body		models.PriceAlert	true	"price alert rule"
+//	@Success		200		{object}	string
+//	@Failure		400		{object}	problem.Details "Invalid request"
+//	@Failure		401		{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403		{object}	problem.Details "Forbidden"
+//	@Failure		404		{object}	problem.Details "Alert not found"
+//	@Failure		500		{object}	problem.Details "Various reasons: failed to write alert to db, etc."
+//	@Router			/v1/price-alerts/{id} [put]
+func (h Handler) UpdatePriceAlert(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.editableHouseholdFromRequest(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	reqBody, statusCode, err := h.decodePriceAlert(r, userId, household.ID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	statusCode, err = h.mongo.UpdatePriceAlert(mux.Vars(r)["id"], *reqBody)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Operation completed successfully",
+	}
+	if err := encode.EncodeResponse(w, statusCode, response); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// DeletePriceAlert deletes a price alert rule of user
+//
+//	@Summary		Deletes a price alert rule for user
+//	@Description	Deletes a price alert rule by id for user by identify through 'access token'.
+//	@Tags			price-alerts
+//	@Accept			json
+//	@Produce		json
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
+//	@Param			id	path		string	true	"id of the price alert rule"
+//	@Success		200	{object}	string
+//	@Failure		400	{object}	problem.Details "Invalid request"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403	{object}	problem.Details "Forbidden"
+//	@Failure		404	{object}	problem.Details "Alert not found"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to delete alert from db, etc."
+//	@Router			/v1/price-alerts/{id} [delete]
+func (h Handler) DeletePriceAlert(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.editableHouseholdFromRequest(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	statusCode, err = h.mongo.DeletePriceAlert(household.ID, mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Operation completed successfully",
+	}
+	if err := encode.EncodeResponse(w, statusCode, response); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// decodePriceAlert decodes and validates the price alert rule from request body, then patches userID from access token
+// and the selected household to it
+func (h Handler) decodePriceAlert(r *http.Request, userId, householdID string) (*models.PriceAlert, int, error) {
+	reqBody, err := encode.DecodeRequest[models.PriceAlert](r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	if reqBody.UserID != "" && reqBody.UserID != userId {
+		return nil, http.StatusForbidden, fmt.Errorf("given `user_id` %s is different from `user_id` in `access_token`", reqBody.UserID)
+	}
+
+	if err := helpers.ValidatePriceAlert(&reqBody); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	reqBody.UserID = userId
+	reqBody.HouseholdID = householdID
+	return &reqBody, http.StatusOK, nil
+}
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/AnhCaooo/go-goods/encode"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
 	"github.com/AnhCaooo/stormbreaker/internal/cache"
 	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/events"
 	"github.com/AnhCaooo/stormbreaker/internal/helpers"
 	"github.com/AnhCaooo/stormbreaker/internal/models"
 	"go.uber.org/zap"
@@ -17,43 +19,106 @@ import (
 //
 //	@Summary		Retrieves the price settings for specific user
 //	@Description	retrieves the price settings for specific user by identify through 'access token'.
+//	@Description	Household which has no price settings yet gets the default price settings from configuration.
 //	@Tags			price-settings
 //	@Accept			json
 //	@Produce		json
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
 //	@Success		200	{object}	models.PriceSettings
-//	@Failure		400	{object}	string "Invalid request"
-//	@Failure		401	{object}	string "Unauthenticated/Unauthorized"
-//	@Failure		500	{object}	string "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
+//	@Header			200	{string}	ETag	"version of the settings, to send back through `If-Match` when changing them. Missing for default price settings"
+//	@Failure		400	{object}	problem.Details "Invalid request"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500	{object}	problem.Details "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
 //	@Router			/v1/price-settings [get]
 func (h Handler) GetPriceSettings(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
 	userId, ok := r.Context().Value(constants.UserIdKey).(string)
 	if !ok {
-		http.Error(w, "User ID not found in context", http.StatusUnauthorized)
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
 		return
 	}
-	settings, statusCode, err := h.LoadPriceSettings(userId)
+	household, statusCode, err := h.householdFromRequest(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	settings, statusCode, err := h.LoadPriceSettings(household)
 	if err != nil {
 		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
-		http.Error(w, err.Error(), statusCode)
+		writeProblem(w, r, statusCode, err)
 		return
 	}
 
+	// default price settings are not stored, so they have no version to change
+	if settings.Version > 0 {
+		w.Header().Set("ETag", helpers.VersionETag(settings.Version))
+	}
 	if err := encode.EncodeResponse(w, statusCode, settings); err != nil {
 		h.logger.Error(
 			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
 			zap.Error(err),
 		)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 }
 
-// LoadPriceSettings retrieves the price settings for a given user ID.
+// GetPriceSettingsHistory lists all changes of the price settings for specific user
+//
+//	@Summary		Lists the changes of price settings for specific user
+//	@Description	Lists every change of the price settings for specific user by identify through 'access token',
+//	@Description	ordered by the time from which it is in force.
+//	@Tags			price-settings
+//	@Accept			json
+//	@Produce		json
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
+//	@Success		200	{array}		models.PriceSettingsHistory
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		500	{object}	problem.Details "Various reasons: failed to read settings history from db, etc."
+//	@Router			/v1/price-settings/history [get]
+func (h Handler) GetPriceSettingsHistory(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userId, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.householdFromRequest(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	history, statusCode, err := h.mongo.GetPriceSettingsHistory(household.ID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if err := encode.EncodeResponse(w, statusCode, history); err != nil {
+		h.logger.Error(
+			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
+			zap.Error(err),
+		)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// LoadPriceSettings retrieves the price settings for a given household.
 // It first checks if the settings are available in the cache. If found, it returns the cached settings.
 // If not found in the cache, it fetches the settings from the MongoDB database, caches them for 24 hours, and then returns them.
-func (h Handler) LoadPriceSettings(userID string) (settings *models.PriceSettings, statusCode int, err error) {
-	cacheKey := fmt.Sprintf("%s_%s", userID, cache.UserPriceSettingsKey)
+// A household which has no price settings yet (ex: `user.create` message was lost or has not arrived yet, or settings were deleted)
+// gets the default price settings from configuration. The defaults are neither stored nor cached, so that reading
+// does not bring back settings which were deleted.
+func (h Handler) LoadPriceSettings(household *models.Household) (settings *models.PriceSettings, statusCode int, err error) {
+	cacheKey := fmt.Sprintf("%s_%s", household.ID, cache.UserPriceSettingsKey)
 	settingsInCache, exists := h.cache.Get(cacheKey)
 	if exists {
 		settings, err := helpers.MapInterfaceToStruct[models.PriceSettings](settingsInCache)
@@ -64,14 +129,32 @@ func (h Handler) LoadPriceSettings(userID string) (settings *models.PriceSetting
 		return settings, http.StatusOK, nil
 	}
 
-	settings, statusCode, err = h.mongo.GetPriceSettings(userID)
+	settings, statusCode, err = h.mongo.GetPriceSettings(household.ID)
+	if statusCode == http.StatusNotFound {
+		h.logger.Debug(fmt.Sprintf("[worker_%d] %s [db] household has no price settings, use default price settings", h.workerID, constants.Server), zap.String("household_id", household.ID))
+		defaults := h.config.DefaultPriceSettings.For(household.UserID, household.ID)
+		return &defaults, http.StatusOK, nil
+	}
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
 	h.logger.Debug(fmt.Sprintf("[worker_%d] %s [db] load price settings successfully", h.workerID, constants.Server))
 	// cache price settings and keep for 24 hours
 	h.cache.SetExpiredAfterTimePeriod(cacheKey, &settings, time.Hour*time.Duration(24))
-	return settings, statusCode, nil
+	return settings, http.StatusOK, nil
+}
+
+// clearHouseholdCache removes the cached price settings and prices of household after its price settings changed
+func (h Handler) clearHouseholdCache(householdID string) {
+	h.cache.Delete(fmt.Sprintf("%s_%s", householdID, cache.UserPriceSettingsKey))
+	h.cache.Delete(fmt.Sprintf("%s_%s", householdID, cache.UserTodayTomorrowPricesKey))
+}
+
+// settingsChanged removes the cached price settings and prices of household and notifies open event streams
+// of household that its price settings changed
+func (h Handler) settingsChanged(householdID string) {
+	h.clearHouseholdCache(householdID)
+	h.events.Publish(events.Event{Name: events.SETTINGS_CHANGED_EVENT, HouseholdID: householdID})
 }
 
 // CreatePriceSettings creates a new price settings for user
@@ -81,44 +164,55 @@ func (h Handler) LoadPriceSettings(userID string) (settings *models.PriceSetting
 //	@Tags			price-settings
 //	@Accept			json
 //	@Produce		json
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
 //	@Param			payload	body		models.PriceSettings	true	"user price settings"
 //	@Success		200	{object}	string
-//	@Failure		400	{object}	string "Invalid request"
-//	@Failure		401	{object}	string "Unauthenticated/Unauthorized"
-//	@Failure		403	{object}	string "Forbidden"
-//	@Failure		404	{object}	string "Settings not found"
-//	@Failure		409	{object}	string "Settings exist already"
-//	@Failure		500	{object}	string "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
+//	@Failure		400	{object}	problem.Details "Invalid request"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403	{object}	problem.Details "Forbidden"
+//	@Failure		404	{object}	problem.Details "Settings not found"
+//	@Failure		409	{object}	problem.Details "Settings exist already"
+//	@Failure		500	{object}	problem.Details "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
 //	@Router			/v1/price-settings [post]
 func (h Handler) CreatePriceSettings(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
 	userId, ok := r.Context().Value(constants.UserIdKey).(string)
 	if !ok {
-		http.Error(w, "User ID not found in context", http.StatusUnauthorized)
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.editableHouseholdFromRequest(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
 		return
 	}
 
 	reqBody, err := encode.DecodeRequest[models.PriceSettings](r)
 	if err != nil {
 		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	if reqBody.UserID != "" && reqBody.UserID != userId {
 		err = fmt.Errorf("given `user_id` %s is different from `user_id` in `access_token`", reqBody.UserID)
 		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
-		http.Error(w, err.Error(), http.StatusForbidden)
+		problem.Write(w, r, http.StatusForbidden, err.Error())
 		return
 	}
 
-	// Patch userID from accessToken to price settings struct
-	reqBody.UserID = userId
-	statusCode, err := h.mongo.InsertPriceSettings(reqBody)
+	// Patch owner and selected household to price settings struct. Settings of shared household belong to its owner.
+	reqBody.UserID = household.UserID
+	reqBody.HouseholdID = household.ID
+	statusCode, err = h.mongo.InsertPriceSettings(reqBody)
 	if err != nil {
 		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
-		http.Error(w, err.Error(), statusCode)
+		writeProblem(w, r, statusCode, err)
 		return
 	}
+	h.settingsChanged(household.ID)
 
 	response := map[string]string{
 		"message": "Operation completed successfully",
@@ -128,7 +222,7 @@ func (h Handler) CreatePriceSettings(w http.ResponseWriter, r *http.Request) {
 			fmt.Sprintf("[worker_%d] %s failed to encode response body", h.workerID, constants.Server),
 			zap.Error(err),
 		)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 }
@@ -137,89 +231,141 @@ func (h Handler) CreatePriceSettings(w http.ResponseWriter, r *http.Request) {
 //
 //	@Summary		Updates the price settings for specific user
 //	@Description	Updates the price settings for specific user by identify through 'access token'.
+//	@Description	The request body is a JSON Merge Patch (RFC 7396): only the fields which are present are changed.
+//	@Description	Fields cannot be removed with `null`, send a value instead (ex: `"monthly_budget": 0` removes the budget).
+//	@Description	Send the `ETag` of the settings which were read through `If-Match`, so that changes from other devices are not overwritten.
+//	@Description	Settings which were stored before they had versions have no `ETag`, send `*` for them.
 //	@Tags			price-settings
-//	@Accept			json
+//	@Accept			application/merge-patch+json
 //	@Produce		json
-//	@Param			payload	body		models.PriceSettings	true	"user price settings"
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
+//	@Param			If-Match		header		string	true	"`ETag` of the settings which client changes. Ex: \"3\""
+//	@Param			payload	body		models.PriceSettingsPatch	true	"fields of price settings to change"
 //	@Success		200	{object}	string
-//	@Failure		400	{object}	string "Invalid request"
-//	@Failure		401	{object}	string "Unauthenticated/Unauthorized"
-//	@Failure		403	{object}	string "Forbidden"
-//	@Failure		404	{object}	string "Settings not found"
-//	@Failure		500	{object}	string "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
+//	@Header			200	{string}	ETag	"version of the changed settings"
+//	@Failure		400	{object}	problem.Details "Invalid request"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403	{object}	problem.Details "Forbidden"
+//	@Failure		404	{object}	problem.Details "Settings not found"
+//	@Failure		412	{object}	problem.Details "Settings were changed by another request after the version in `If-Match`"
+//	@Failure		415	{object}	problem.Details "Request body is not `application/merge-patch+json`"
+//	@Failure		428	{object}	problem.Details "Request has no `If-Match` header"
+//	@Failure		500	{object}	problem.Details "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
 //	@Router			/v1/price-settings [patch]
 func (h Handler) PatchPriceSettings(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
 	userId, ok := r.Context().Value(constants.UserIdKey).(string)
 	if !ok {
-		http.Error(w, "User ID not found in context", http.StatusUnauthorized)
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
 		return
 	}
 
-	reqBody, err := encode.DecodeRequest[models.PriceSettings](r)
+	household, statusCode, err := h.editableHouseholdFromRequest(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	if !helpers.IsMergePatch(r.Header.Get("Content-Type")) {
+		err = fmt.Errorf("request body should be `%s`", helpers.MERGE_PATCH_CONTENT_TYPE)
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		err = fmt.Errorf("`If-Match` header is required. Send the `ETag` of the settings which were read, or `*`")
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusPreconditionRequired, err.Error())
+		return
+	}
+	expectedVersion, err := helpers.ParseIfMatch(ifMatch)
 	if err != nil {
 		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reqBody, err := encode.DecodeRequest[models.PriceSettingsPatch](r)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	if reqBody.UserID != "" && reqBody.UserID != userId {
 		err = fmt.Errorf("given `user_id` %s is different from `user_id` in `access_token`", reqBody.UserID)
 		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
-		http.Error(w, err.Error(), http.StatusForbidden)
+		problem.Write(w, r, http.StatusForbidden, err.Error())
+		return
+	}
+	if err = helpers.ValidatePriceSettingsPatch(reqBody); err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		problem.Write(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Patch userID from accessToken to price settings struct
-	reqBody.UserID = userId
-	statusCode, err := h.mongo.PatchPriceSettings(reqBody)
+	// Settings of shared household belong to its owner.
+	settings, statusCode, err := h.mongo.PatchPriceSettings(household.UserID, household.ID, reqBody, expectedVersion)
 	if err != nil {
 		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
-		http.Error(w, err.Error(), statusCode)
+		writeProblem(w, r, statusCode, err)
 		return
 	}
+	h.settingsChanged(household.ID)
 
 	response := map[string]string{
 		"message": "Operation completed successfully",
 	}
+	w.Header().Set("ETag", helpers.VersionETag(settings.Version))
 
 	if err := encode.EncodeResponse(w, statusCode, response); err != nil {
 		h.logger.Error(
 			fmt.Sprintf("[worker_%d] %s failed to encode response body:", h.workerID, constants.Server),
 			zap.Error(err),
 		)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
-
-	h.cache.Delete(fmt.Sprintf("%s_%s", userId, cache.UserPriceSettingsKey))
-	h.cache.Delete(fmt.Sprintf("%s_%s", userId, cache.UserTodayTomorrowPricesKey))
 }
 
-// todo: maybe only Admin can perform this action? (to be considered)
-// DeletePriceSettings deletes the price settings when user was deleted or removed
+// DeletePriceSettings deletes the price settings when user was deleted or removed.
+// Admins delete the settings of any household through ForceDeletePriceSettings.
 //
 //	@Summary		Deletes the price settings for specific user
 //	@Description	Deletes the price settings for specific user by identify through 'access token'.
 //	@Tags			price-settings
 //	@Accept			json
 //	@Produce		json
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
 //	@Success		200	{object}	string
-//	@Failure		400	{object}	string "Invalid request"
-//	@Failure		401	{object}	string "Unauthenticated/Unauthorized"
-//	@Failure		404	{object}	string "Settings not found"
-//	@Failure		500	{object}	string "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
+//	@Failure		400	{object}	problem.Details "Invalid request"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		403	{object}	problem.Details "Forbidden"
+//	@Failure		404	{object}	problem.Details "Settings not found"
+//	@Failure		500	{object}	problem.Details "Various reasons: cannot fetch price from 3rd party, failed to read settings from db, etc."
 //	@Router			/v1/price-settings [delete]
 func (h Handler) DeletePriceSettings(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
 	userId, ok := r.Context().Value(constants.UserIdKey).(string)
 	if !ok {
-		http.Error(w, "User ID not found in context", http.StatusUnauthorized)
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
 		return
 	}
 
-	statusCode, err := h.mongo.DeletePriceSettings(userId)
+	household, statusCode, err := h.editableHouseholdFromRequest(r, userId)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	statusCode, err = h.mongo.DeletePriceSettings(household.ID)
 	if err != nil {
 		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
-		http.Error(w, err.Error(), statusCode)
+		writeProblem(w, r, statusCode, err)
 		return
 	}
 
@@ -232,10 +378,8 @@ func (h Handler) DeletePriceSettings(w http.ResponseWriter, r *http.Request) {
 			fmt.Sprintf("[worker_%d] %s failed to encode response body:", h.workerID, constants.Server),
 			zap.Error(err),
 		)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
-	h.cache.Delete(fmt.Sprintf("%s_%s", userId, cache.UserPriceSettingsKey))
-	h.cache.Delete(fmt.Sprintf("%s_%s", userId, cache.UserTodayTomorrowPricesKey))
-
+	h.settingsChanged(household.ID)
 }
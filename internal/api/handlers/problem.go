@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/db"
+)
+
+// problemCodes maps the failures of db operations to their error codes
+var problemCodes = []struct {
+	err  error
+	code string
+}{
+	{db.ErrHouseholdNotFound, problem.HOUSEHOLD_NOT_FOUND},
+	{db.ErrDefaultHouseholdChanged, problem.DEFAULT_HOUSEHOLD_READ_ONLY},
+	{db.ErrDefaultHouseholdDeleted, problem.DEFAULT_HOUSEHOLD_READ_ONLY},
+	{db.ErrHouseholdMemberNotFound, problem.HOUSEHOLD_MEMBER_NOT_FOUND},
+	{db.ErrHouseholdMemberInvited, problem.HOUSEHOLD_MEMBER_INVITED},
+	{db.ErrHouseholdInvitationNotFound, problem.HOUSEHOLD_INVITATION_NOT_FOUND},
+	{db.ErrPriceSettingsNotFound, problem.PRICE_SETTINGS_NOT_FOUND},
+	{db.ErrPriceSettingsExist, problem.PRICE_SETTINGS_EXIST},
+	{db.ErrPriceSettingsUnchanged, problem.PRICE_SETTINGS_UNCHANGED},
+	{db.ErrPriceAlertNotFound, problem.PRICE_ALERT_NOT_FOUND},
+	{db.ErrInvalidPriceAlertID, problem.INVALID_PRICE_ALERT_ID},
+	{db.ErrCalendarFeedNotFound, problem.CALENDAR_FEED_NOT_FOUND},
+	{db.ErrNotificationPreferencesNotFound, problem.NOTIFICATION_PREFERENCES_NOT_FOUND},
+}
+
+// writeProblem writes the problem of failed operation with the error code of its failure
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, err error) {
+	problem.WriteCode(w, r, status, problemCode(status, err), err.Error())
+}
+
+// problemCode returns the error code of failure. Failures which are not known get the default code of HTTP status.
+func problemCode(status int, err error) string {
+	for _, known := range problemCodes {
+		if errors.Is(err, known.err) {
+			return known.code
+		}
+	}
+	return problem.CodeOf(status)
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	stormbreakerv1 "github.com/AnhCaooo/stormbreaker/proto/stormbreaker/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// RPCServer serves the gRPC API (stormbreaker.v1.Stormbreaker) with the same logic as the REST handlers.
+// The id of user is added to the context of call by the authentication interceptors of middleware.
+type RPCServer struct {
+	stormbreakerv1.UnimplementedStormbreakerServer
+	handler Handler
+}
+
+// NewRPCServer returns a new RPCServer instance which shares the dependencies of handler
+func NewRPCServer(handler *Handler) *RPCServer {
+	return &RPCServer{handler: *handler}
+}
+
+// GetTodayTomorrow returns the prices of today and tomorrow with price settings of household applied
+func (s *RPCServer) GetTodayTomorrow(ctx context.Context, req *stormbreakerv1.GetTodayTomorrowRequest) (*stormbreakerv1.TodayTomorrowPrice, error) {
+	s = s.withContext(ctx)
+	household, err := s.household(ctx, req.GetHouseholdId(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, _, err := s.handler.loadTodayTomorrowPrices(household)
+	if err != nil {
+		return nil, s.rpcError(http.StatusInternalServerError, err)
+	}
+	return toRPCTodayTomorrowPrice(prices), nil
+}
+
+// GetMarketPrice returns the prices in any time range. The price settings which were in force at each slot are applied.
+func (s *RPCServer) GetMarketPrice(ctx context.Context, req *stormbreakerv1.GetMarketPriceRequest) (*stormbreakerv1.MarketPrice, error) {
+	s = s.withContext(ctx)
+	household, err := s.household(ctx, req.GetHouseholdId(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	priceRequest := fromRPCMarketPriceRequest(req)
+	prices, statusCode, err := s.handler.loadMarketPrices(household, &priceRequest)
+	if err != nil {
+		return nil, s.rpcError(statusCode, err)
+	}
+	return toRPCMarketPrice(prices), nil
+}
+
+// GetPriceSettings returns the price settings of household
+func (s *RPCServer) GetPriceSettings(ctx context.Context, req *stormbreakerv1.GetPriceSettingsRequest) (*stormbreakerv1.PriceSettings, error) {
+	s = s.withContext(ctx)
+	household, err := s.household(ctx, req.GetHouseholdId(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, statusCode, err := s.handler.LoadPriceSettings(household)
+	if err != nil {
+		return nil, s.rpcError(statusCode, err)
+	}
+	return toRPCPriceSettings(settings), nil
+}
+
+// UpdatePriceSettings changes only the fields which are present in request, like `PATCH /v1/price-settings`
+func (s *RPCServer) UpdatePriceSettings(ctx context.Context, req *stormbreakerv1.UpdatePriceSettingsRequest) (*stormbreakerv1.PriceSettings, error) {
+	s = s.withContext(ctx)
+	household, err := s.household(ctx, req.GetHouseholdId(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	// like `If-Match` of `PATCH /v1/price-settings`, the version is required so that changes from other clients are not overwritten
+	if req.ExpectedVersion == nil {
+		return nil, s.rpcError(http.StatusPreconditionRequired, fmt.Errorf("`expected_version` is required. Send the version of the settings which were read"))
+	}
+	patch := fromRPCPriceSettingsPatch(req)
+	if err := helpers.ValidatePriceSettingsPatch(patch); err != nil {
+		return nil, s.rpcError(http.StatusBadRequest, err)
+	}
+
+	// Settings of shared household belong to its owner.
+	settings, statusCode, err := s.handler.mongo.PatchPriceSettings(household.UserID, household.ID, patch, req.ExpectedVersion)
+	if err != nil {
+		return nil, s.rpcError(statusCode, err)
+	}
+	s.handler.settingsChanged(household.ID)
+	return toRPCPriceSettings(settings), nil
+}
+
+// DeletePriceSettings deletes the price settings of household
+func (s *RPCServer) DeletePriceSettings(ctx context.Context, req *stormbreakerv1.DeletePriceSettingsRequest) (*emptypb.Empty, error) {
+	s = s.withContext(ctx)
+	household, err := s.household(ctx, req.GetHouseholdId(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	statusCode, err := s.handler.mongo.DeletePriceSettings(household.ID)
+	if err != nil {
+		return nil, s.rpcError(statusCode, err)
+	}
+	s.handler.settingsChanged(household.ID)
+	return &emptypb.Empty{}, nil
+}
+
+// SubscribePrices streams the same events as `GET /v1/market-price/stream`
+func (s *RPCServer) SubscribePrices(req *stormbreakerv1.SubscribePricesRequest, server stormbreakerv1.Stormbreaker_SubscribePricesServer) error {
+	s = s.withContext(server.Context())
+	h := s.handler
+	household, err := s.household(server.Context(), req.GetHouseholdId(), false)
+	if err != nil {
+		return err
+	}
+
+	prices, _, err := h.loadTodayTomorrowPrices(household)
+	if err != nil {
+		return s.rpcError(http.StatusInternalServerError, err)
+	}
+
+	subscription, unsubscribe := h.events.Subscribe(household.ID)
+	defer unsubscribe()
+	h.logger.Info(fmt.Sprintf("[worker_%d] price subscription opened", h.workerID), zap.String("household_id", household.ID))
+
+	send := func(name string, data interface{}) error {
+		event, err := toRPCPriceEvent(name, data)
+		if err != nil {
+			return err
+		}
+		return server.Send(event)
+	}
+	stream := &priceStream{handler: h, household: household, prices: prices, send: send}
+	err = stream.run(server.Context(), subscription, nil)
+	stream.logClosed(err)
+	if err != nil {
+		return s.rpcError(http.StatusInternalServerError, err)
+	}
+	return nil
+}
+
+// withContext returns the server whose logs carry the id of request in context
+func (s *RPCServer) withContext(ctx context.Context) *RPCServer {
+	return &RPCServer{handler: s.handler.withContext(ctx)}
+}
+
+// household returns the household of user which the call selects. `editable` requires the role which can change it.
+func (s *RPCServer) household(ctx context.Context, householdID string, editable bool) (*models.Household, error) {
+	userID, ok := ctx.Value(constants.UserIdKey).(string)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "User ID not found in context")
+	}
+
+	selectHousehold := s.handler.accessibleHousehold
+	if editable {
+		selectHousehold = s.handler.editableHousehold
+	}
+	household, statusCode, err := selectHousehold(userID, householdID)
+	if err != nil {
+		return nil, s.rpcError(statusCode, err)
+	}
+	return household, nil
+}
+
+// rpcError logs the error and converts it with its HTTP status code to gRPC status.
+// Like problem details, the message of server errors is not returned to client.
+func (s *RPCServer) rpcError(statusCode int, err error) error {
+	h := s.handler
+	code := rpcCode(statusCode)
+	if statusCode >= http.StatusInternalServerError || statusCode == 0 {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		return status.Error(code, problem.INTERNAL_ERROR_MESSAGE)
+	}
+	h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+	return status.Error(code, err.Error())
+}
+
+// rpcCode returns the gRPC status code which matches HTTP status code
+func rpcCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed, http.StatusPreconditionRequired:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	}
+	return codes.Internal
+}
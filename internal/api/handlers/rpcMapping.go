@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/AnhCaooo/stormbreaker/internal/events"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	stormbreakerv1 "github.com/AnhCaooo/stormbreaker/proto/stormbreaker/v1"
+)
+
+// toRPCPriceSeries converts the price series to its gRPC message
+func toRPCPriceSeries(series models.PriceSeries) *stormbreakerv1.PriceSeries {
+	slots := make([]*stormbreakerv1.PriceSlot, 0, len(series.Data))
+	for _, data := range series.Data {
+		slots = append(slots, &stormbreakerv1.PriceSlot{
+			Time:       data.Time,
+			TimeUtc:    data.TimeUTC,
+			Price:      data.Price,
+			VatFactor:  data.VatFactor,
+			IsToday:    data.IsToday,
+			IncludeVat: data.IncludeVat == "1",
+		})
+	}
+	return &stormbreakerv1.PriceSeries{Unit: series.Name, Slots: slots}
+}
+
+// toRPCDailyPrice converts the prices of a day to its gRPC message
+func toRPCDailyPrice(daily models.DailyPrice) *stormbreakerv1.DailyPrice {
+	return &stormbreakerv1.DailyPrice{Available: daily.Available, Prices: toRPCPriceSeries(daily.Prices)}
+}
+
+// toRPCTodayTomorrowPrice converts today's and tomorrow's prices to their gRPC message
+func toRPCTodayTomorrowPrice(prices *models.TodayTomorrowPrice) *stormbreakerv1.TodayTomorrowPrice {
+	return &stormbreakerv1.TodayTomorrowPrice{
+		Today:    toRPCDailyPrice(prices.Today),
+		Tomorrow: toRPCDailyPrice(prices.Tomorrow),
+	}
+}
+
+// toRPCMarketPrice converts the market prices from external source to their gRPC message
+func toRPCMarketPrice(prices *models.PriceResponse) *stormbreakerv1.MarketPrice {
+	series := make([]*stormbreakerv1.PriceSeries, 0, len(prices.Data.Series))
+	for _, s := range prices.Data.Series {
+		series = append(series, toRPCPriceSeries(s))
+	}
+	return &stormbreakerv1.MarketPrice{Group: prices.Data.Group, Series: series}
+}
+
+// toRPCPriceSettings converts the price settings to their gRPC message
+func toRPCPriceSettings(settings *models.PriceSettings) *stormbreakerv1.PriceSettings {
+	return &stormbreakerv1.PriceSettings{
+		UserId:        settings.UserID,
+		HouseholdId:   settings.HouseholdID,
+		VatIncluded:   settings.VatIncluded,
+		Margin:        settings.Marginal,
+		MonthlyBudget: settings.MonthlyBudget,
+		Version:       settings.Version,
+	}
+}
+
+// toRPCPriceEvent converts the event of price stream to its gRPC message
+func toRPCPriceEvent(name string, data interface{}) (*stormbreakerv1.PriceEvent, error) {
+	switch value := data.(type) {
+	case *models.CurrentPrice:
+		return &stormbreakerv1.PriceEvent{Event: &stormbreakerv1.PriceEvent_Price{Price: &stormbreakerv1.CurrentPrice{
+			Time:    value.Time,
+			TimeUtc: value.TimeUTC,
+			Price:   value.Price,
+			Unit:    value.Unit,
+		}}}, nil
+	case models.DailyPrice:
+		if name == events.TOMORROW_AVAILABLE_EVENT {
+			return &stormbreakerv1.PriceEvent{Event: &stormbreakerv1.PriceEvent_TomorrowAvailable{TomorrowAvailable: toRPCDailyPrice(value)}}, nil
+		}
+	case *models.PriceSettings:
+		return &stormbreakerv1.PriceEvent{Event: &stormbreakerv1.PriceEvent_SettingsChanged{SettingsChanged: toRPCPriceSettings(value)}}, nil
+	}
+	return nil, fmt.Errorf("unsupported %s event: %T", name, data)
+}
+
+// fromRPCMarketPriceRequest converts the gRPC request of market prices to the request of external source
+func fromRPCMarketPriceRequest(req *stormbreakerv1.GetMarketPriceRequest) models.PriceRequest {
+	request := models.PriceRequest{
+		StartDate: req.GetStartDate(),
+		EndDate:   req.GetEndDate(),
+		Group:     req.GetGroup(),
+	}
+	if req.GetCompareToLastYear() {
+		request.CompareToLastYear = 1
+	}
+	return request
+}
+
+// fromRPCPriceSettingsPatch converts the gRPC request of changing price settings to merge patch
+func fromRPCPriceSettingsPatch(req *stormbreakerv1.UpdatePriceSettingsRequest) models.PriceSettingsPatch {
+	return models.PriceSettingsPatch{
+		VatIncluded:   req.VatIncluded,
+		Marginal:      req.Margin,
+		MonthlyBudget: req.MonthlyBudget,
+	}
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/events"
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.uber.org/zap"
+)
+
+const (
+	// EVENT_STREAM_CONTENT_TYPE is the media type of Server-Sent Events
+	EVENT_STREAM_CONTENT_TYPE string = "text/event-stream"
+	// PRICE_EVENT is pushed with the current price when a new price slot begins
+	PRICE_EVENT string = "price"
+	// STREAM_HEARTBEAT_INTERVAL keeps proxies from closing an idle stream
+	STREAM_HEARTBEAT_INTERVAL time.Duration = 30 * time.Second
+)
+
+// StreamPrices keeps a Server-Sent Events stream open and pushes price updates of household to client
+//
+//	@Summary		Streams live price updates
+//	@Description	Keeps a Server-Sent Events stream open and pushes these events of household, with its price settings applied:
+//	@Description	`price` with the current price when the stream opens and whenever a new price slot (hour or quarter) begins,
+//	@Description	`tomorrow_available` with tomorrow's prices once they are published,
+//	@Description	`settings_changed` with the new price settings after they were changed (followed by `price` with the current price).
+//	@Description	The data of every event is JSON. Comment lines are sent regularly to keep the connection open.
+//	@Tags			market-price
+//	@Produce		text/event-stream
+//	@Param			household_id	query		string	false	"id of the household. The default household of user when empty"
+//	@Success		200	{object}	models.CurrentPrice	"stream of events"
+//	@Failure		401	{object}	problem.Details "Unauthenticated/Unauthorized"
+//	@Failure		404	{object}	problem.Details "Household not found"
+//	@Failure		500	{object}	problem.Details "Various reasons: cannot fetch price from 3rd party, streaming is not supported, etc."
+//	@Router			/v1/market-price/stream [get]
+func (h Handler) StreamPrices(w http.ResponseWriter, r *http.Request) {
+	h = h.withContext(r.Context())
+	userID, ok := r.Context().Value(constants.UserIdKey).(string)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, "User ID not found in context")
+		return
+	}
+
+	household, statusCode, err := h.householdFromRequest(r, userID)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Client), zap.Error(err))
+		writeProblem(w, r, statusCode, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		err = fmt.Errorf("streaming is not supported")
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	prices, _, err := h.loadTodayTomorrowPrices(household)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s", h.workerID, constants.Server), zap.Error(err))
+		problem.Write(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	subscription, unsubscribe := h.events.Subscribe(household.ID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", EVENT_STREAM_CONTENT_TYPE)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// nginx buffers responses by default, which would hold the events back
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	h.logger.Info(fmt.Sprintf("[worker_%d] price stream opened", h.workerID), zap.String("household_id", household.ID))
+
+	sse := &eventStreamWriter{writer: w, flusher: flusher}
+	stream := &priceStream{handler: h, household: household, prices: prices, send: sse.send}
+	stream.logClosed(stream.run(r.Context(), subscription, sse.heartbeat))
+}
+
+// eventStreamWriter writes events to client in Server-Sent Events format
+type eventStreamWriter struct {
+	writer  http.ResponseWriter
+	flusher http.Flusher
+}
+
+// send writes the event with JSON data to client
+func (s *eventStreamWriter) send(name string, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %s", name, err.Error())
+	}
+	return s.write(fmt.Sprintf("event: %s\ndata: %s\n\n", name, body))
+}
+
+// heartbeat writes a comment line which clients ignore, so that proxies keep the connection open
+func (s *eventStreamWriter) heartbeat() error {
+	return s.write(": keep-alive\n\n")
+}
+
+// write writes raw lines of stream to client and flushes them immediately
+func (s *eventStreamWriter) write(lines string) error {
+	if _, err := fmt.Fprint(s.writer, lines); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// priceStream is the state of one open price stream. The events are sent through `send`,
+// so that the same stream serves both Server-Sent Events and gRPC clients.
+type priceStream struct {
+	handler   Handler
+	household *models.Household
+	prices    *models.TodayTomorrowPrice
+	lastSlot  string // start (UTC) of the slot whose price was pushed last
+	send      func(name string, data interface{}) error
+}
+
+// run pushes the current price, then the events of household until client leaves or server shuts down.
+// `heartbeat` is called regularly when it is given.
+func (s *priceStream) run(ctx context.Context, subscription <-chan events.Event, heartbeat func() error) error {
+	if err := s.pushCurrentPrice(); err != nil {
+		return err
+	}
+
+	slotTimer := time.NewTimer(time.Until(helpers.NextQuarter(time.Now())))
+	defer slotTimer.Stop()
+	heartbeatTicker := time.NewTicker(STREAM_HEARTBEAT_INTERVAL)
+	defer heartbeatTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-subscription:
+			if !ok {
+				// server is shutting down
+				return nil
+			}
+			if err := s.push(event); err != nil {
+				return err
+			}
+
+		case <-slotTimer.C:
+			slotTimer.Reset(time.Until(helpers.NextQuarter(time.Now())))
+			// cached prices expire at 23:59, so the prices of new day are loaded here
+			if err := s.reloadPrices(); err != nil {
+				return err
+			}
+			if err := s.pushCurrentPrice(); err != nil {
+				return err
+			}
+
+		case <-heartbeatTicker.C:
+			if heartbeat == nil {
+				continue
+			}
+			if err := heartbeat(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// push pushes the event from events hub together with its data for household
+func (s *priceStream) push(event events.Event) error {
+	switch event.Name {
+	case events.TOMORROW_AVAILABLE_EVENT:
+		if err := s.reloadPrices(); err != nil {
+			return err
+		}
+		return s.send(events.TOMORROW_AVAILABLE_EVENT, s.prices.Tomorrow)
+
+	case events.SETTINGS_CHANGED_EVENT:
+		settings, _, err := s.handler.LoadPriceSettings(s.household)
+		if err != nil {
+			return err
+		}
+		if err := s.send(events.SETTINGS_CHANGED_EVENT, settings); err != nil {
+			return err
+		}
+		if err := s.reloadPrices(); err != nil {
+			return err
+		}
+		// the price of the current slot changed together with the settings
+		s.lastSlot = ""
+		return s.pushCurrentPrice()
+	}
+	return nil
+}
+
+// pushCurrentPrice pushes the price of current slot, unless it was pushed already
+func (s *priceStream) pushCurrentPrice() error {
+	current, err := helpers.CurrentPrice(s.prices, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if current == nil || current.TimeUTC == s.lastSlot {
+		return nil
+	}
+	if err := s.send(PRICE_EVENT, current); err != nil {
+		return err
+	}
+	s.lastSlot = current.TimeUTC
+	return nil
+}
+
+// reloadPrices loads the prices of household again, mostly from cache
+func (s *priceStream) reloadPrices() error {
+	prices, _, err := s.handler.loadTodayTomorrowPrices(s.household)
+	if err != nil {
+		return err
+	}
+	s.prices = prices
+	return nil
+}
+
+// logClosed logs why the stream was closed
+func (s *priceStream) logClosed(err error) {
+	h := s.handler
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("[worker_%d] %s price stream failed", h.workerID, constants.Server), zap.Error(err))
+		return
+	}
+	h.logger.Info(fmt.Sprintf("[worker_%d] price stream closed", h.workerID), zap.String("household_id", s.household.ID))
+}
@@ -0,0 +1,63 @@
+// AnhCao 2024
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.uber.org/zap"
+)
+
+// Authorize lets only the users who have the admin role (from the role claim of access token) use the routes which it wraps,
+// the others get `403`. Every admin action is logged with the admin and the status code of response.
+// It is applied to the subrouter of admin API and has to run after Authenticate, which adds the id and roles of user.
+func (m *Middleware) Authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := r.Context().Value(constants.UserIdKey).(string)
+		requestID, _ := r.Context().Value(constants.RequestIdKey).(string)
+		roles, _ := r.Context().Value(constants.RolesKey).([]string)
+		if !slices.Contains(roles, m.adminRole()) {
+			m.logger.Warn(
+				fmt.Sprintf("[worker_%d] %s user is not allowed to use admin API", m.workerID, constants.Client),
+				zap.String("user_id", userID),
+				zap.String("method", r.Method),
+				zap.String("endpoint", r.URL.Path),
+				zap.String(constants.RequestIdField, requestID),
+			)
+			problem.Write(w, r, http.StatusForbidden, "Only admins may use this endpoint.")
+			return
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		m.logger.Info(
+			fmt.Sprintf("[worker_%d] admin action", m.workerID),
+			zap.String("admin_id", userID),
+			zap.String("method", r.Method),
+			zap.String("endpoint", r.URL.Path),
+			zap.String("query", r.URL.RawQuery),
+			zap.Int("status", recorder.statusCode),
+			zap.String(constants.RequestIdField, requestID),
+		)
+	})
+}
+
+// roleClaim returns the claim of access token which carries the roles of user
+func (m *Middleware) roleClaim() string {
+	if m.config.Supabase.Auth.RoleClaim == "" {
+		return models.DEFAULT_ROLE_CLAIM
+	}
+	return m.config.Supabase.Auth.RoleClaim
+}
+
+// adminRole returns the role which may use the admin API
+func (m *Middleware) adminRole() string {
+	if m.config.Supabase.Auth.AdminRole == "" {
+		return models.DEFAULT_ADMIN_ROLE
+	}
+	return m.config.Supabase.Auth.AdminRole
+}
@@ -0,0 +1,46 @@
+// AnhCao 2024
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestAuthorize(t *testing.T) {
+	config := &models.Config{}
+	config.Supabase.Auth.AdminRole = "operator"
+	middleware := NewMiddleware(zap.NewNop(), config, 1)
+	handler := middleware.Authorize(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name           string
+		path           string
+		roles          []string
+		expectedStatus int
+	}{
+		{name: "admin uses admin API", path: "/admin/summary", roles: []string{"support", "operator"}, expectedStatus: http.StatusNoContent},
+		{name: "user without admin role", path: "/admin/summary", roles: []string{"support"}, expectedStatus: http.StatusForbidden},
+		{name: "user without roles", path: "/admin/price-settings", roles: nil, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, test.path, nil)
+			ctx := context.WithValue(request.Context(), constants.UserIdKey, "12345")
+			ctx = context.WithValue(ctx, constants.RolesKey, test.roles)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request.WithContext(ctx))
+			if recorder.Code != test.expectedStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, test.expectedStatus)
+			}
+		})
+	}
+}
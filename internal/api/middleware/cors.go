@@ -0,0 +1,77 @@
+// AnhCao 2024
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+)
+
+var (
+	// defaultCORSMethods are the methods which API serves
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	// defaultCORSHeaders are the request headers which API reads
+	defaultCORSHeaders = []string{"Authorization", "Content-Type", "Accept", "If-Match", "If-None-Match", "If-Modified-Since", REQUEST_ID_HEADER, "traceparent", "tracestate"}
+)
+
+// CORS allows the configured browser applications on other origins to call the API.
+// It answers preflight requests (`OPTIONS` with `Access-Control-Request-Method`) itself, because browsers
+// do not send access token with them. So it has to wrap the whole router: mux does not run middlewares
+// for `OPTIONS` requests of routes which do not serve that method.
+func (m *Middleware) CORS(next http.Handler) http.Handler {
+	config := m.config.CORS
+	if len(config.AllowedOrigins) == 0 {
+		return next
+	}
+	methods := config.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	headers := config.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		isPreflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		// responses differ by origin, so caches must not share them between origins
+		w.Header().Add("Vary", "Origin")
+		if isPreflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if !helpers.IsAllowedOrigin(origin, config.AllowedOrigins) {
+			if isPreflight {
+				// without CORS headers browser does not send the actual request
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// origin is returned instead of "*", which browsers reject together with credentials
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if config.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		if !isPreflight {
+			if len(config.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(config.ExposedHeaders, ", "))
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+		if config.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(config.MaxAge))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,111 @@
+// AnhCao 2024
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/metrics"
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics records the count and latency of requests by route and status code.
+// The route is the path template (ex: /v1/households/{id}), so that ids do not create a time series each.
+func (m *Middleware) Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := routeTemplate(r)
+
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		metrics.ObserveHTTPRequest(route, r.Method, recorder.statusCode, time.Since(start))
+	})
+}
+
+// MetricsToken lets only the scrapers which send the configured token (`Authorization: Bearer <token>`) read the metrics.
+// Every scraper may read them when no token is configured.
+func (m *Middleware) MetricsToken(next http.Handler) http.Handler {
+	token := m.config.Metrics.Token
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" {
+			problem.WriteCode(w, r, http.StatusForbidden, problem.MISSING_ACCESS_TOKEN, "Request has no token in `Authorization` header.")
+			return
+		}
+		given := strings.TrimPrefix(authorization, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			m.logger.Warn(fmt.Sprintf("[worker_%d] unauthorized metrics scrape", m.workerID))
+			problem.WriteCode(w, r, http.StatusUnauthorized, problem.INVALID_ACCESS_TOKEN, "Token is not valid.")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UnaryMetrics records the count and latency of gRPC calls by method and status code
+func (m *Middleware) UnaryMetrics(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	metrics.ObserveGRPCCall(info.FullMethod, status.Code(err).String(), time.Since(start))
+	return resp, err
+}
+
+// StreamMetrics records the count and duration of streaming gRPC calls by method and status code
+func (m *Middleware) StreamMetrics(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	metrics.ObserveGRPCCall(info.FullMethod, status.Code(err).String(), time.Since(start))
+	return err
+}
+
+// routeTemplate returns the path template of matched route (ex: /v1/households/{id}), or the path when no route matched
+func routeTemplate(r *http.Request) string {
+	if current := mux.CurrentRoute(r); current != nil {
+		if template, err := current.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return r.URL.Path
+}
+
+// statusRecorder keeps the status code which handler writes
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	if !s.wroteHeader {
+		s.statusCode = statusCode
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(b)
+}
+
+// Flush lets event streams flush through the recorder
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the original writer
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
@@ -0,0 +1,47 @@
+// AnhCao 2024
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestMetricsToken(t *testing.T) {
+	scrape := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		token          string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "no token is configured", token: "", authorization: "", expectedStatus: http.StatusOK},
+		{name: "scraper sends the token", token: "s3cret", authorization: "Bearer s3cret", expectedStatus: http.StatusOK},
+		{name: "scraper sends other token", token: "s3cret", authorization: "Bearer guess", expectedStatus: http.StatusUnauthorized},
+		{name: "scraper sends no token", token: "s3cret", authorization: "", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &models.Config{}
+			config.Metrics.Token = test.token
+			handler := NewMiddleware(zap.NewNop(), config, 1).MetricsToken(scrape)
+
+			request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if test.authorization != "" {
+				request.Header.Set("Authorization", test.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != test.expectedStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, test.expectedStatus)
+			}
+		})
+	}
+}
@@ -4,33 +4,61 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 
 	"github.com/AnhCaooo/go-goods/auth"
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
 	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
 	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 )
 
 type Middleware struct {
-	logger   *zap.Logger
-	config   *models.Config
-	workerID int
+	logger         *zap.Logger
+	config         *models.Config
+	limits         []routeLimit
+	trustedProxies []*net.IPNet
+	workerID       int
 }
 
 func NewMiddleware(logger *zap.Logger, config *models.Config, workerID int) *Middleware {
 	return &Middleware{
-		logger:   logger,
-		config:   config,
-		workerID: workerID,
+		logger:         logger,
+		config:         config,
+		limits:         newRouteLimits(config.RateLimit),
+		trustedProxies: newTrustedProxies(config.RateLimit.TrustedProxies),
+		workerID:       workerID,
 	}
 }
 
+// RequestID reads the id of request from `X-Request-ID` header, or generates a new one when the header is missing.
+// The id is stored in request context and returned through `X-Request-ID` header, so that errors can be matched with logs.
+func (m *Middleware) RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(REQUEST_ID_HEADER)
+		if !isValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		w.Header().Set(REQUEST_ID_HEADER, requestID)
+		ctx := context.WithValue(r.Context(), constants.RequestIdKey, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // log the coming request to the server
 func (m *Middleware) Logger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		m.logger.Info(fmt.Sprintf("[worker_%d] request received", m.workerID), zap.String("method", r.Method), zap.String("endpoint", r.URL.Path))
+		requestID, _ := r.Context().Value(constants.RequestIdKey).(string)
+		m.logger.Info(
+			fmt.Sprintf("[worker_%d] request received", m.workerID),
+			zap.String("method", r.Method),
+			zap.String("endpoint", r.URL.Path),
+			zap.String(constants.RequestIdField, requestID),
+		)
 		next.ServeHTTP(w, r)
 	})
 }
@@ -38,38 +66,47 @@ func (m *Middleware) Logger(next http.Handler) http.Handler {
 // read the token from request and do verify the access token
 func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.HasPrefix(r.URL.Path, "/swagger/") {
-			next.ServeHTTP(w, r)
-			return
-		}
-		tokenString := r.Header.Get("Authorization")
-		if tokenString == "" {
-			m.logger.Error(fmt.Sprintf("[worker_%d] permission Denied: No authentication provided in header", m.workerID))
-			w.WriteHeader(http.StatusForbidden)
-			w.Write([]byte("403 - Forbidden"))
-			return
-		}
-
-		tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
-		token, err := auth.VerifyToken(tokenString, m.config.Supabase.Auth.JwtSecret)
-		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			m.logger.Error(fmt.Sprintf("[worker_%d] unauthorized request", m.workerID), zap.Error(err))
-			w.Write([]byte("401 - Unauthorized"))
-			return
-		}
-
-		// due to 'Supabase' authentication, it stores userId via "sub" field
-		userID, err := auth.ExtractValueFromTokenClaim(token, "sub")
+		userID, roles, code, err := m.verifyAccessToken(r.Header.Get("Authorization"))
 		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			m.logger.Error(fmt.Sprintf("[worker_%d] unauthorized request", m.workerID), zap.Error(err))
-			w.Write([]byte("401 - Unauthorized"))
+			status := http.StatusUnauthorized
+			if code == problem.MISSING_ACCESS_TOKEN {
+				status = http.StatusForbidden
+			}
+			problem.WriteCode(w, r, status, code, err.Error())
 			return
 		}
 
-		// Add userID to the context
+		// Add userID and roles of user to the context
 		ctx := context.WithValue(r.Context(), constants.UserIdKey, userID)
+		ctx = context.WithValue(ctx, constants.RolesKey, roles)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// verifyAccessToken verifies the access token from value of `Authorization` header (or metadata) and returns the id and roles of user in it.
+// When the token is missing or invalid, the code of problem and an error which is safe to show to client are returned.
+func (m *Middleware) verifyAccessToken(authorization string) (userID string, roles []string, code string, err error) {
+	if authorization == "" {
+		m.logger.Error(fmt.Sprintf("[worker_%d] permission Denied: No authentication provided in header", m.workerID))
+		return "", nil, problem.MISSING_ACCESS_TOKEN, fmt.Errorf("Request has no access token in `Authorization` header.")
+	}
+
+	tokenString := strings.Replace(authorization, "Bearer ", "", 1)
+	token, err := auth.VerifyToken(tokenString, m.config.Supabase.Auth.JwtSecret)
+	if err != nil {
+		m.logger.Error(fmt.Sprintf("[worker_%d] unauthorized request", m.workerID), zap.Error(err))
+		return "", nil, problem.INVALID_ACCESS_TOKEN, fmt.Errorf("Access token is not valid or has expired.")
+	}
+
+	// due to 'Supabase' authentication, it stores userId via "sub" field
+	userID, err = auth.ExtractValueFromTokenClaim(token, "sub")
+	if err != nil {
+		m.logger.Error(fmt.Sprintf("[worker_%d] unauthorized request", m.workerID), zap.Error(err))
+		return "", nil, problem.INVALID_ACCESS_TOKEN, fmt.Errorf("Access token has no user.")
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		roles = helpers.RolesFromClaims(claims, m.roleClaim())
+	}
+	return userID, roles, "", nil
+}
@@ -0,0 +1,172 @@
+// AnhCao 2024
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/api/problem"
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/AnhCaooo/stormbreaker/internal/ratelimit"
+	"go.uber.org/zap"
+)
+
+// routeLimit is the token bucket limiters of a group of routes: one for IP addresses and one for users
+type routeLimit struct {
+	group       models.RateLimitGroup
+	ipLimiter   *ratelimit.Limiter
+	userLimiter *ratelimit.Limiter
+}
+
+// newRouteLimits creates the limiters for every group of routes in configuration
+func newRouteLimits(config models.RateLimit) []routeLimit {
+	limits := make([]routeLimit, 0, len(config.Groups))
+	for _, group := range config.Groups {
+		ipRequestsPerMinute, ipBurst := group.IPLimit()
+		limits = append(limits, routeLimit{
+			group:       group,
+			ipLimiter:   ratelimit.NewLimiter(ipRequestsPerMinute, ipBurst),
+			userLimiter: ratelimit.NewLimiter(group.RequestsPerMinute, group.Burst),
+		})
+	}
+	return limits
+}
+
+// newTrustedProxies parses the addresses and networks of trusted proxies. Invalid ones are rejected when configuration is loaded.
+func newTrustedProxies(proxies []string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			networks = append(networks, network)
+			continue
+		}
+		if ip := net.ParseIP(proxy); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	return networks
+}
+
+// RateLimitIP limits the requests of every IP address with the token bucket of the group which the route belongs to.
+// It runs before Authenticate, so that clients without valid access token cannot make the service verify tokens without limits.
+// The state of bucket is returned through `RateLimit-*` headers, and rejected requests get `429` with `Retry-After`.
+func (m *Middleware) RateLimitIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := m.routeLimit(r.URL.Path)
+		if limit == nil || m.allowRequest(w, r, limit.ipLimiter, limit.group, "ip:"+m.clientIP(r)) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// RateLimit limits the requests of every user with the token bucket of the group which the route belongs to.
+// It has to run after Authenticate, which adds the id of user. Requests without user (ex: calendar feed) are limited by RateLimitIP only.
+// The state of bucket is returned through `RateLimit-*` headers, and rejected requests get `429` with `Retry-After`.
+func (m *Middleware) RateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := m.routeLimit(r.URL.Path)
+		userID, ok := r.Context().Value(constants.UserIdKey).(string)
+		if limit == nil || !ok || m.allowRequest(w, r, limit.userLimiter, limit.group, "user:"+userID) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// allowRequest takes a token from the bucket of client and writes the state of bucket to headers.
+// It writes the `429` response and returns false when the request is rejected.
+func (m *Middleware) allowRequest(w http.ResponseWriter, r *http.Request, limiter *ratelimit.Limiter, group models.RateLimitGroup, key string) bool {
+	decision := limiter.Allow(key)
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(decision.Reset)))
+	if decision.Allowed {
+		return true
+	}
+	retryAfter := seconds(decision.RetryAfter)
+	m.logger.Info(
+		fmt.Sprintf("[worker_%d] %s too many requests", m.workerID, constants.Client),
+		zap.String("group", group.Name),
+		zap.String("client", key),
+	)
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	problem.Write(w, r, http.StatusTooManyRequests, fmt.Sprintf("Too many requests. Please retry after %d seconds.", retryAfter))
+	return false
+}
+
+// routeLimit returns the limit of the first group which path belongs to, or nil when the path is not limited
+func (m *Middleware) routeLimit(path string) *routeLimit {
+	for i, limit := range m.limits {
+		for _, prefix := range limit.group.PathPrefixes {
+			if strings.HasPrefix(path, prefix) {
+				return &m.limits[i]
+			}
+		}
+	}
+	return nil
+}
+
+// clientIP returns the IP address of client which made the request
+func (m *Middleware) clientIP(r *http.Request) string {
+	return clientAddress(r.RemoteAddr, r.Header.Values("X-Forwarded-For"), m.trustedProxies)
+}
+
+// clientAddress returns the IP address of client. When the connection comes from a trusted proxy, the address is read
+// from `X-Forwarded-For` header: proxies append the address which they received the request from, so the header is read
+// from the end, and the first address which is not a trusted proxy is the client. Addresses before it may be sent by client,
+// so they are not trusted.
+func clientAddress(remoteAddr string, forwardedFor []string, trustedProxies []*net.IPNet) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	if !isTrustedProxy(host, trustedProxies) {
+		return host
+	}
+
+	addresses := make([]string, 0)
+	for _, header := range forwardedFor {
+		for _, address := range strings.Split(header, ",") {
+			addresses = append(addresses, strings.TrimSpace(address))
+		}
+	}
+	for i := len(addresses) - 1; i >= 0; i-- {
+		if net.ParseIP(addresses[i]) == nil {
+			// header is malformed, so the addresses before it cannot be trusted either
+			break
+		}
+		host = addresses[i]
+		if !isTrustedProxy(host, trustedProxies) {
+			break
+		}
+	}
+	return host
+}
+
+// isTrustedProxy checks whether the address belongs to one of trusted proxies
+func isTrustedProxy(address string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// seconds rounds the duration up to whole seconds, as HTTP headers expect
+func seconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
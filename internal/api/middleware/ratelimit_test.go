@@ -0,0 +1,142 @@
+// AnhCao 2024
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClientAddress(t *testing.T) {
+	trustedProxies := newTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expected     string
+	}{
+		{name: "direct connection", remoteAddr: "203.0.113.7:51234", expected: "203.0.113.7"},
+		{name: "forwarded header of untrusted connection is ignored", remoteAddr: "203.0.113.7:51234", forwardedFor: []string{"198.51.100.1"}, expected: "203.0.113.7"},
+		{name: "client behind trusted proxy", remoteAddr: "10.1.2.3:443", forwardedFor: []string{"198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "client behind chain of trusted proxies", remoteAddr: "10.1.2.3:443", forwardedFor: []string{"198.51.100.1, 192.168.1.1", "10.0.0.5"}, expected: "198.51.100.1"},
+		{name: "address sent by client is not trusted", remoteAddr: "10.1.2.3:443", forwardedFor: []string{"1.1.1.1, 198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "trusted proxy without forwarded header", remoteAddr: "192.168.1.1:443", expected: "192.168.1.1"},
+		{name: "malformed forwarded header", remoteAddr: "10.1.2.3:443", forwardedFor: []string{"198.51.100.1, unknown"}, expected: "10.1.2.3"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := clientAddress(test.remoteAddr, test.forwardedFor, trustedProxies); got != test.expected {
+				t.Errorf("clientAddress() = %q, want %q", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestRateLimitIPRunsBeforeAuthentication(t *testing.T) {
+	config := &models.Config{}
+	config.RateLimit.Groups = []models.RateLimitGroup{
+		{Name: "prices", PathPrefixes: []string{"/v1/market-price"}, RequestsPerMinute: 60, Burst: 5, IPRequestsPerMinute: 1, IPBurst: 2},
+	}
+	middleware := NewMiddleware(zap.NewNop(), config, 1)
+	authenticated := 0
+	handler := middleware.RateLimitIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// access token of every request is invalid
+		authenticated++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	expectedStatus := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}
+	for i, expected := range expectedStatus {
+		request := httptest.NewRequest(http.MethodGet, "/v1/market-price/today-tomorrow", nil)
+		request.RemoteAddr = "203.0.113.7:51234"
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != expected {
+			t.Errorf("request %d: status = %d, want %d", i+1, recorder.Code, expected)
+		}
+	}
+	if authenticated != 2 {
+		t.Errorf("expected access token to be verified 2 times, got %d", authenticated)
+	}
+
+	// requests of other address have their own bucket
+	request := httptest.NewRequest(http.MethodGet, "/v1/market-price/today-tomorrow", nil)
+	request.RemoteAddr = "198.51.100.1:51234"
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestRateLimitByUser(t *testing.T) {
+	config := &models.Config{}
+	config.RateLimit.Groups = []models.RateLimitGroup{
+		{Name: "prices", PathPrefixes: []string{"/v1/market-price"}, RequestsPerMinute: 1, Burst: 1},
+	}
+	middleware := NewMiddleware(zap.NewNop(), config, 1)
+	handler := middleware.RateLimit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name           string
+		path           string
+		userID         string
+		expectedStatus int
+	}{
+		{name: "first request of user", path: "/v1/market-price", userID: "12345", expectedStatus: http.StatusNoContent},
+		{name: "bucket of user is empty", path: "/v1/market-price", userID: "12345", expectedStatus: http.StatusTooManyRequests},
+		{name: "other user has own bucket", path: "/v1/market-price", userID: "67890", expectedStatus: http.StatusNoContent},
+		{name: "request without user is limited by address only", path: "/v1/market-price", expectedStatus: http.StatusNoContent},
+		{name: "route without group is not limited", path: "/v1/households", userID: "12345", expectedStatus: http.StatusNoContent},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, test.path, nil)
+			if test.userID != "" {
+				request = request.WithContext(context.WithValue(request.Context(), constants.UserIdKey, test.userID))
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != test.expectedStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, test.expectedStatus)
+			}
+			if test.expectedStatus == http.StatusTooManyRequests && recorder.Header().Get("Retry-After") == "" {
+				t.Error("expected `Retry-After` header")
+			}
+		})
+	}
+}
+
+func TestUnaryRateLimit(t *testing.T) {
+	config := &models.Config{}
+	config.RateLimit.Groups = []models.RateLimitGroup{
+		{Name: "grpc", PathPrefixes: []string{"/stormbreaker.v1.Stormbreaker/"}, RequestsPerMinute: 1, Burst: 1},
+	}
+	middleware := NewMiddleware(zap.NewNop(), config, 1)
+	info := &grpc.UnaryServerInfo{FullMethod: "/stormbreaker.v1.Stormbreaker/GetPriceSettings"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	ctx := context.WithValue(context.Background(), constants.UserIdKey, "12345")
+	if _, err := middleware.UnaryRateLimit(ctx, nil, info, handler); err != nil {
+		t.Fatalf("unexpected error of first call: %v", err)
+	}
+	_, err := middleware.UnaryRateLimit(ctx, nil, info, handler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted, got %v", err)
+	}
+}
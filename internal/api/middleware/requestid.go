@@ -0,0 +1,34 @@
+// AnhCao 2024
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// REQUEST_ID_HEADER is the header which carries the id of request from client and back in response
+const REQUEST_ID_HEADER string = "X-Request-ID"
+
+// newRequestID generates a random id of request
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// isValidRequestID checks that the id of request which client sent is safe to write to logs and headers
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > 128 {
+		return false
+	}
+	for _, c := range requestID {
+		isLetter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !isDigit && c != '-' && c != '_' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
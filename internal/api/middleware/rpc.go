@@ -0,0 +1,159 @@
+// AnhCao 2024
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryAuthenticate verifies the access token of gRPC call like Authenticate does for HTTP requests,
+// and adds the id of request and the id of user to the context of call
+func (m *Middleware) UnaryAuthenticate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := m.authenticateCall(ctx)
+	m.logCall(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamAuthenticate verifies the access token of streaming gRPC call like Authenticate does for HTTP requests,
+// and adds the id of request and the id of user to the context of stream
+func (m *Middleware) StreamAuthenticate(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := m.authenticateCall(stream.Context())
+	m.logCall(ctx, info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticateCall reads the id of request and the access token from metadata of gRPC call
+func (m *Middleware) authenticateCall(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := firstMetadata(md, strings.ToLower(REQUEST_ID_HEADER))
+	if !isValidRequestID(requestID) {
+		requestID = newRequestID()
+	}
+	ctx = context.WithValue(ctx, constants.RequestIdKey, requestID)
+
+	userID, roles, _, err := m.verifyAccessToken(firstMetadata(md, "authorization"))
+	if err != nil {
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+	ctx = context.WithValue(ctx, constants.UserIdKey, userID)
+	return context.WithValue(ctx, constants.RolesKey, roles), nil
+}
+
+// logCall logs the received call together with the id of request
+func (m *Middleware) logCall(ctx context.Context, method string) {
+	requestID, _ := ctx.Value(constants.RequestIdKey).(string)
+	m.logger.Info(
+		fmt.Sprintf("[worker_%d] call received", m.workerID),
+		zap.String("method", method),
+		zap.String(constants.RequestIdField, requestID),
+	)
+}
+
+// firstMetadata returns the first value of metadata key, or empty string when the key is missing
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// contextStream replaces the context of server stream (ex: with the one which carries the id of user)
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryRateLimitIP limits the gRPC calls of every IP address like RateLimitIP does for HTTP requests.
+// It runs before UnaryAuthenticate. The groups of routes match the full name of method (ex: "/stormbreaker.v1.Stormbreaker/").
+func (m *Middleware) UnaryRateLimitIP(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := m.allowCall(ctx, info.FullMethod, false); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// UnaryRateLimit limits the gRPC calls of every user like RateLimit does for HTTP requests. It runs after UnaryAuthenticate.
+func (m *Middleware) UnaryRateLimit(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := m.allowCall(ctx, info.FullMethod, true); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamRateLimitIP limits the streaming gRPC calls of every IP address. It runs before StreamAuthenticate.
+func (m *Middleware) StreamRateLimitIP(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := m.allowCall(stream.Context(), info.FullMethod, false); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// StreamRateLimit limits the streaming gRPC calls of every user. It runs after StreamAuthenticate.
+func (m *Middleware) StreamRateLimit(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := m.allowCall(stream.Context(), info.FullMethod, true); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// allowCall takes a token from the bucket of user (`byUser`) or IP address of call.
+// Rejected calls get `ResourceExhausted` with `retry-after` (seconds) in header metadata.
+func (m *Middleware) allowCall(ctx context.Context, method string, byUser bool) error {
+	limit := m.routeLimit(method)
+	if limit == nil {
+		return nil
+	}
+
+	limiter := limit.ipLimiter
+	key := "ip:" + m.callerIP(ctx)
+	if byUser {
+		userID, ok := ctx.Value(constants.UserIdKey).(string)
+		if !ok {
+			return nil
+		}
+		limiter, key = limit.userLimiter, "user:"+userID
+	}
+
+	decision := limiter.Allow(key)
+	if decision.Allowed {
+		return nil
+	}
+	retryAfter := seconds(decision.RetryAfter)
+	m.logger.Info(
+		fmt.Sprintf("[worker_%d] %s too many calls", m.workerID, constants.Client),
+		zap.String("group", limit.group.Name),
+		zap.String("client", key),
+	)
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfter)))
+	return status.Errorf(codes.ResourceExhausted, "Too many requests. Please retry after %d seconds.", retryAfter)
+}
+
+// callerIP returns the IP address of client which made the gRPC call
+func (m *Middleware) callerIP(ctx context.Context) string {
+	remoteAddr := ""
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remoteAddr = p.Addr.String()
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return clientAddress(remoteAddr, md.Get("x-forwarded-for"), m.trustedProxies)
+}
@@ -0,0 +1,167 @@
+// AnhCao 2024
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	middleware := NewMiddleware(zap.NewNop(), &models.Config{}, 1)
+	router := mux.NewRouter()
+	router.Use(middleware.RequestID, middleware.Tracing)
+	router.HandleFunc("/v1/households/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["id"] == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}).Methods("GET")
+
+	tests := []struct {
+		name            string
+		path            string
+		traceparent     string
+		expectedTraceID string
+		expectedStatus  int
+		expectedCode    codes.Code
+	}{
+		{name: "new trace", path: "/v1/households/12345", expectedStatus: http.StatusOK, expectedCode: codes.Unset},
+		{
+			name:            "trace of caller continues",
+			path:            "/v1/households/12345",
+			traceparent:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectedStatus:  http.StatusOK,
+			expectedCode:    codes.Unset,
+		},
+		{name: "server error", path: "/v1/households/broken", expectedStatus: http.StatusInternalServerError, expectedCode: codes.Error},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exporter.Reset()
+			request := httptest.NewRequest("GET", test.path, nil)
+			if test.traceparent != "" {
+				request.Header.Set("traceparent", test.traceparent)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), request)
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name != "GET /v1/households/{id}" {
+				t.Errorf("span name = %s, want GET /v1/households/{id}", span.Name)
+			}
+			if test.expectedTraceID != "" && span.SpanContext.TraceID().String() != test.expectedTraceID {
+				t.Errorf("trace id = %s, want %s", span.SpanContext.TraceID(), test.expectedTraceID)
+			}
+			if !hasAttribute(span.Attributes, attribute.Int("http.response.status_code", test.expectedStatus)) {
+				t.Errorf("span has no status code %d: %v", test.expectedStatus, span.Attributes)
+			}
+			if span.Status.Code != test.expectedCode {
+				t.Errorf("span status = %v, want %v", span.Status.Code, test.expectedCode)
+			}
+		})
+	}
+}
+
+func TestUnaryTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	middleware := NewMiddleware(zap.NewNop(), &models.Config{}, 1)
+	info := &grpc.UnaryServerInfo{FullMethod: "/stormbreaker.v1.Stormbreaker/GetPriceSettings"}
+
+	tests := []struct {
+		name            string
+		traceparent     string
+		err             error
+		expectedTraceID string
+		expectedCode    codes.Code
+	}{
+		{name: "new trace", expectedCode: codes.Unset},
+		{
+			name:            "trace of caller continues",
+			traceparent:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectedCode:    codes.Unset,
+		},
+		{name: "client error", err: status.Error(grpccodes.NotFound, "not found"), expectedCode: codes.Unset},
+		{name: "server error", err: status.Error(grpccodes.Internal, "broken"), expectedCode: codes.Error},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exporter.Reset()
+			ctx := context.Background()
+			if test.traceparent != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("traceparent", test.traceparent))
+			}
+			_, err := middleware.UnaryTracing(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, test.err
+			})
+			if err != test.err {
+				t.Errorf("error = %v, want %v", err, test.err)
+			}
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name != "stormbreaker.v1.Stormbreaker/GetPriceSettings" {
+				t.Errorf("span name = %s, want stormbreaker.v1.Stormbreaker/GetPriceSettings", span.Name)
+			}
+			if test.expectedTraceID != "" && span.SpanContext.TraceID().String() != test.expectedTraceID {
+				t.Errorf("trace id = %s, want %s", span.SpanContext.TraceID(), test.expectedTraceID)
+			}
+			if !hasAttribute(span.Attributes, attribute.String("rpc.method", "GetPriceSettings")) {
+				t.Errorf("span has no method: %v", span.Attributes)
+			}
+			if span.Status.Code != test.expectedCode {
+				t.Errorf("span status = %v, want %v", span.Status.Code, test.expectedCode)
+			}
+		})
+	}
+}
+
+func hasAttribute(attributes []attribute.KeyValue, expected attribute.KeyValue) bool {
+	for _, attr := range attributes {
+		if attr == expected {
+			return true
+		}
+	}
+	return false
+}
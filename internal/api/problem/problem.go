@@ -0,0 +1,118 @@
+// AnhCao 2024
+//
+// Package problem writes error responses as RFC 7807 problem details (`application/problem+json`).
+// Every problem has a stable machine-readable code which clients (web, mobile) can branch on,
+// a message which is safe to show to user and the id of the request to find the details in logs.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+)
+
+const CONTENT_TYPE string = "application/problem+json"
+
+// Stable error codes. Clients rely on them, so existing codes must not be renamed.
+const (
+	INVALID_REQUEST        string = "invalid_request"        // request body, query or header is not valid
+	MISSING_ACCESS_TOKEN   string = "missing_access_token"   // request has no `Authorization` header
+	INVALID_ACCESS_TOKEN   string = "invalid_access_token"   // access token cannot be verified or has no user
+	UNAUTHENTICATED        string = "unauthenticated"        // user of request is unknown
+	FORBIDDEN              string = "forbidden"              // user is not allowed to do the operation
+	NOT_FOUND              string = "not_found"              // resource or endpoint does not exist
+	METHOD_NOT_ALLOWED     string = "method_not_allowed"     // endpoint does not support the method
+	CONFLICT               string = "conflict"               // resource exists already
+	PRECONDITION_FAILED    string = "precondition_failed"    // resource was changed after the version in `If-Match`
+	PRECONDITION_REQUIRED  string = "precondition_required"  // request which changes resource has no `If-Match` header
+	UNSUPPORTED_MEDIA_TYPE string = "unsupported_media_type" // request body has media type which endpoint does not accept
+	TOO_MANY_REQUESTS      string = "too_many_requests"      // client made too many requests, retry after `Retry-After` seconds
+	INTERNAL_ERROR         string = "internal_error"         // something went wrong in the service or its dependencies
+
+	HOUSEHOLD_NOT_FOUND                string = "household_not_found"                // household does not exist or user has no access to it
+	DEFAULT_HOUSEHOLD_READ_ONLY        string = "default_household_read_only"        // default household of user cannot be changed or deleted
+	HOUSEHOLD_MEMBER_NOT_FOUND         string = "household_member_not_found"         // member of household does not exist
+	HOUSEHOLD_MEMBER_INVITED           string = "household_member_invited"           // user is invited to household already
+	HOUSEHOLD_INVITATION_NOT_FOUND     string = "household_invitation_not_found"     // invitation to household does not exist
+	PRICE_SETTINGS_NOT_FOUND           string = "price_settings_not_found"           // household has no stored price settings
+	PRICE_SETTINGS_EXIST               string = "price_settings_exist"               // household has price settings already
+	PRICE_SETTINGS_UNCHANGED           string = "price_settings_unchanged"           // patch of price settings has nothing to change
+	PRICE_ALERT_NOT_FOUND              string = "price_alert_not_found"              // price alert does not exist
+	INVALID_PRICE_ALERT_ID             string = "invalid_price_alert_id"             // id of price alert is malformed
+	CALENDAR_FEED_NOT_FOUND            string = "calendar_feed_not_found"            // calendar feed does not exist
+	NOTIFICATION_PREFERENCES_NOT_FOUND string = "notification_preferences_not_found" // user has no stored notification preferences
+)
+
+// INTERNAL_ERROR_MESSAGE is shown to user instead of the details of server errors, which only go to logs
+const INTERNAL_ERROR_MESSAGE string = "Something went wrong on our side. Please try again later."
+
+// Details represents the body of error response (RFC 7807)
+type Details struct {
+	Type      string `json:"type" example:"about:blank"`                                               // type of the problem. Always "about:blank", so `code` identifies the problem
+	Title     string `json:"title" example:"Not Found"`                                                // short summary of the HTTP status
+	Status    int    `json:"status" example:"404"`                                                     // HTTP status code
+	Code      string `json:"code" example:"not_found"`                                                 // stable machine-readable error code
+	Detail    string `json:"detail" example:"failed to get household: no matched household was found"` // message which is safe to show to user
+	Instance  string `json:"instance" example:"/v1/households/6759a8f1c2a4b5e3f1d2c3b4"`               // path of the request
+	RequestID string `json:"request_id" example:"4f9c1e0b7a6d5c3b2a1f0e9d8c7b6a5f"`                    // id of the request, also returned through `X-Request-ID` header
+}
+
+// Write writes the problem with the default code of HTTP status.
+// The message of server errors (5xx) is replaced by a generic one because it may contain internal details.
+func Write(w http.ResponseWriter, r *http.Request, status int, message string) {
+	WriteCode(w, r, status, CodeOf(status), message)
+}
+
+// WriteCode writes the problem with given code.
+// The message of server errors (5xx) is replaced by a generic one because it may contain internal details.
+func WriteCode(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	if status >= http.StatusInternalServerError {
+		message = INTERNAL_ERROR_MESSAGE
+	}
+	requestID, _ := r.Context().Value(constants.RequestIdKey).(string)
+	details := Details{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Code:      code,
+		Detail:    message,
+		Instance:  r.URL.Path,
+		RequestID: requestID,
+	}
+
+	w.Header().Set("Content-Type", CONTENT_TYPE)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(details)
+}
+
+// CodeOf returns the default error code of HTTP status
+func CodeOf(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return INVALID_REQUEST
+	case http.StatusUnauthorized:
+		return UNAUTHENTICATED
+	case http.StatusForbidden:
+		return FORBIDDEN
+	case http.StatusNotFound:
+		return NOT_FOUND
+	case http.StatusMethodNotAllowed:
+		return METHOD_NOT_ALLOWED
+	case http.StatusConflict:
+		return CONFLICT
+	case http.StatusPreconditionFailed:
+		return PRECONDITION_FAILED
+	case http.StatusUnsupportedMediaType:
+		return UNSUPPORTED_MEDIA_TYPE
+	case http.StatusPreconditionRequired:
+		return PRECONDITION_REQUIRED
+	case http.StatusTooManyRequests:
+		return TOO_MANY_REQUESTS
+	}
+	if status < http.StatusInternalServerError {
+		return INVALID_REQUEST
+	}
+	return INTERNAL_ERROR
+}
@@ -0,0 +1,92 @@
+// AnhCao 2024
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		message        string
+		expectedCode   string
+		expectedDetail string
+	}{
+		{
+			name:           "client error keeps its message",
+			status:         http.StatusBadRequest,
+			message:        "`margin` should be a valid number",
+			expectedCode:   INVALID_REQUEST,
+			expectedDetail: "`margin` should be a valid number",
+		},
+		{
+			name:           "precondition failed has its own code",
+			status:         http.StatusPreconditionFailed,
+			message:        "settings were changed",
+			expectedCode:   PRECONDITION_FAILED,
+			expectedDetail: "settings were changed",
+		},
+		{
+			name:           "precondition required has its own code",
+			status:         http.StatusPreconditionRequired,
+			message:        "`If-Match` header is required",
+			expectedCode:   PRECONDITION_REQUIRED,
+			expectedDetail: "`If-Match` header is required",
+		},
+		{
+			name:           "unsupported media type has its own code",
+			status:         http.StatusUnsupportedMediaType,
+			message:        "request body should be `application/merge-patch+json`",
+			expectedCode:   UNSUPPORTED_MEDIA_TYPE,
+			expectedDetail: "request body should be `application/merge-patch+json`",
+		},
+		{
+			name:           "too many requests has its own code",
+			status:         http.StatusTooManyRequests,
+			message:        "too many requests, retry after 2 seconds",
+			expectedCode:   TOO_MANY_REQUESTS,
+			expectedDetail: "too many requests, retry after 2 seconds",
+		},
+		{
+			name:           "server error hides internal details",
+			status:         http.StatusInternalServerError,
+			message:        "failed to get price settings: connection refused 10.0.0.3:27017",
+			expectedCode:   INTERNAL_ERROR,
+			expectedDetail: INTERNAL_ERROR_MESSAGE,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/v1/price-settings", nil)
+			r = r.WithContext(context.WithValue(r.Context(), constants.RequestIdKey, "abc123"))
+			w := httptest.NewRecorder()
+
+			Write(w, r, test.status, test.message)
+
+			if w.Code != test.status {
+				t.Errorf("unexpected status code: got %d, want %d", w.Code, test.status)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != CONTENT_TYPE {
+				t.Errorf("unexpected content type: got %q, want %q", contentType, CONTENT_TYPE)
+			}
+			var details Details
+			if err := json.NewDecoder(w.Body).Decode(&details); err != nil {
+				t.Fatalf("failed to decode problem details: %v", err)
+			}
+			if details.Code != test.expectedCode || details.Detail != test.expectedDetail {
+				t.Errorf("unexpected problem: got (%q, %q), want (%q, %q)", details.Code, details.Detail, test.expectedCode, test.expectedDetail)
+			}
+			if details.RequestID != "abc123" || details.Instance != "/v1/price-settings" || details.Status != test.status {
+				t.Errorf("unexpected problem details: %+v", details)
+			}
+		})
+	}
+}
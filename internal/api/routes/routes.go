@@ -7,6 +7,9 @@ import (
 	"github.com/AnhCaooo/stormbreaker/internal/api/handlers"
 )
 
+// ADMIN_PATH_PREFIX is the prefix of admin API, whose routes only the users who have the admin role in access token may use
+const ADMIN_PATH_PREFIX string = "/admin"
+
 // Endpoint is the presentation of object which contains values for routing
 type Endpoint struct {
 	Path    string
@@ -14,7 +17,7 @@ type Endpoint struct {
 	Method  string
 }
 
-// InitializeEndpoints creates a pool of Endpoints
+// InitializeEndpoints creates a pool of Endpoints which require access token of user
 func InitializeEndpoints(handler *handlers.Handler) []Endpoint {
 	return []Endpoint{
 		{
@@ -32,6 +35,21 @@ func InitializeEndpoints(handler *handlers.Handler) []Endpoint {
 			Handler: handler.GetTodayTomorrowPrice,
 			Method:  "GET",
 		},
+		{
+			Path:    "/v2/market-price",
+			Handler: handler.PostMarketPriceV2,
+			Method:  "POST",
+		},
+		{
+			Path:    "/v2/market-price/today-tomorrow",
+			Handler: handler.GetTodayTomorrowPriceV2,
+			Method:  "GET",
+		},
+		{
+			Path:    "/v1/market-price/stream",
+			Handler: handler.StreamPrices,
+			Method:  "GET",
+		},
 		{
 			Path:    "/v1/price-settings",
 			Handler: handler.GetPriceSettings,
@@ -52,6 +70,185 @@ func InitializeEndpoints(handler *handlers.Handler) []Endpoint {
 			Handler: handler.DeletePriceSettings,
 			Method:  "DELETE",
 		},
+		{
+			Path:    "/v1/price-settings/history",
+			Handler: handler.GetPriceSettingsHistory,
+			Method:  "GET",
+		},
+		{
+			Path:    "/v1/cost",
+			Handler: handler.GetCost,
+			Method:  "GET",
+		},
+		{
+			Path:    "/v1/cost/projection",
+			Handler: handler.GetBillProjection,
+			Method:  "GET",
+		},
+		{
+			Path:    "/v1/price-alerts",
+			Handler: handler.GetPriceAlerts,
+			Method:  "GET",
+		},
+		{
+			Path:    "/v1/price-alerts",
+			Handler: handler.CreatePriceAlert,
+			Method:  "POST",
+		},
+		{
+			Path:    "/v1/price-alerts/{id}",
+			Handler: handler.GetPriceAlert,
+			Method:  "GET",
+		},
+		{
+			Path:    "/v1/price-alerts/{id}",
+			Handler: handler.UpdatePriceAlert,
+			Method:  "PUT",
+		},
+		{
+			Path:    "/v1/price-alerts/{id}",
+			Handler: handler.DeletePriceAlert,
+			Method:  "DELETE",
+		},
+		{
+			Path:    "/v1/notification-preferences",
+			Handler: handler.GetNotificationPreferences,
+			Method:  "GET",
+		},
+		{
+			Path:    "/v1/notification-preferences",
+			Handler: handler.PutNotificationPreferences,
+			Method:  "PUT",
+		},
+		{
+			Path:    "/v1/households",
+			Handler: handler.GetHouseholds,
+			Method:  "GET",
+		},
+		{
+			Path:    "/v1/households",
+			Handler: handler.CreateHousehold,
+			Method:  "POST",
+		},
+		{
+			Path:    "/v1/households/{id}",
+			Handler: handler.GetHousehold,
+			Method:  "GET",
+		},
+		{
+			Path:    "/v1/households/{id}",
+			Handler: handler.UpdateHousehold,
+			Method:  "PUT",
+		},
+		{
+			Path:    "/v1/households/{id}",
+			Handler: handler.DeleteHousehold,
+			Method:  "DELETE",
+		},
+		{
+			Path:    "/v1/households/{id}/members",
+			Handler: handler.GetHouseholdMembers,
+			Method:  "GET",
+		},
+		{
+			Path:    "/v1/households/{id}/members",
+			Handler: handler.InviteHouseholdMember,
+			Method:  "POST",
+		},
+		{
+			Path:    "/v1/households/{id}/members/{user_id}",
+			Handler: handler.UpdateHouseholdMember,
+			Method:  "PUT",
+		},
+		{
+			Path:    "/v1/households/{id}/members/{user_id}",
+			Handler: handler.DeleteHouseholdMember,
+			Method:  "DELETE",
+		},
+		{
+			Path:    "/v1/household-invitations",
+			Handler: handler.GetHouseholdInvitations,
+			Method:  "GET",
+		},
+		{
+			Path:    "/v1/household-invitations/{id}/accept",
+			Handler: handler.AcceptHouseholdInvitation,
+			Method:  "POST",
+		},
+		{
+			Path:    "/v1/me/export",
+			Handler: handler.ExportUserData,
+			Method:  "GET",
+		},
+		{
+			Path:    "/v1/me",
+			Handler: handler.EraseUserData,
+			Method:  "DELETE",
+		},
+		{
+			Path:    "/v1/calendar-feed",
+			Handler: handler.CreateCalendarFeed,
+			Method:  "POST",
+		},
+		{
+			Path:    "/v1/calendar-feed",
+			Handler: handler.DeleteCalendarFeed,
+			Method:  "DELETE",
+		},
+		{
+			Path:    "/graphql",
+			Handler: handler.GraphQL,
+			Method:  "POST",
+		},
 		// ? /v1/market-price/usage-situation - use AI to analyze from which time user can use normally, or just fixed limit?
 	}
 }
+
+// InitializePublicEndpoints creates a pool of Endpoints which clients without access token may use,
+// like health probes of orchestrator (ex: Kubernetes) and calendar applications
+func InitializePublicEndpoints(handler *handlers.Handler) []Endpoint {
+	return []Endpoint{
+		{
+			// the calendar feed is identified by its own secret token
+			Path:    "/v1/calendar.ics",
+			Handler: handler.GetCalendar,
+			Method:  "GET",
+		},
+		{
+			Path:    "/healthz",
+			Handler: handler.Liveness,
+			Method:  "GET",
+		},
+		{
+			Path:    "/readyz",
+			Handler: handler.Readiness,
+			Method:  "GET",
+		},
+	}
+}
+
+// InitializeAdminEndpoints creates a pool of Endpoints of admin API. Their paths are relative to `ADMIN_PATH_PREFIX`.
+func InitializeAdminEndpoints(handler *handlers.Handler) []Endpoint {
+	return []Endpoint{
+		{
+			Path:    "/price-settings",
+			Handler: handler.ListPriceSettings,
+			Method:  "GET",
+		},
+		{
+			Path:    "/price-settings/{household_id}",
+			Handler: handler.ForceDeletePriceSettings,
+			Method:  "DELETE",
+		},
+		{
+			Path:    "/users/{user_id}/price-settings",
+			Handler: handler.GetUserPriceSettings,
+			Method:  "GET",
+		},
+		{
+			Path:    "/summary",
+			Handler: handler.GetPriceSettingsSummary,
+			Method:  "GET",
+		},
+	}
+}
@@ -2,10 +2,12 @@
 package cache
 
 import (
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/AnhCaooo/stormbreaker/internal/metrics"
 	"go.uber.org/zap"
 )
 
@@ -23,6 +25,7 @@ type Cache struct {
 
 type CacheValue struct {
 	Value      interface{}
+	CreatedAt  time.Time
 	Expiration time.Time
 }
 
@@ -53,6 +56,7 @@ func (c *Cache) SetExpiredAfterTimePeriod(key string, value interface{}, duratio
 	)
 	c.Data[key] = CacheValue{
 		Value:      value,
+		CreatedAt:  time.Now().UTC(),
 		Expiration: expirationTime,
 	}
 }
@@ -71,6 +75,7 @@ func (c *Cache) SetExpiredAtTime(key string, value interface{}, expiredTime time
 
 	c.Data[key] = CacheValue{
 		Value:      value,
+		CreatedAt:  time.Now().UTC(),
 		Expiration: expiredTime,
 	}
 }
@@ -81,13 +86,20 @@ func (c *Cache) SetExpiredAtTime(key string, value interface{}, expiredTime time
 // If the value is still valid, it returns the value and a boolean value of `true` to indicate that a valid value was found.
 // If the value is not valid (means not yet cached), it returns `nil` and a boolean value of `false`.
 func (c *Cache) Get(key string) (interface{}, bool) {
+	value, exists := c.Lookup(key)
+	return value.Value, exists
+}
+
+// Lookup works like Get, but returns the whole cache entry, so that caller also knows when the value was cached and when it expires
+func (c *Cache) Lookup(key string) (CacheValue, bool) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
 	value, exists := c.Data[key]
 	if !exists {
 		c.logger.Debug("cache key was not found from cache")
-		return nil, false
+		metrics.ObserveCacheLookup(keyClass(key), false)
+		return CacheValue{}, false
 	}
 	if time.Now().After(value.Expiration) {
 		c.logger.Debug("cache was expired",
@@ -95,16 +107,30 @@ func (c *Cache) Get(key string) (interface{}, bool) {
 			zap.Time("current-time-in-utc-zone", time.Now()),
 		)
 		c.Delete(key)
-		return nil, false
+		metrics.ObserveCacheLookup(keyClass(key), false)
+		return CacheValue{}, false
 	}
+	metrics.ObserveCacheLookup(keyClass(key), true)
 	c.logger.Debug("cache living time",
 		zap.Any("expired-time-in-utc-zone", value.Expiration),
 		zap.Time("current-time-in-utc-zone", time.Now().UTC()),
 	)
-	return value.Value, true
+	return value, true
 }
 
 // Delete cache based on receiving cache key. If key is not valid, then Delete is no-op
 func (c *Cache) Delete(key string) {
 	delete(c.Data, key)
 }
+
+// keyClass returns the kind of cache key without the id of household and month which it belongs to
+// (ex: `<id>_price_settings`), so that metrics do not get a time series for every household.
+// Plain prices are checked first, because their key contains the key of household's prices.
+func keyClass(key string) string {
+	for _, class := range []string{PlainTodayTomorrowPricesKey, UserTodayTomorrowPricesKey, UserPriceSettingsKey} {
+		if strings.Contains(key, class) {
+			return class
+		}
+	}
+	return "other"
+}
@@ -3,5 +3,10 @@ package constants
 type contextKey string
 
 const (
-	UserIdKey contextKey = "USER_ID" // Key type for storing userID in context
+	UserIdKey    contextKey = "USER_ID"    // Key type for storing userID in context
+	RequestIdKey contextKey = "REQUEST_ID" // Key type for storing the id of request in context
+	RolesKey     contextKey = "ROLES"      // Key type for storing the roles of user (from access token) in context
 )
+
+// RequestIdField is the field of log lines which carries the id of request, so that one user action can be traced across services
+const RequestIdField string = "request_id"
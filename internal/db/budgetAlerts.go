@@ -0,0 +1,37 @@
+// AnhCao 2024
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ClaimBudgetAlert records that the household is warned about its budget in given month. It returns false when the household
+// was warned in the month already (ex: before the service restarted, or by other instance of service), so that it is not warned twice.
+func (db Mongo) ClaimBudgetAlert(householdID, month string, now time.Time) (claimed bool, err error) {
+	alert := models.BudgetAlert{
+		HouseholdID: householdID,
+		Month:       month,
+		AlertedAt:   now.UTC(),
+	}
+	if _, err = db.budgetAlertsCollection.InsertOne(db.ctx, alert); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim budget alert: %s", err.Error())
+	}
+	return true, nil
+}
+
+// ReleaseBudgetAlert removes the claim of budget alert when the warning could not be sent, so that it is sent again in the next check.
+func (db Mongo) ReleaseBudgetAlert(householdID, month string) error {
+	filter := bson.M{"household_id": householdID, "month": month}
+	if _, err := db.budgetAlertsCollection.DeleteOne(db.ctx, filter); err != nil {
+		return fmt.Errorf("failed to release budget alert: %s", err.Error())
+	}
+	return nil
+}
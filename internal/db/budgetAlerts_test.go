@@ -0,0 +1,74 @@
+// AnhCao 2024
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AnhCaooo/go-goods/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestClaimBudgetAlert(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+	now := time.Date(2024, 12, 9, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		mockResponse    bson.D
+		expectedClaimed bool
+		expectedError   string
+	}{
+		{
+			name:            "household is not warned in the month yet",
+			mockResponse:    mtest.CreateSuccessResponse(),
+			expectedClaimed: true,
+		},
+		{
+			name: "household is warned in the month already",
+			mockResponse: mtest.CreateWriteErrorsResponse(mtest.WriteError{
+				Index:   0,
+				Code:    11000,
+				Message: "duplicate key error",
+			}),
+			expectedClaimed: false,
+		},
+		{
+			name:          "database failure",
+			mockResponse:  mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 12345, Message: "some database error"}),
+			expectedError: "failed to claim budget alert: some database error",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.budgetAlertsCollection = mt.Coll
+			mt.AddMockResponses(test.mockResponse)
+
+			claimed, err := db.ClaimBudgetAlert("12345", "2024-12", now)
+			if test.expectedError != "" {
+				if err == nil || err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %v, want %q", err, test.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if claimed != test.expectedClaimed {
+				t.Errorf("unexpected claim: got %v, want %v", claimed, test.expectedClaimed)
+			}
+
+			inserted := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+			if month := inserted.Lookup("month").StringValue(); month != "2024-12" {
+				t.Errorf("unexpected month: got %q, want %q", month, "2024-12")
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// AnhCao 2024
+package db
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// GetCalendarFeed retrieves the calendar feed by the hash of its token
+func (db Mongo) GetCalendarFeed(tokenHash string) (feed *models.CalendarFeed, statusCode int, err error) {
+	feed = &models.CalendarFeed{}
+	if err = db.calendarCollection.FindOne(db.ctx, bson.M{"token_hash": tokenHash}).Decode(feed); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, http.StatusNotFound, fmt.Errorf("failed to get calendar feed: %w", ErrCalendarFeedNotFound)
+		}
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get calendar feed: %s", err.Error())
+	}
+	db.logger.Debug("get calendar feed successfully", zap.String("household_id", feed.HouseholdID))
+	return feed, http.StatusOK, nil
+}
+
+// UpsertCalendarFeed creates the calendar feed of user for household, or replaces the existing one.
+// Replacing changes the token, so the URL of the previous feed stops working.
+func (db Mongo) UpsertCalendarFeed(feed models.CalendarFeed) (statusCode int, err error) {
+	if feed.UserID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot insert un-authenticated document")
+		return
+	}
+
+	filter := bson.M{"user_id": feed.UserID, "household_id": feed.HouseholdID}
+	opts := options.Replace().SetUpsert(true)
+	if _, err = db.calendarCollection.ReplaceOne(db.ctx, filter, feed, opts); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to create calendar feed: %s", err.Error())
+		return
+	}
+	db.logger.Info("create calendar feed successfully", zap.String("household_id", feed.HouseholdID))
+	return http.StatusCreated, nil
+}
+
+// DeleteCalendarFeed deletes the calendar feed of user for household, so that its URL stops working
+func (db Mongo) DeleteCalendarFeed(userID, householdID string) (statusCode int, err error) {
+	if userID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot delete calendar feed of unauthenticated user")
+		return
+	}
+
+	result, err := db.calendarCollection.DeleteOne(db.ctx, bson.M{"user_id": userID, "household_id": householdID})
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to delete calendar feed: %s", err.Error())
+		return
+	}
+	if result.DeletedCount == 0 {
+		statusCode = http.StatusNotFound
+		err = fmt.Errorf("failed to delete calendar feed: %w", ErrCalendarFeedNotFound)
+		return
+	}
+	db.logger.Info("delete calendar feed successfully", zap.String("household_id", householdID))
+	return http.StatusOK, nil
+}
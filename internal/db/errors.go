@@ -0,0 +1,22 @@
+// AnhCao 2024
+package db
+
+import "errors"
+
+// Failures which are caused by the request rather than by the database. Errors of operations wrap them,
+// so callers can tell the failures apart with `errors.Is`. Their messages are safe to show to user.
+var (
+	ErrHouseholdNotFound               = errors.New("no matched household was found")
+	ErrDefaultHouseholdChanged         = errors.New("default household cannot be changed")
+	ErrDefaultHouseholdDeleted         = errors.New("default household cannot be deleted")
+	ErrHouseholdMemberNotFound         = errors.New("no matched member was found")
+	ErrHouseholdMemberInvited          = errors.New("user is invited already")
+	ErrHouseholdInvitationNotFound     = errors.New("no matched invitation was found")
+	ErrPriceSettingsNotFound           = errors.New("no matched settings were found")
+	ErrPriceSettingsExist              = errors.New("price settings exist already")
+	ErrPriceSettingsUnchanged          = errors.New("patch should contain at least one of `vat_included`, `margin` or `monthly_budget`")
+	ErrPriceAlertNotFound              = errors.New("no matched alert was found")
+	ErrInvalidPriceAlertID             = errors.New("invalid price alert id")
+	ErrCalendarFeedNotFound            = errors.New("no matched feed was found")
+	ErrNotificationPreferencesNotFound = errors.New("no matched notification preferences were found")
+)
@@ -0,0 +1,226 @@
+// AnhCao 2024
+package db
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// GetAccessibleHousehold retrieves the household which user owns or is an accepted member of,
+// with the role of user in it. A household which user cannot access is reported as not found.
+func (db Mongo) GetAccessibleHousehold(userID, householdID string) (household *models.Household, statusCode int, err error) {
+	if userID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot get household from unauthenticated user")
+		return
+	}
+	if householdID == userID {
+		household := defaultHousehold(userID)
+		return &household, http.StatusOK, nil
+	}
+
+	stored := &models.Household{}
+	if err = db.householdsCollection.FindOne(db.ctx, bson.M{"_id": householdID}).Decode(stored); err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to get household: %s", err.Error())
+		}
+		stored = nil
+	}
+	if stored != nil && stored.UserID == userID {
+		stored.Role = models.HOUSEHOLD_ROLE_OWNER
+		return stored, http.StatusOK, nil
+	}
+
+	member := &models.HouseholdMember{}
+	filter := bson.M{"household_id": householdID, "user_id": userID, "status": models.HOUSEHOLD_MEMBER_ACCEPTED}
+	if err = db.membersCollection.FindOne(db.ctx, filter).Decode(member); err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to get household: %s", err.Error())
+		}
+		return nil, http.StatusNotFound, fmt.Errorf("failed to get household: %w", ErrHouseholdNotFound)
+	}
+
+	if stored == nil {
+		// the default household of owner is never stored
+		shared := defaultHousehold(member.OwnerID)
+		stored = &shared
+	}
+	stored.Role = member.Role
+	db.logger.Debug("get shared household successfully", zap.String("household_id", householdID), zap.String("role", member.Role))
+	return stored, http.StatusOK, nil
+}
+
+// GetHouseholdMembers retrieves the invitations and members of household
+func (db Mongo) GetHouseholdMembers(householdID string) (members []models.HouseholdMember, statusCode int, err error) {
+	cursor, err := db.membersCollection.Find(db.ctx, bson.M{"household_id": householdID})
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to get household members: %s", err.Error())
+		return
+	}
+
+	members = make([]models.HouseholdMember, 0)
+	if err = cursor.All(db.ctx, &members); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to cursor all household members: %s", err.Error())
+		return nil, statusCode, err
+	}
+	db.logger.Info("get household members successfully", zap.Int("amount", len(members)))
+	return members, http.StatusOK, nil
+}
+
+// InviteHouseholdMember stores the invitation which the owner of household sends to another user
+func (db Mongo) InviteHouseholdMember(member models.HouseholdMember) (invited *models.HouseholdMember, statusCode int, err error) {
+	if member.OwnerID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot insert un-authenticated document")
+		return
+	}
+
+	member.Status = models.HOUSEHOLD_MEMBER_PENDING
+	member.InvitedAt = time.Now().UTC()
+	if _, err = db.membersCollection.InsertOne(db.ctx, member); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			statusCode = http.StatusConflict
+			err = fmt.Errorf("failed to invite household member: %w", ErrHouseholdMemberInvited)
+			return
+		}
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to invite household member: %s", err.Error())
+		return
+	}
+	db.logger.Info("invite household member successfully", zap.String("household_id", member.HouseholdID))
+	return &member, http.StatusCreated, nil
+}
+
+// GetHouseholdInvitations retrieves the invitations which user has not accepted yet
+func (db Mongo) GetHouseholdInvitations(userID string) (invitations []models.HouseholdMember, statusCode int, err error) {
+	if userID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot get household invitations from unauthenticated user")
+		return
+	}
+
+	filter := bson.M{"user_id": userID, "status": models.HOUSEHOLD_MEMBER_PENDING}
+	cursor, err := db.membersCollection.Find(db.ctx, filter)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to get household invitations: %s", err.Error())
+		return
+	}
+
+	invitations = make([]models.HouseholdMember, 0)
+	if err = cursor.All(db.ctx, &invitations); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to cursor all household invitations: %s", err.Error())
+		return nil, statusCode, err
+	}
+	db.logger.Info("get household invitations successfully", zap.Int("amount", len(invitations)))
+	return invitations, http.StatusOK, nil
+}
+
+// AcceptHouseholdInvitation makes user a member of household which user was invited to
+func (db Mongo) AcceptHouseholdInvitation(userID, householdID string) (statusCode int, err error) {
+	if userID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot accept household invitation of unauthenticated user")
+		return
+	}
+
+	filter := bson.M{"household_id": householdID, "user_id": userID, "status": models.HOUSEHOLD_MEMBER_PENDING}
+	update := bson.M{"$set": bson.M{"status": models.HOUSEHOLD_MEMBER_ACCEPTED}}
+	result, err := db.membersCollection.UpdateOne(db.ctx, filter, update)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to accept household invitation: %s", err.Error())
+		return
+	}
+	if result.MatchedCount == 0 {
+		statusCode = http.StatusNotFound
+		err = fmt.Errorf("failed to accept household invitation: %w", ErrHouseholdInvitationNotFound)
+		return
+	}
+	db.logger.Info("accept household invitation successfully", zap.String("household_id", householdID))
+	return http.StatusOK, nil
+}
+
+// UpdateHouseholdMemberRole changes the role of member (or invited user) in household
+func (db Mongo) UpdateHouseholdMemberRole(householdID, userID, role string) (statusCode int, err error) {
+	filter := bson.M{"household_id": householdID, "user_id": userID}
+	update := bson.M{"$set": bson.M{"role": role}}
+	result, err := db.membersCollection.UpdateOne(db.ctx, filter, update)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to update household member: %s", err.Error())
+		return
+	}
+	if result.MatchedCount == 0 {
+		statusCode = http.StatusNotFound
+		err = fmt.Errorf("failed to update household member: %w", ErrHouseholdMemberNotFound)
+		return
+	}
+	db.logger.Info("update household member successfully", zap.String("household_id", householdID), zap.String("role", role))
+	return http.StatusOK, nil
+}
+
+// DeleteHouseholdMember removes the member (or invitation) of household together with the price alerts
+// which the member defined for the household
+func (db Mongo) DeleteHouseholdMember(householdID, userID string) (statusCode int, err error) {
+	result, err := db.membersCollection.DeleteOne(db.ctx, bson.M{"household_id": householdID, "user_id": userID})
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to delete household member: %s", err.Error())
+		return
+	}
+	if result.DeletedCount == 0 {
+		statusCode = http.StatusNotFound
+		err = fmt.Errorf("failed to delete household member: %w", ErrHouseholdMemberNotFound)
+		return
+	}
+	if _, err = db.priceAlertsCollection.DeleteMany(db.ctx, bson.M{"household_id": householdID, "user_id": userID}); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to delete price alerts of household member: %s", err.Error())
+		return
+	}
+	db.logger.Info("delete household member successfully", zap.String("household_id", householdID))
+	return http.StatusOK, nil
+}
+
+// getSharedHouseholds retrieves the households of other users which user is an accepted member of, with the role of user in them
+func (db Mongo) getSharedHouseholds(userID string) ([]models.Household, error) {
+	filter := bson.M{"user_id": userID, "status": models.HOUSEHOLD_MEMBER_ACCEPTED}
+	cursor, err := db.membersCollection.Find(db.ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared households: %s", err.Error())
+	}
+	memberships := make([]models.HouseholdMember, 0)
+	if err = cursor.All(db.ctx, &memberships); err != nil {
+		return nil, fmt.Errorf("failed to cursor all shared households: %s", err.Error())
+	}
+
+	households := make([]models.Household, 0, len(memberships))
+	for _, membership := range memberships {
+		household := defaultHousehold(membership.OwnerID)
+		if membership.HouseholdID != membership.OwnerID {
+			err = db.householdsCollection.FindOne(db.ctx, bson.M{"_id": membership.HouseholdID}).Decode(&household)
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				// household was deleted by its owner
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to get shared household: %s", err.Error())
+			}
+		}
+		household.IsDefault = false
+		household.Role = membership.Role
+		households = append(households, household)
+	}
+	return households, nil
+}
@@ -0,0 +1,119 @@
+// AnhCao 2024
+package db
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/AnhCaooo/go-goods/log"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestGetAccessibleHousehold(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+
+	noDocument := mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch)
+	cottage := mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch, bson.D{
+		{Key: "_id", Value: "6759a8f1c2a4b5e3f1d2c3b4"},
+		{Key: "user_id", Value: "99999"},
+		{Key: "name", Value: "Summer cottage"},
+	})
+
+	tests := []struct {
+		name               string
+		userID             string
+		householdID        string
+		mockResponses      []bson.D
+		expectedID         string
+		expectedRole       string
+		expectedStatusCode int
+	}{
+		{
+			name:               "default household of user",
+			userID:             "12345",
+			householdID:        "12345",
+			mockResponses:      nil,
+			expectedID:         "12345",
+			expectedRole:       models.HOUSEHOLD_ROLE_OWNER,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "own household",
+			userID:             "99999",
+			householdID:        "6759a8f1c2a4b5e3f1d2c3b4",
+			mockResponses:      []bson.D{cottage},
+			expectedID:         "6759a8f1c2a4b5e3f1d2c3b4",
+			expectedRole:       models.HOUSEHOLD_ROLE_OWNER,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:        "shared household",
+			userID:      "12345",
+			householdID: "6759a8f1c2a4b5e3f1d2c3b4",
+			mockResponses: []bson.D{cottage, mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch, bson.D{
+				{Key: "household_id", Value: "6759a8f1c2a4b5e3f1d2c3b4"},
+				{Key: "owner_id", Value: "99999"},
+				{Key: "user_id", Value: "12345"},
+				{Key: "role", Value: models.HOUSEHOLD_ROLE_EDITOR},
+				{Key: "status", Value: models.HOUSEHOLD_MEMBER_ACCEPTED},
+			})},
+			expectedID:         "6759a8f1c2a4b5e3f1d2c3b4",
+			expectedRole:       models.HOUSEHOLD_ROLE_EDITOR,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:        "shared default household of other user",
+			userID:      "12345",
+			householdID: "99999",
+			mockResponses: []bson.D{noDocument, mtest.CreateCursorResponse(0, "db.coll", mtest.FirstBatch, bson.D{
+				{Key: "household_id", Value: "99999"},
+				{Key: "owner_id", Value: "99999"},
+				{Key: "user_id", Value: "12345"},
+				{Key: "role", Value: models.HOUSEHOLD_ROLE_VIEWER},
+				{Key: "status", Value: models.HOUSEHOLD_MEMBER_ACCEPTED},
+			})},
+			expectedID:         "99999",
+			expectedRole:       models.HOUSEHOLD_ROLE_VIEWER,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "household of other user which is not shared",
+			userID:             "12345",
+			householdID:        "6759a8f1c2a4b5e3f1d2c3b4",
+			mockResponses:      []bson.D{cottage, noDocument},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.householdsCollection = mt.Coll
+			db.membersCollection = mt.Coll
+
+			if test.mockResponses != nil {
+				mt.AddMockResponses(test.mockResponses...)
+			}
+			household, statusCode, err := db.GetAccessibleHousehold(test.userID, test.householdID)
+
+			if statusCode != test.expectedStatusCode {
+				t.Fatalf("unexpected status code: got %d, want %d (error: %v)", statusCode, test.expectedStatusCode, err)
+			}
+			if test.expectedStatusCode != http.StatusOK {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if household.ID != test.expectedID || household.Role != test.expectedRole {
+				t.Errorf("unexpected household: got %s with role %s, want %s with role %s", household.ID, household.Role, test.expectedID, test.expectedRole)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// AnhCao 2024
+package db
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// GetHouseholds retrieves all households of user. The default household comes first,
+// followed by the other households of user and the households which are shared with user.
+func (db Mongo) GetHouseholds(userID string) (households []models.Household, statusCode int, err error) {
+	if userID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot get households from unauthenticated user")
+		return
+	}
+
+	cursor, err := db.householdsCollection.Find(db.ctx, bson.M{"user_id": userID})
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to get households: %s", err.Error())
+		return
+	}
+
+	stored := make([]models.Household, 0)
+	if err = cursor.All(db.ctx, &stored); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to cursor all households: %s", err.Error())
+		return nil, statusCode, err
+	}
+	for i := range stored {
+		stored[i].Role = models.HOUSEHOLD_ROLE_OWNER
+	}
+	shared, err := db.getSharedHouseholds(userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	households = append([]models.Household{defaultHousehold(userID)}, stored...)
+	households = append(households, shared...)
+	db.logger.Info("get households successfully", zap.Int("amount", len(households)))
+	return households, http.StatusOK, nil
+}
+
+// GetHousehold retrieves a single household of user by its id.
+// The default household (same id as user) is never stored, so it is always found.
+func (db Mongo) GetHousehold(userID, householdID string) (household *models.Household, statusCode int, err error) {
+	if userID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot get household from unauthenticated user")
+		return
+	}
+	if householdID == userID {
+		household := defaultHousehold(userID)
+		return &household, http.StatusOK, nil
+	}
+
+	household = &models.Household{}
+	filter := bson.M{"_id": householdID, "user_id": userID}
+	if err = db.householdsCollection.FindOne(db.ctx, filter).Decode(household); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, http.StatusNotFound, fmt.Errorf("failed to get household: %w", ErrHouseholdNotFound)
+		}
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get household: %s", err.Error())
+	}
+	household.Role = models.HOUSEHOLD_ROLE_OWNER
+	db.logger.Debug("get household successfully")
+	return household, http.StatusOK, nil
+}
+
+// InsertHousehold inserts a new household of user and returns it with generated id
+func (db Mongo) InsertHousehold(household models.Household) (inserted *models.Household, statusCode int, err error) {
+	if household.UserID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot insert un-authenticated document")
+		return
+	}
+
+	household.ID = primitive.NewObjectID().Hex()
+	household.IsDefault = false
+	if _, err = db.householdsCollection.InsertOne(db.ctx, household); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to insert household: %s", err.Error())
+		return
+	}
+	db.logger.Info("create new household successfully")
+	return &household, http.StatusCreated, nil
+}
+
+// UpdateHousehold replaces the household of user. The default household cannot be changed.
+func (db Mongo) UpdateHousehold(householdID string, household models.Household) (statusCode int, err error) {
+	if household.UserID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot update un-authenticated document")
+		return
+	}
+	if householdID == household.UserID {
+		statusCode = http.StatusBadRequest
+		err = fmt.Errorf("failed to update household: %w", ErrDefaultHouseholdChanged)
+		return
+	}
+
+	household.ID = householdID
+	filter := bson.M{"_id": householdID, "user_id": household.UserID}
+	result, err := db.householdsCollection.ReplaceOne(db.ctx, filter, household)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to update household: %s", err.Error())
+		return
+	}
+	if result.MatchedCount == 0 {
+		statusCode = http.StatusNotFound
+		err = fmt.Errorf("failed to update household: %w", ErrHouseholdNotFound)
+		return
+	}
+	db.logger.Info("update household successfully")
+	return http.StatusOK, nil
+}
+
+// DeleteHousehold deletes the household of user together with its price settings, settings history, consumption, price alerts,
+// calendar feeds and members.
+// The default household cannot be deleted.
+func (db Mongo) DeleteHousehold(userID, householdID string) (statusCode int, err error) {
+	if userID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot delete household of unauthenticated user")
+		return
+	}
+	if householdID == userID {
+		statusCode = http.StatusBadRequest
+		err = fmt.Errorf("failed to delete household: %w", ErrDefaultHouseholdDeleted)
+		return
+	}
+
+	result, err := db.householdsCollection.DeleteOne(db.ctx, bson.M{"_id": householdID, "user_id": userID})
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to delete household: %s", err.Error())
+		return
+	}
+	if result.DeletedCount == 0 {
+		statusCode = http.StatusNotFound
+		err = fmt.Errorf("failed to delete household: %w", ErrHouseholdNotFound)
+		return
+	}
+
+	if err = db.deleteHouseholdData(bson.M{"household_id": householdID}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if _, err = db.membersCollection.DeleteMany(db.ctx, bson.M{"household_id": householdID}); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to delete household members: %s", err.Error())
+	}
+	db.logger.Info("delete household successfully", zap.String("household_id", householdID))
+	return http.StatusOK, nil
+}
+
+// deleteHouseholdData deletes the price settings, settings history, consumption, price alerts, calendar feeds and budget alerts which match the filter
+func (db Mongo) deleteHouseholdData(filter bson.M) error {
+	for _, collection := range []*mongo.Collection{
+		db.collection,
+		db.historyCollection,
+		db.consumptionCollection,
+		db.priceAlertsCollection,
+		db.calendarCollection,
+		db.budgetAlertsCollection,
+	} {
+		if _, err := collection.DeleteMany(db.ctx, filter); err != nil {
+			return fmt.Errorf("failed to delete household data from %s: %s", collection.Name(), err.Error())
+		}
+	}
+	return nil
+}
+
+// defaultHousehold returns the household which every user has from the beginning
+func defaultHousehold(userID string) models.Household {
+	return models.Household{
+		ID:        userID,
+		UserID:    userID,
+		Name:      models.DEFAULT_HOUSEHOLD_NAME,
+		IsDefault: true,
+		Role:      models.HOUSEHOLD_ROLE_OWNER,
+	}
+}
@@ -0,0 +1,175 @@
+// AnhCao 2024
+package db
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/AnhCaooo/go-goods/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestDeleteHousehold(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+
+	deleted := func(n int) bson.D {
+		return bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "n", Value: n},
+		}
+	}
+
+	tests := []struct {
+		name               string
+		userID             string
+		householdID        string
+		mockResponses      []bson.D
+		expectedStatusCode int
+		expectedError      string
+	}{
+		{
+			name:        "successful deletion together with household data",
+			userID:      "12345",
+			householdID: "6759a8f1c2a4b5e3f1d2c3b4",
+			// household, then its price settings, history, consumption, price alerts, calendar feeds, budget alerts and members
+			mockResponses:      []bson.D{deleted(1), deleted(1), deleted(2), deleted(24), deleted(1), deleted(1), deleted(1), deleted(2)},
+			expectedStatusCode: http.StatusOK,
+			expectedError:      "",
+		},
+		{
+			name:               "empty user ID",
+			userID:             "",
+			householdID:        "6759a8f1c2a4b5e3f1d2c3b4",
+			mockResponses:      nil,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      "cannot delete household of unauthenticated user",
+		},
+		{
+			name:               "default household cannot be deleted",
+			userID:             "12345",
+			householdID:        "12345",
+			mockResponses:      nil,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "failed to delete household: default household cannot be deleted",
+		},
+		{
+			name:               "no matched documents: household of other user",
+			userID:             "99999",
+			householdID:        "6759a8f1c2a4b5e3f1d2c3b4",
+			mockResponses:      []bson.D{deleted(0)},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError:      "failed to delete household: no matched household was found",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.collection = mt.Coll
+			db.historyCollection = mt.Coll
+			db.consumptionCollection = mt.Coll
+			db.priceAlertsCollection = mt.Coll
+			db.householdsCollection = mt.Coll
+			db.membersCollection = mt.Coll
+			db.calendarCollection = mt.Coll
+			db.budgetAlertsCollection = mt.Coll
+
+			if test.mockResponses != nil {
+				mt.AddMockResponses(test.mockResponses...)
+			}
+			statusCode, err := db.DeleteHousehold(test.userID, test.householdID)
+
+			// Validate error
+			if test.expectedError != "" {
+				if err == nil {
+					t.Errorf("expected error %q, got nil", test.expectedError)
+				} else if err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %q, want %q", err.Error(), test.expectedError)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			// Validate status code
+			if statusCode != test.expectedStatusCode {
+				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
+			}
+		})
+	}
+}
+
+func TestGetHousehold(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+
+	tests := []struct {
+		name               string
+		mockResponses      []bson.D
+		expectedStatusCode int
+		expectedError      string
+		expectedNotFound   bool
+	}{
+		{
+			name: "successful get",
+			mockResponses: []bson.D{
+				mtest.CreateCursorResponse(0, "test.households", mtest.FirstBatch, bson.D{
+					{Key: "_id", Value: "6759a8f1c2a4b5e3f1d2c3b4"},
+					{Key: "user_id", Value: "12345"},
+					{Key: "name", Value: "Summer cottage"},
+				}),
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "no matched household",
+			mockResponses:      []bson.D{mtest.CreateCursorResponse(0, "test.households", mtest.FirstBatch)},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError:      "failed to get household: no matched household was found",
+			expectedNotFound:   true,
+		},
+		{
+			name: "database failure is not reported as not found",
+			mockResponses: []bson.D{
+				mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 12345, Message: "some database error"}),
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedError:      "failed to get household: some database error",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.householdsCollection = mt.Coll
+			mt.AddMockResponses(test.mockResponses...)
+
+			_, statusCode, err := db.GetHousehold("12345", "6759a8f1c2a4b5e3f1d2c3b4")
+
+			// Validate error
+			if test.expectedError != "" {
+				if err == nil {
+					t.Errorf("expected error %q, got nil", test.expectedError)
+				} else if err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %q, want %q", err.Error(), test.expectedError)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if errors.Is(err, ErrHouseholdNotFound) != test.expectedNotFound {
+				t.Errorf("unexpected failure: got %v, want ErrHouseholdNotFound %v", err, test.expectedNotFound)
+			}
+
+			// Validate status code
+			if statusCode != test.expectedStatusCode {
+				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
+			}
+		})
+	}
+}
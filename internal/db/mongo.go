@@ -3,22 +3,62 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"time"
 
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
 	"github.com/AnhCaooo/stormbreaker/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
+const (
+	CONSUMPTION_COLLECTION  string = "consumption"
+	PRICE_ALERTS_COLLECTION string = "price_alerts"
+	// notification preferences are stored next to price settings, one document per user
+	NOTIFICATION_PREFERENCES_COLLECTION string = "notification_preferences"
+	PRICE_SETTINGS_HISTORY_COLLECTION   string = "price_settings_history"
+	HOUSEHOLDS_COLLECTION               string = "households"
+	HOUSEHOLD_MEMBERS_COLLECTION        string = "household_members"
+	CALENDAR_FEEDS_COLLECTION           string = "calendar_feeds"
+	SCHEDULED_NOTIFICATIONS_COLLECTION  string = "scheduled_notifications"
+	BUDGET_ALERTS_COLLECTION            string = "budget_alerts"
+)
+
+// storedPriceSettings is the document of price settings together with its id, whose timestamp is the time when the settings were created
+type storedPriceSettings struct {
+	ID                   primitive.ObjectID `bson:"_id"`
+	models.PriceSettings `bson:",inline"`
+}
+
 type Mongo struct {
-	config     *models.Database
-	logger     *zap.Logger
-	ctx        context.Context
-	Client     *mongo.Client
-	collection *mongo.Collection
+	config                *models.Database
+	logger                *zap.Logger
+	ctx                   context.Context
+	Client                *mongo.Client
+	collection            *mongo.Collection
+	consumptionCollection *mongo.Collection
+	priceAlertsCollection *mongo.Collection
+	// notificationCollection stores the notification preferences of users
+	notificationCollection *mongo.Collection
+	// historyCollection stores every change of price settings with the time from which it is in force
+	historyCollection *mongo.Collection
+	// householdsCollection stores the households of users except their default household
+	householdsCollection *mongo.Collection
+	// membersCollection stores the invitations and memberships of shared households
+	membersCollection *mongo.Collection
+	// calendarCollection stores the subscriptions of users to the calendar feed of households
+	calendarCollection *mongo.Collection
+	// scheduledCollection stores the messages which wait for the delivery time that users prefer
+	scheduledCollection *mongo.Collection
+	// budgetAlertsCollection stores the months in which households were warned about their budget
+	budgetAlertsCollection *mongo.Collection
 }
 
 func NewMongo(ctx context.Context, config *models.Database, logger *zap.Logger) *Mongo {
@@ -29,9 +69,42 @@ func NewMongo(ctx context.Context, config *models.Database, logger *zap.Logger)
 	}
 }
 
+// WithLogger returns a copy of database which writes its logs with given logger (ex: with the id of request).
+// The copy shares the connection and collections.
+func (db *Mongo) WithLogger(logger *zap.Logger) *Mongo {
+	if db == nil {
+		return nil
+	}
+	scoped := *db
+	scoped.logger = logger
+	return &scoped
+}
+
+// WithContext returns a copy of database whose operations belong to given context (ex: the span of request).
+// The operations are not cancelled with the context, so that they finish like before when client disconnects.
+func (db *Mongo) WithContext(ctx context.Context) *Mongo {
+	if db == nil {
+		return nil
+	}
+	scoped := *db
+	scoped.ctx = context.WithoutCancel(ctx)
+	return &scoped
+}
+
+// Ping checks that the database server is reachable
+func (db *Mongo) Ping(ctx context.Context) error {
+	if db.Client == nil {
+		return errors.New("database connection is not established")
+	}
+	if err := db.Client.Ping(ctx, nil); err != nil {
+		return fmt.Errorf("failed to ping database: %s", err.Error())
+	}
+	return nil
+}
+
 // EstablishConnection tries to connect to mongo server and create collection if it does not exist
 func (db *Mongo) EstablishConnection() (err error) {
-	clientOptions := options.Client().ApplyURI(db.getURI())
+	clientOptions := options.Client().ApplyURI(db.getURI()).SetMonitor(commandMonitor())
 	db.Client, err = mongo.Connect(db.ctx, clientOptions)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %s", err.Error())
@@ -51,10 +124,23 @@ func (db *Mongo) EstablishConnection() (err error) {
 
 func (db *Mongo) initializeCollection() error {
 	db.collection = db.Client.Database(db.config.Name).Collection(db.config.Collection)
+	db.consumptionCollection = db.Client.Database(db.config.Name).Collection(CONSUMPTION_COLLECTION)
+	db.priceAlertsCollection = db.Client.Database(db.config.Name).Collection(PRICE_ALERTS_COLLECTION)
+	db.notificationCollection = db.Client.Database(db.config.Name).Collection(NOTIFICATION_PREFERENCES_COLLECTION)
+	db.historyCollection = db.Client.Database(db.config.Name).Collection(PRICE_SETTINGS_HISTORY_COLLECTION)
+	db.householdsCollection = db.Client.Database(db.config.Name).Collection(HOUSEHOLDS_COLLECTION)
+	db.membersCollection = db.Client.Database(db.config.Name).Collection(HOUSEHOLD_MEMBERS_COLLECTION)
+	db.calendarCollection = db.Client.Database(db.config.Name).Collection(CALENDAR_FEEDS_COLLECTION)
+	db.scheduledCollection = db.Client.Database(db.config.Name).Collection(SCHEDULED_NOTIFICATIONS_COLLECTION)
+	db.budgetAlertsCollection = db.Client.Database(db.config.Name).Collection(BUDGET_ALERTS_COLLECTION)
+
+	if err := db.migrateToHouseholds(); err != nil {
+		return err
+	}
 
 	// Ensure unique index (only needs to be done once)
 	indexModel := mongo.IndexModel{
-		Keys: bson.M{"user_id": 1}, // Unique on "user_id" field
+		Keys: bson.M{"household_id": 1}, // Unique on "household_id" field
 		Options: options.Index().
 			SetUnique(true),
 	}
@@ -64,6 +150,186 @@ func (db *Mongo) initializeCollection() error {
 		return fmt.Errorf("failed to create index while initialize collection: %s", err.Error())
 	}
 
+	// One consumption value per household per time slot
+	consumptionIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "household_id", Value: 1},
+			{Key: "time", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true),
+	}
+	if _, err = db.consumptionCollection.Indexes().CreateOne(db.ctx, consumptionIndexModel); err != nil {
+		return fmt.Errorf("failed to create index while initialize consumption collection: %s", err.Error())
+	}
+
+	priceAlertsIndexModels := []mongo.IndexModel{
+		{Keys: bson.M{"user_id": 1}},
+		{Keys: bson.M{"household_id": 1}},
+	}
+	if _, err = db.priceAlertsCollection.Indexes().CreateMany(db.ctx, priceAlertsIndexModels); err != nil {
+		return fmt.Errorf("failed to create index while initialize price alerts collection: %s", err.Error())
+	}
+
+	notificationIndexModel := mongo.IndexModel{
+		Keys: bson.M{"user_id": 1},
+		Options: options.Index().
+			SetUnique(true),
+	}
+	if _, err = db.notificationCollection.Indexes().CreateOne(db.ctx, notificationIndexModel); err != nil {
+		return fmt.Errorf("failed to create index while initialize notification preferences collection: %s", err.Error())
+	}
+
+	historyIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "household_id", Value: 1},
+			{Key: "effective_from", Value: 1},
+		},
+	}
+	if _, err = db.historyCollection.Indexes().CreateOne(db.ctx, historyIndexModel); err != nil {
+		return fmt.Errorf("failed to create index while initialize price settings history collection: %s", err.Error())
+	}
+	// One entry per version of household's price settings. Entries which were recorded before settings had versions are left out.
+	historyVersionIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "household_id", Value: 1},
+			{Key: "version", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"version": bson.M{"$gt": 0}}),
+	}
+	if _, err = db.historyCollection.Indexes().CreateOne(db.ctx, historyVersionIndexModel); err != nil {
+		return fmt.Errorf("failed to create index while initialize price settings history collection: %s", err.Error())
+	}
+
+	householdsIndexModel := mongo.IndexModel{
+		Keys: bson.M{"user_id": 1},
+	}
+	if _, err = db.householdsCollection.Indexes().CreateOne(db.ctx, householdsIndexModel); err != nil {
+		return fmt.Errorf("failed to create index while initialize households collection: %s", err.Error())
+	}
+
+	// One invitation per user per household
+	membersIndexModels := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "household_id", Value: 1},
+				{Key: "user_id", Value: 1},
+			},
+			Options: options.Index().
+				SetUnique(true),
+		},
+		{Keys: bson.M{"user_id": 1}},
+	}
+	if _, err = db.membersCollection.Indexes().CreateMany(db.ctx, membersIndexModels); err != nil {
+		return fmt.Errorf("failed to create index while initialize household members collection: %s", err.Error())
+	}
+
+	// One feed per user per household, found by the hash of its token
+	calendarIndexModels := []mongo.IndexModel{
+		{
+			Keys: bson.M{"token_hash": 1},
+			Options: options.Index().
+				SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "household_id", Value: 1},
+			},
+			Options: options.Index().
+				SetUnique(true),
+		},
+	}
+	if _, err = db.calendarCollection.Indexes().CreateMany(db.ctx, calendarIndexModels); err != nil {
+		return fmt.Errorf("failed to create index while initialize calendar feeds collection: %s", err.Error())
+	}
+
+	// One scheduled message per user per household per kind of message, found by its delivery time
+	scheduledIndexModels := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "household_id", Value: 1},
+				{Key: "routing_key", Value: 1},
+			},
+			Options: options.Index().
+				SetUnique(true),
+		},
+		{Keys: bson.M{"deliver_at": 1}},
+	}
+	if _, err = db.scheduledCollection.Indexes().CreateMany(db.ctx, scheduledIndexModels); err != nil {
+		return fmt.Errorf("failed to create index while initialize scheduled notifications collection: %s", err.Error())
+	}
+
+	// One budget alert per household per month
+	budgetAlertsIndexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "household_id", Value: 1},
+			{Key: "month", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true),
+	}
+	if _, err = db.budgetAlertsCollection.Indexes().CreateOne(db.ctx, budgetAlertsIndexModel); err != nil {
+		return fmt.Errorf("failed to create index while initialize budget alerts collection: %s", err.Error())
+	}
+
+	return nil
+}
+
+// migrateToHouseholds moves the data which was stored before households existed into the default household of its user
+// (household id is the user id), then drops the indexes which allowed only one household per user.
+// It only touches documents without household, so it is safe to run at every start.
+func (db *Mongo) migrateToHouseholds() error {
+	toDefaultHousehold := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{{Key: "household_id", Value: "$user_id"}}}},
+	}
+	filter := bson.M{"household_id": bson.M{"$exists": false}}
+	for _, collection := range []*mongo.Collection{
+		db.collection,
+		db.consumptionCollection,
+		db.priceAlertsCollection,
+		db.historyCollection,
+	} {
+		result, err := collection.UpdateMany(db.ctx, filter, toDefaultHousehold)
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s to households: %s", collection.Name(), err.Error())
+		}
+		if result.ModifiedCount > 0 {
+			db.logger.Info("migrated documents to default households", zap.String("collection", collection.Name()), zap.Int64("amount", result.ModifiedCount))
+		}
+	}
+
+	staleIndexes := map[*mongo.Collection]string{
+		db.collection:            "user_id_1",
+		db.consumptionCollection: "user_id_1_time_1",
+		db.historyCollection:     "user_id_1_effective_from_1",
+	}
+	for collection, name := range staleIndexes {
+		if err := db.dropIndexIfExists(collection, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropIndexIfExists drops the index with given name if the collection has it
+func (db *Mongo) dropIndexIfExists(collection *mongo.Collection, name string) error {
+	specifications, err := collection.Indexes().ListSpecifications(db.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list indexes of %s: %s", collection.Name(), err.Error())
+	}
+	for _, specification := range specifications {
+		if specification.Name != name {
+			continue
+		}
+		if _, err = collection.Indexes().DropOne(db.ctx, name); err != nil {
+			return fmt.Errorf("failed to drop index %s of %s: %s", name, collection.Name(), err.Error())
+		}
+		db.logger.Info("dropped stale index", zap.String("collection", collection.Name()), zap.String("index", name))
+	}
 	return nil
 }
 
@@ -72,38 +338,46 @@ func (db Mongo) getURI() string {
 	return fmt.Sprintf("mongodb://%s:%s@%s:%s/?timeoutMS=5000", db.config.Username, db.config.Password, db.config.Host, db.config.Port)
 }
 
-// GetPriceSettings retrieves a document by UserID
-func (db Mongo) GetPriceSettings(userID string) (settings *models.PriceSettings, statusCode int, err error) {
-	if userID == "" {
+// GetPriceSettings retrieves the price settings of household
+func (db Mongo) GetPriceSettings(householdID string) (settings *models.PriceSettings, statusCode int, err error) {
+	if householdID == "" {
 		statusCode = http.StatusUnauthorized
 		err = fmt.Errorf("cannot get price settings from unauthenticated user")
 		return
 	}
 	settings = &models.PriceSettings{}
-	filter := bson.M{"user_id": userID}
+	filter := bson.M{"household_id": householdID}
 	if err = db.collection.FindOne(db.ctx, filter).Decode(settings); err != nil {
-		settings = nil
-		statusCode = http.StatusNotFound
-		err = fmt.Errorf("failed to get price settings: %s", err.Error())
-		return
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, http.StatusNotFound, fmt.Errorf("failed to get price settings: %w", ErrPriceSettingsNotFound)
+		}
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get price settings: %s", err.Error())
 	}
 	db.logger.Info("get price settings successfully")
 	return settings, http.StatusOK, nil
 }
 
 // InsertPriceSettings inserts a new document into the PriceSettings collection.
+// Settings without household belong to the default household of user.
 func (db Mongo) InsertPriceSettings(settings models.PriceSettings) (statusCode int, err error) {
 	if settings.UserID == "" {
 		statusCode = http.StatusUnauthorized
 		err = fmt.Errorf("cannot insert un-authenticated document")
 		return
 	}
+	if settings.HouseholdID == "" {
+		settings.HouseholdID = settings.UserID
+	}
+	if settings.Version, err = db.nextPriceSettingsVersion(settings.HouseholdID); err != nil {
+		statusCode = http.StatusInternalServerError
+		return
+	}
 
 	_, err = db.collection.InsertOne(db.ctx, settings)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
 			statusCode = http.StatusConflict
-			err = fmt.Errorf("failed to insert: document already exists")
+			err = fmt.Errorf("failed to insert price settings: %w", ErrPriceSettingsExist)
 			return
 		} else {
 			statusCode = http.StatusInternalServerError
@@ -112,79 +386,428 @@ func (db Mongo) InsertPriceSettings(settings models.PriceSettings) (statusCode i
 		}
 	}
 
+	if statusCode, err = db.recordPriceSettingsChange(nil, time.Time{}, models.PriceSettingsHistory{PriceSettings: settings}); err != nil {
+		return
+	}
+
 	db.logger.Info("create new price settings successfully")
 	return http.StatusCreated, err
 }
 
-// PatchPriceSettings updates partial data for user's price settings.
-func (db Mongo) PatchPriceSettings(settings models.PriceSettings) (statusCode int, err error) {
-	if settings.UserID == "" {
+// PatchPriceSettings changes only the fields of household's price settings which are present in the patch, and returns the changed settings.
+// When `expectedVersion` is given, the settings are changed only if nobody changed them since client read that version.
+// Otherwise 412 is returned, so that client reloads the settings instead of overwriting the other change.
+func (db Mongo) PatchPriceSettings(ownerID, householdID string, patch models.PriceSettingsPatch, expectedVersion *int64) (updated *models.PriceSettings, statusCode int, err error) {
+	if ownerID == "" {
 		statusCode = http.StatusUnauthorized
 		err = fmt.Errorf("cannot insert un-authenticated document")
 		return
 	}
+	if householdID == "" {
+		householdID = ownerID
+	}
 
-	filter := bson.M{"user_id": settings.UserID}
+	changes := bson.M{}
+	if patch.VatIncluded != nil {
+		changes["vat_included"] = *patch.VatIncluded
+	}
+	if patch.Marginal != nil {
+		changes["margin"] = *patch.Marginal
+	}
+	if patch.MonthlyBudget != nil {
+		changes["monthly_budget"] = *patch.MonthlyBudget
+	}
+	if len(changes) == 0 {
+		statusCode = http.StatusBadRequest
+		err = fmt.Errorf("failed to update price settings: %w", ErrPriceSettingsUnchanged)
+		return
+	}
+
+	filter := bson.M{"household_id": householdID, "user_id": ownerID}
+	if expectedVersion != nil {
+		filter["version"] = *expectedVersion
+		if *expectedVersion == 0 {
+			// settings stored before versioning have no version yet
+			filter["version"] = bson.M{"$in": bson.A{0, nil}}
+		}
+	}
 	updates := bson.M{
-		"$set": bson.M{
-			"vat_included": settings.VatIncluded,
-			"margin":       settings.Marginal,
-		},
+		"$set": changes,
+		"$inc": bson.M{"version": 1},
 	}
-	result, err := db.collection.UpdateOne(db.ctx, filter, updates)
-	if err != nil {
+	// keep the settings before update to record the change
+	var stored storedPriceSettings
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	if err = db.collection.FindOneAndUpdate(db.ctx, filter, updates, opts).Decode(&stored); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			statusCode, err = db.patchPriceSettingsMiss(ownerID, householdID, expectedVersion)
+			return
+		}
 		statusCode = http.StatusInternalServerError
 		err = fmt.Errorf("failed to update price settings: %s", err.Error())
 		return
 	}
-	if result.MatchedCount == 0 {
-		statusCode = http.StatusNotFound
-		err = fmt.Errorf("failed to update price settings: no matched settings were found")
+
+	previous := stored.PriceSettings
+	current := helpers.ApplyPriceSettingsPatch(previous, patch)
+	current.Version = previous.Version + 1
+	if statusCode, err = db.recordPriceSettingsChange(&previous, stored.ID.Timestamp(), models.PriceSettingsHistory{PriceSettings: current}); err != nil {
 		return
 	}
-	db.logger.Info("update price settings successfully", zap.Any("updated_amount", result.MatchedCount))
-	return http.StatusOK, nil
+	db.logger.Info("update price settings successfully", zap.Int64("version", current.Version))
+	return &current, http.StatusOK, nil
 }
 
-// DeletePriceSettings deletes user's price settings.
-func (db Mongo) DeletePriceSettings(userID string) (statusCode int, err error) {
-	if userID == "" {
+// patchPriceSettingsMiss explains why no price settings matched the patch: either they do not exist,
+// or another request changed them after the version which client expects.
+func (db Mongo) patchPriceSettingsMiss(ownerID, householdID string, expectedVersion *int64) (statusCode int, err error) {
+	if expectedVersion != nil {
+		count, err := db.collection.CountDocuments(db.ctx, bson.M{"household_id": householdID, "user_id": ownerID})
+		if err != nil {
+			return http.StatusInternalServerError, fmt.Errorf("failed to update price settings: %s", err.Error())
+		}
+		if count > 0 {
+			return http.StatusPreconditionFailed, fmt.Errorf("failed to update price settings: settings were changed after version %d, reload them and try again", *expectedVersion)
+		}
+	}
+	return http.StatusNotFound, fmt.Errorf("failed to update price settings: %w", ErrPriceSettingsNotFound)
+}
+
+// DeletePriceSettings deletes the price settings of household. The deletion is recorded in history,
+// so that the default price settings are in force from now on until the settings are created again.
+func (db Mongo) DeletePriceSettings(householdID string) (statusCode int, err error) {
+	if householdID == "" {
 		statusCode = http.StatusUnauthorized
 		err = fmt.Errorf("cannot get price settings from unauthenticated user")
 		return
 	}
-	filter := bson.M{"user_id": userID}
-	db.logger.Info("deleting price settings", zap.String("user_id", userID))
+	filter := bson.M{"household_id": householdID}
+	db.logger.Info("deleting price settings", zap.String("household_id", householdID))
 
-	result, err := db.collection.DeleteOne(db.ctx, filter)
-	if err != nil {
+	// keep the deleted settings to record the deletion
+	var stored storedPriceSettings
+	if err = db.collection.FindOneAndDelete(db.ctx, filter).Decode(&stored); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			statusCode = http.StatusNotFound
+			err = fmt.Errorf("failed to delete price settings: %w", ErrPriceSettingsNotFound)
+			return
+		}
 		statusCode = http.StatusInternalServerError
 		err = fmt.Errorf("failed to delete price settings: %s", err.Error())
 		return
 	}
-	if result.DeletedCount == 0 {
-		statusCode = http.StatusNotFound
-		err = fmt.Errorf("failed to delete price settings: no matched settings were found")
+
+	previous := stored.PriceSettings
+	deletion := models.PriceSettingsHistory{
+		PriceSettings: models.PriceSettings{
+			UserID:      previous.UserID,
+			HouseholdID: previous.HouseholdID,
+			Version:     previous.Version + 1,
+		},
+		Deleted: true,
+	}
+	if statusCode, err = db.recordPriceSettingsChange(&previous, stored.ID.Timestamp(), deletion); err != nil {
 		return
 	}
-	db.logger.Info("delete user price settings successfully", zap.Int64("deleted_amount", result.DeletedCount))
+	db.logger.Info("delete user price settings successfully", zap.Int64("version", previous.Version))
 	return http.StatusOK, nil
 }
 
+// GetPriceSettingsWithBudget retrieves all price settings which have a monthly budget set.
+// Use case: scheduler checks whether the projected bill of users crosses their budget.
+func (db Mongo) GetPriceSettingsWithBudget() (settings []models.PriceSettings, err error) {
+	filter := bson.M{"monthly_budget": bson.M{"$gt": 0}}
+	cursor, err := db.collection.Find(db.ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price settings with budget: %s", err.Error())
+	}
+
+	settings = make([]models.PriceSettings, 0)
+	if err = cursor.All(db.ctx, &settings); err != nil {
+		return nil, fmt.Errorf("failed to cursor all price settings with budget: %s", err.Error())
+	}
+	db.logger.Info("get price settings with budget successfully", zap.Int("amount", len(settings)))
+	return settings, nil
+}
+
 // GetAllPriceSettings retrieves all documents in the PriceSettings collection.
-// Use case: Admin wants to see all price settings.
-func (db Mongo) GetAllPriceSettings() error {
-	// Retrieves documents that match the filter and prints them as structs
-	cursor, err := db.collection.Find(context.TODO(), bson.D{})
+// Use case: Scheduler delivers personalized messages to all users.
+func (db Mongo) GetAllPriceSettings() ([]models.PriceSettings, error) {
+	cursor, err := db.collection.Find(db.ctx, bson.D{})
 	if err != nil {
-		return fmt.Errorf("failed: %s", err.Error())
+		return nil, fmt.Errorf("failed to get all price settings: %s", err.Error())
 	}
 
-	var results []models.PriceSettings
+	results := make([]models.PriceSettings, 0)
 	if err = cursor.All(db.ctx, &results); err != nil {
-		return fmt.Errorf("failed to cursor all price settings: %s", err.Error())
+		return nil, fmt.Errorf("failed to cursor all price settings: %s", err.Error())
 	}
-	db.logger.Info("get all price settings successfully", zap.Any("results", results))
+	db.logger.Info("get all price settings successfully", zap.Int("amount", len(results)))
 
-	return nil
+	return results, nil
+}
+
+// ListPriceSettings retrieves the page of price settings of all users which match the filters of query, ordered by user and household.
+// Use case: Admin browses the price settings of users.
+func (db Mongo) ListPriceSettings(query models.PriceSettingsQuery) (page *models.PriceSettingsPage, statusCode int, err error) {
+	filter := priceSettingsFilter(query)
+	total, err := db.collection.CountDocuments(db.ctx, filter)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to count price settings: %s", err.Error())
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "user_id", Value: 1}, {Key: "household_id", Value: 1}}).
+		SetSkip(int64(query.Page-1) * int64(query.PageSize)).
+		SetLimit(int64(query.PageSize))
+	cursor, err := db.collection.Find(db.ctx, filter, opts)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to list price settings: %s", err.Error())
+		return
+	}
+
+	items := make([]models.PriceSettings, 0)
+	if err = cursor.All(db.ctx, &items); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to cursor price settings: %s", err.Error())
+		return
+	}
+	db.logger.Info("list price settings successfully", zap.Int("amount", len(items)), zap.Int64("total", total))
+	return &models.PriceSettingsPage{Items: items, Page: query.Page, PageSize: query.PageSize, Total: total}, http.StatusOK, nil
+}
+
+// SummarizePriceSettings counts the users and households which have price settings, and the distribution of their settings.
+// The settings are summarized by database, so that they are not loaded into memory.
+// Use case: Admin overviews the price settings of all users.
+func (db Mongo) SummarizePriceSettings() (summary *models.PriceSettingsSummary, statusCode int, err error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$facet", Value: bson.M{
+			"totals": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":          nil,
+					"households":   bson.M{"$sum": 1},
+					"vat_included": bson.M{"$sum": bson.M{"$cond": bson.A{"$vat_included", 1, 0}}},
+					"with_budget":  bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$monthly_budget", 0}}, 1, 0}}},
+					"with_margin":  bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$isNumber": "$margin"}, 1, 0}}},
+					"margin_total": bson.M{"$sum": "$margin"},
+					"margin_min":   bson.M{"$min": "$margin"},
+					"budget_total": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$monthly_budget", 0}}, "$monthly_budget", 0}}},
+				}},
+			},
+			"users": bson.A{
+				bson.M{"$group": bson.M{"_id": "$user_id"}},
+				bson.M{"$count": "count"},
+			},
+			"margins": bson.A{
+				// settings without margin are counted in their own bucket, instead of the default bucket
+				bson.M{"$match": bson.M{"margin": bson.M{"$type": "number"}}},
+				bucketStage("$margin", helpers.MarginBounds),
+			},
+			"budgets": bson.A{
+				bson.M{"$match": bson.M{"monthly_budget": bson.M{"$gt": 0}}},
+				bucketStage("$monthly_budget", helpers.BudgetBounds),
+			},
+		}}},
+	}
+	cursor, err := db.collection.Aggregate(db.ctx, pipeline)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to summarize price settings: %s", err.Error())
+		return
+	}
+
+	var facets []priceSettingsFacets
+	if err = cursor.All(db.ctx, &facets); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to cursor summary of price settings: %s", err.Error())
+		return
+	}
+
+	var result priceSettingsFacets
+	if len(facets) > 0 {
+		result = facets[0]
+	}
+	// margins may be negative, then the first bucket starts at the smallest margin
+	lowestMargin := 0.0
+	if len(result.Totals) > 0 && result.Totals[0].MarginMin != nil && *result.Totals[0].MarginMin < 0 {
+		lowestMargin = *result.Totals[0].MarginMin
+	}
+	summary = &models.PriceSettingsSummary{
+		MarginDistribution: append(
+			[]models.DistributionBucket{{Label: "none"}},
+			helpers.NewDistribution(lowestMargin, helpers.MarginBounds)...,
+		),
+		BudgetDistribution: append(
+			[]models.DistributionBucket{{Label: "none"}},
+			helpers.NewDistribution(0, helpers.BudgetBounds)...,
+		),
+	}
+	if len(result.Users) > 0 {
+		summary.Users = result.Users[0].Count
+	}
+	withMargin := 0
+	if len(result.Totals) > 0 {
+		totals := result.Totals[0]
+		summary.Households = totals.Households
+		summary.VatIncluded = totals.VatIncluded
+		summary.WithBudget = totals.WithBudget
+		withMargin = totals.WithMargin
+		if totals.WithMargin > 0 {
+			summary.AverageMargin = math.Round(totals.MarginTotal/float64(totals.WithMargin)*100) / 100
+		}
+		if totals.WithBudget > 0 {
+			summary.AverageBudget = math.Round(totals.BudgetTotal/float64(totals.WithBudget)*100) / 100
+		}
+	}
+	summary.MarginDistribution[0].Count = summary.Households - withMargin
+	for _, bucket := range result.Margins {
+		// the first bucket of margins is the settings without margin
+		summary.MarginDistribution[helpers.BucketIndex(bucket.Lower, helpers.MarginBounds)+1].Count += bucket.Count
+	}
+	summary.BudgetDistribution[0].Count = summary.Households - summary.WithBudget
+	for _, bucket := range result.Budgets {
+		// the first bucket of budgets is the settings without budget
+		summary.BudgetDistribution[helpers.BucketIndex(bucket.Lower, helpers.BudgetBounds)+1].Count += bucket.Count
+	}
+	db.logger.Info("summarize price settings successfully", zap.Int("households", summary.Households))
+	return summary, http.StatusOK, nil
+}
+
+// priceSettingsFacets is the result of aggregation which summarizes the price settings
+type priceSettingsFacets struct {
+	Totals []struct {
+		Households  int      `bson:"households"`
+		VatIncluded int      `bson:"vat_included"`
+		WithBudget  int      `bson:"with_budget"`
+		WithMargin  int      `bson:"with_margin"`
+		MarginTotal float64  `bson:"margin_total"`
+		MarginMin   *float64 `bson:"margin_min"`
+		BudgetTotal float64  `bson:"budget_total"`
+	} `bson:"totals"`
+	Users []struct {
+		Count int `bson:"count"`
+	} `bson:"users"`
+	Margins []bucketCount `bson:"margins"`
+	Budgets []bucketCount `bson:"budgets"`
+}
+
+// bucketCount is the amount of values in the bucket of `$bucket` stage, which is identified by its lower bound
+type bucketCount struct {
+	Lower float64 `bson:"_id"`
+	Count int     `bson:"count"`
+}
+
+// bucketStage builds the `$bucket` stage which counts the values of field by the buckets whose upper bounds are `bounds`.
+// The first bucket has no lower bound and the values which are not below any bound belong to the last (default) bucket.
+func bucketStage(field string, bounds []float64) bson.M {
+	boundaries := bson.A{math.Inf(-1)}
+	for _, bound := range bounds {
+		boundaries = append(boundaries, bound)
+	}
+	return bson.M{"$bucket": bson.M{
+		"groupBy":    field,
+		"boundaries": boundaries,
+		"default":    bounds[len(bounds)-1],
+		"output":     bson.M{"count": bson.M{"$sum": 1}},
+	}}
+}
+
+// GetPriceSettingsOfUser retrieves the price settings of every household which user owns.
+// Use case: Admin looks up a user.
+func (db Mongo) GetPriceSettingsOfUser(userID string) (settings []models.PriceSettings, statusCode int, err error) {
+	if userID == "" {
+		statusCode = http.StatusBadRequest
+		err = fmt.Errorf("cannot get price settings without user")
+		return
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "household_id", Value: 1}})
+	cursor, err := db.collection.Find(db.ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to get price settings of user: %s", err.Error())
+		return
+	}
+
+	settings = make([]models.PriceSettings, 0)
+	if err = cursor.All(db.ctx, &settings); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to cursor price settings of user: %s", err.Error())
+		return
+	}
+	if len(settings) == 0 {
+		statusCode = http.StatusNotFound
+		err = fmt.Errorf("failed to get price settings of user: %w", ErrPriceSettingsNotFound)
+		return
+	}
+	db.logger.Info("get price settings of user successfully", zap.Int("amount", len(settings)))
+	return settings, http.StatusOK, nil
+}
+
+// priceSettingsFilter builds the filter of price settings from the filters of query which are set
+func priceSettingsFilter(query models.PriceSettingsQuery) bson.M {
+	filter := bson.M{}
+	if query.UserID != "" {
+		filter["user_id"] = query.UserID
+	}
+	if query.HouseholdID != "" {
+		filter["household_id"] = query.HouseholdID
+	}
+	if query.VatIncluded != nil {
+		filter["vat_included"] = *query.VatIncluded
+	}
+	if query.HasBudget != nil {
+		if *query.HasBudget {
+			filter["monthly_budget"] = bson.M{"$gt": 0}
+		} else {
+			// settings stored before budgets have no budget yet
+			filter["monthly_budget"] = bson.M{"$not": bson.M{"$gt": 0}}
+		}
+	}
+	margin := bson.M{}
+	if query.MinMargin != nil {
+		margin["$gte"] = *query.MinMargin
+	}
+	if query.MaxMargin != nil {
+		margin["$lte"] = *query.MaxMargin
+	}
+	if len(margin) > 0 {
+		filter["margin"] = margin
+	}
+	return filter
+}
+
+// GetConsumption retrieves the consumption of household in the time range [from, to), ordered by time.
+func (db Mongo) GetConsumption(householdID string, from, to time.Time) (consumption []models.Consumption, statusCode int, err error) {
+	if householdID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot get consumption from unauthenticated user")
+		return
+	}
+
+	filter := bson.M{
+		"household_id": householdID,
+		"time": bson.M{
+			"$gte": from,
+			"$lt":  to,
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: 1}})
+	cursor, err := db.consumptionCollection.Find(db.ctx, filter, opts)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to get consumption: %s", err.Error())
+		return
+	}
+
+	consumption = make([]models.Consumption, 0)
+	if err = cursor.All(db.ctx, &consumption); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to cursor all consumption: %s", err.Error())
+		return nil, statusCode, err
+	}
+	db.logger.Info("get consumption successfully", zap.Int("amount", len(consumption)))
+	return consumption, http.StatusOK, nil
 }
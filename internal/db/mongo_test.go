@@ -3,9 +3,11 @@ package db
 
 import (
 	"context"
+	"math"
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/AnhCaooo/go-goods/log"
 	"github.com/AnhCaooo/stormbreaker/internal/models"
@@ -76,7 +78,7 @@ func TestInsertPriceSettings(t *testing.T) {
 				Code:  11000, // Duplicate key error code
 			}),
 			expectedStatusCode: http.StatusConflict,
-			expectedError:      "failed to insert: document already exists",
+			expectedError:      "failed to insert price settings: price settings exist already",
 		},
 	}
 
@@ -84,10 +86,15 @@ func TestInsertPriceSettings(t *testing.T) {
 		mt.Run(test.name, func(mt *mtest.T) {
 			db := NewMongo(ctx, nil, logger)
 			db.collection = mt.Coll
+			db.historyCollection = mt.Coll
 
-			// Add mock response if expected
+			// Add mock response if expected, after the latest version in history and followed by the response of recording history
 			if test.expectedResponse != nil {
-				mt.AddMockResponses(test.expectedResponse)
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "test.price_settings_history", mtest.FirstBatch),
+					test.expectedResponse,
+					mtest.CreateSuccessResponse(),
+				)
 			}
 
 			// Call the function
@@ -149,7 +156,7 @@ func TestGetPriceSettings(t *testing.T) {
 			mockResponse:       mtest.CreateCursorResponse(0, "test.price_settings", mtest.FirstBatch),
 			expectedSettings:   models.PriceSettings{},
 			expectedStatusCode: http.StatusNotFound,
-			expectedError:      "failed to get price settings: mongo: no documents in result",
+			expectedError:      "failed to get price settings: no matched settings were found",
 		},
 	}
 
@@ -198,65 +205,97 @@ func TestPatchPriceSettings(t *testing.T) {
 	logger := log.InitLogger(zapcore.DebugLevel)
 	ctx := context.TODO()
 
+	margin := 0.75
+	version := int64(2)
+
 	tests := []struct {
 		name               string
-		priceSettings      models.PriceSettings
-		mockResponse       bson.D
+		userID             string
+		patch              models.PriceSettingsPatch
+		expectedVersion    *int64
+		mockResponses      []bson.D
 		expectedStatusCode int
 		expectedError      string
 	}{
 		{
-			name: "successful update: valid user ID and updated fields",
-			priceSettings: models.PriceSettings{
-				UserID:      "12345",
-				Marginal:    0.75,
-				VatIncluded: true,
-			},
-			mockResponse: bson.D{
-				{Key: "n", Value: 1},         // Number of matched documents
-				{Key: "nModified", Value: 1}, // Number of modified documents
-				{Key: "ok", Value: 1},
+			name:            "successful update: only margin is changed at expected version",
+			userID:          "12345",
+			patch:           models.PriceSettingsPatch{Marginal: &margin},
+			expectedVersion: &version,
+			mockResponses: []bson.D{
+				// settings before update
+				{
+					{Key: "ok", Value: 1},
+					{Key: "value", Value: bson.D{
+						{Key: "user_id", Value: "12345"},
+						{Key: "margin", Value: 0.59},
+						{Key: "vat_included", Value: true},
+						{Key: "version", Value: 2},
+					}},
+				},
+				// amount of recorded history
+				mtest.CreateCursorResponse(0, "test.price_settings_history", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+				// record the change
+				mtest.CreateSuccessResponse(),
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedError:      "",
 		},
 		{
-			name: "unauthorized operation: empty user ID",
-			priceSettings: models.PriceSettings{
-				UserID:      "",
-				Marginal:    0.75,
-				VatIncluded: true,
-			},
-			mockResponse:       nil,
+			name:               "unauthorized operation: empty user ID",
+			userID:             "",
+			patch:              models.PriceSettingsPatch{Marginal: &margin},
+			mockResponses:      nil,
 			expectedStatusCode: http.StatusUnauthorized,
 			expectedError:      "cannot insert un-authenticated document",
 		},
 		{
-			name: "not found: no matched settings for the given user ID",
-			priceSettings: models.PriceSettings{
-				UserID:      "67890",
-				Marginal:    0.85,
-				VatIncluded: false,
-			},
-			mockResponse: bson.D{
-				{Key: "ok", Value: 1},
-				{Key: "nModified", Value: 0},
-				{Key: "matchedCount", Value: 0},
+			name:   "not found: no matched settings for the given user ID",
+			userID: "67890",
+			patch:  models.PriceSettingsPatch{Marginal: &margin},
+			mockResponses: []bson.D{
+				{
+					{Key: "ok", Value: 1},
+					{Key: "value", Value: nil},
+				},
 			},
 			expectedStatusCode: http.StatusNotFound,
 			expectedError:      "failed to update price settings: no matched settings were found",
 		},
 		{
-			name: "internal server error: database failure",
-			priceSettings: models.PriceSettings{
-				UserID:      "12345",
-				Marginal:    0.75,
-				VatIncluded: true,
+			name:            "precondition failed: settings were changed after the expected version",
+			userID:          "12345",
+			patch:           models.PriceSettingsPatch{Marginal: &margin},
+			expectedVersion: &version,
+			mockResponses: []bson.D{
+				{
+					{Key: "ok", Value: 1},
+					{Key: "value", Value: nil},
+				},
+				// settings exist at another version
+				mtest.CreateCursorResponse(0, "test.price_settings", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedError:      "failed to update price settings: settings were changed after version 2, reload them and try again",
+		},
+		{
+			name:               "bad request: nothing to change",
+			userID:             "12345",
+			patch:              models.PriceSettingsPatch{},
+			mockResponses:      nil,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "failed to update price settings: patch should contain at least one of `vat_included`, `margin` or `monthly_budget`",
+		},
+		{
+			name:   "internal server error: database failure",
+			userID: "12345",
+			patch:  models.PriceSettingsPatch{Marginal: &margin},
+			mockResponses: []bson.D{
+				mtest.CreateCommandErrorResponse(mtest.CommandError{
+					Code:    12345,
+					Message: "some database error",
+				}),
 			},
-			mockResponse: mtest.CreateCommandErrorResponse(mtest.CommandError{
-				Code:    12345,
-				Message: "some database error",
-			}),
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedError:      "failed to update price settings: some database error",
 		},
@@ -267,12 +306,13 @@ func TestPatchPriceSettings(t *testing.T) {
 			// Setup MongoDB mock instance
 			db := NewMongo(ctx, nil, logger)
 			db.collection = mt.Coll
+			db.historyCollection = mt.Coll
 
 			// Set up mock responses
-			mt.AddMockResponses(test.mockResponse)
+			mt.AddMockResponses(test.mockResponses...)
 
 			// Call the PatchPriceSettings function
-			statusCode, err := db.PatchPriceSettings(test.priceSettings)
+			settings, statusCode, err := db.PatchPriceSettings(test.userID, "", test.patch, test.expectedVersion)
 
 			// Validate error
 			if test.expectedError != "" {
@@ -285,6 +325,10 @@ func TestPatchPriceSettings(t *testing.T) {
 				t.Errorf("unexpected error: %v", err)
 			}
 
+			if err == nil && (settings.Marginal != margin || !settings.VatIncluded || settings.Version != version+1) {
+				t.Errorf("unexpected settings after patch: %+v", settings)
+			}
+
 			// Validate status code
 			if statusCode != test.expectedStatusCode {
 				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
@@ -302,16 +346,27 @@ func TestDeletePriceSettings(t *testing.T) {
 	tests := []struct {
 		name               string
 		userID             string
-		mockResponse       bson.D
+		mockResponses      []bson.D
 		expectedStatusCode int
 		expectedError      string
 	}{
 		{
 			name:   "successful deletion",
 			userID: "12345",
-			mockResponse: bson.D{
-				{Key: "ok", Value: 1},
-				{Key: "n", Value: 1}, // Number of documents deleted
+			mockResponses: []bson.D{
+				// deleted settings
+				{
+					{Key: "ok", Value: 1},
+					{Key: "value", Value: bson.D{
+						{Key: "user_id", Value: "12345"},
+						{Key: "household_id", Value: "12345"},
+						{Key: "version", Value: 2},
+					}},
+				},
+				// amount of recorded history
+				mtest.CreateCursorResponse(0, "test.price_settings_history", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}),
+				// record the deletion
+				mtest.CreateSuccessResponse(),
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedError:      "",
@@ -319,16 +374,18 @@ func TestDeletePriceSettings(t *testing.T) {
 		{
 			name:               "empty user ID",
 			userID:             "",
-			mockResponse:       nil,
+			mockResponses:      nil,
 			expectedStatusCode: http.StatusUnauthorized,
 			expectedError:      "cannot get price settings from unauthenticated user",
 		},
 		{
 			name:   "no matched documents: user ID not found",
 			userID: "99999",
-			mockResponse: bson.D{
-				{Key: "ok", Value: 1},
-				{Key: "n", Value: 0}, // No documents deleted
+			mockResponses: []bson.D{
+				{
+					{Key: "ok", Value: 1},
+					{Key: "value", Value: nil},
+				},
 			},
 			expectedStatusCode: http.StatusNotFound,
 			expectedError:      "failed to delete price settings: no matched settings were found",
@@ -339,10 +396,9 @@ func TestDeletePriceSettings(t *testing.T) {
 		mt.Run(test.name, func(mt *mtest.T) {
 			db := NewMongo(ctx, nil, logger)
 			db.collection = mt.Coll
+			db.historyCollection = mt.Coll
 
-			if test.mockResponse != nil {
-				mt.AddMockResponses(test.mockResponse)
-			}
+			mt.AddMockResponses(test.mockResponses...)
 			// Call the function being tested
 			statusCode, err := db.DeletePriceSettings(test.userID)
 
@@ -364,3 +420,360 @@ func TestDeletePriceSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestListPriceSettings(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+	query := models.PriceSettingsQuery{Page: 2, PageSize: 1}
+
+	tests := []struct {
+		name               string
+		mockResponses      []bson.D
+		expectedPage       *models.PriceSettingsPage
+		expectedStatusCode int
+		expectedError      string
+	}{
+		{
+			name: "successful operation/page of price settings",
+			mockResponses: []bson.D{
+				mtest.CreateCursorResponse(0, "stormbreaker.price_settings", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}),
+				mtest.CreateCursorResponse(0, "stormbreaker.price_settings", mtest.FirstBatch, bson.D{
+					{Key: "user_id", Value: "12345"},
+					{Key: "household_id", Value: "67890"},
+					{Key: "vat_included", Value: true},
+					{Key: "margin", Value: 0.59},
+				}),
+			},
+			expectedPage: &models.PriceSettingsPage{
+				Items:    []models.PriceSettings{{UserID: "12345", HouseholdID: "67890", VatIncluded: true, Marginal: 0.59}},
+				Page:     2,
+				PageSize: 1,
+				Total:    2,
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "general error/something went wrong while counting price settings",
+			mockResponses: []bson.D{
+				mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 12345, Message: "some database error"}),
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedError:      "failed to count price settings: some database error",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.collection = mt.Coll
+			mt.AddMockResponses(test.mockResponses...)
+
+			page, statusCode, err := db.ListPriceSettings(query)
+			if test.expectedError != "" {
+				if err == nil || err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %v, want %q", err, test.expectedError)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if statusCode != test.expectedStatusCode {
+				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
+			}
+			if !reflect.DeepEqual(page, test.expectedPage) {
+				t.Errorf("unexpected page: got %+v, want %+v", page, test.expectedPage)
+			}
+		})
+	}
+}
+
+func TestSummarizePriceSettings(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+	emptySummary := &models.PriceSettingsSummary{
+		MarginDistribution: []models.DistributionBucket{
+			{Label: "none"}, {Label: "0-0.25"}, {Label: "0.25-0.5"}, {Label: "0.5-1"}, {Label: "1-2"}, {Label: "2+"},
+		},
+		BudgetDistribution: []models.DistributionBucket{
+			{Label: "none"}, {Label: "0-50"}, {Label: "50-100"}, {Label: "100-200"}, {Label: "200+"},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		mockResponses      []bson.D
+		expectedSummary    *models.PriceSettingsSummary
+		expectedStatusCode int
+		expectedError      string
+	}{
+		{
+			name: "successful operation/summary of price settings",
+			mockResponses: []bson.D{
+				mtest.CreateCursorResponse(0, "stormbreaker.price_settings", mtest.FirstBatch, bson.D{
+					{Key: "totals", Value: bson.A{bson.D{
+						{Key: "households", Value: int32(4)},
+						{Key: "vat_included", Value: int32(3)},
+						{Key: "with_budget", Value: int32(3)},
+						{Key: "with_margin", Value: int32(4)},
+						{Key: "margin_total", Value: 5.29},
+						{Key: "margin_min", Value: 0.1},
+						{Key: "budget_total", Value: int32(450)},
+					}}},
+					{Key: "users", Value: bson.A{bson.D{{Key: "count", Value: int32(3)}}}},
+					{Key: "margins", Value: bson.A{
+						bson.D{{Key: "_id", Value: math.Inf(-1)}, {Key: "count", Value: int32(1)}},
+						bson.D{{Key: "_id", Value: 0.5}, {Key: "count", Value: int32(1)}},
+						bson.D{{Key: "_id", Value: 1.0}, {Key: "count", Value: int32(1)}},
+						bson.D{{Key: "_id", Value: 2.0}, {Key: "count", Value: int32(1)}},
+					}},
+					{Key: "budgets", Value: bson.A{
+						bson.D{{Key: "_id", Value: 50.0}, {Key: "count", Value: int32(1)}},
+						bson.D{{Key: "_id", Value: 100.0}, {Key: "count", Value: int32(1)}},
+						bson.D{{Key: "_id", Value: 200.0}, {Key: "count", Value: int32(1)}},
+					}},
+				}),
+			},
+			expectedSummary: &models.PriceSettingsSummary{
+				Users:         3,
+				Households:    4,
+				VatIncluded:   3,
+				WithBudget:    3,
+				AverageMargin: 1.32,
+				AverageBudget: 150,
+				MarginDistribution: []models.DistributionBucket{
+					{Label: "none", Count: 0},
+					{Label: "0-0.25", Count: 1},
+					{Label: "0.25-0.5", Count: 0},
+					{Label: "0.5-1", Count: 1},
+					{Label: "1-2", Count: 1},
+					{Label: "2+", Count: 1},
+				},
+				BudgetDistribution: []models.DistributionBucket{
+					{Label: "none", Count: 1},
+					{Label: "0-50", Count: 0},
+					{Label: "50-100", Count: 1},
+					{Label: "100-200", Count: 1},
+					{Label: "200+", Count: 1},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "successful operation/settings without margin and with negative margin",
+			mockResponses: []bson.D{
+				mtest.CreateCursorResponse(0, "stormbreaker.price_settings", mtest.FirstBatch, bson.D{
+					{Key: "totals", Value: bson.A{bson.D{
+						{Key: "households", Value: int32(3)},
+						{Key: "vat_included", Value: int32(3)},
+						{Key: "with_budget", Value: int32(0)},
+						{Key: "with_margin", Value: int32(2)},
+						{Key: "margin_total", Value: 0.2},
+						{Key: "margin_min", Value: -0.3},
+						{Key: "budget_total", Value: int32(0)},
+					}}},
+					{Key: "users", Value: bson.A{bson.D{{Key: "count", Value: int32(3)}}}},
+					{Key: "margins", Value: bson.A{
+						bson.D{{Key: "_id", Value: math.Inf(-1)}, {Key: "count", Value: int32(1)}},
+						bson.D{{Key: "_id", Value: 0.5}, {Key: "count", Value: int32(1)}},
+					}},
+					{Key: "budgets", Value: bson.A{}},
+				}),
+			},
+			expectedSummary: &models.PriceSettingsSummary{
+				Users:         3,
+				Households:    3,
+				VatIncluded:   3,
+				AverageMargin: 0.1,
+				MarginDistribution: []models.DistributionBucket{
+					{Label: "none", Count: 1},
+					{Label: "-0.3-0.25", Count: 1},
+					{Label: "0.25-0.5", Count: 0},
+					{Label: "0.5-1", Count: 1},
+					{Label: "1-2", Count: 0},
+					{Label: "2+", Count: 0},
+				},
+				BudgetDistribution: []models.DistributionBucket{
+					{Label: "none", Count: 3},
+					{Label: "0-50", Count: 0},
+					{Label: "50-100", Count: 0},
+					{Label: "100-200", Count: 0},
+					{Label: "200+", Count: 0},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "successful operation/no price settings",
+			mockResponses: []bson.D{
+				mtest.CreateCursorResponse(0, "stormbreaker.price_settings", mtest.FirstBatch, bson.D{
+					{Key: "totals", Value: bson.A{}},
+					{Key: "users", Value: bson.A{}},
+					{Key: "margins", Value: bson.A{}},
+					{Key: "budgets", Value: bson.A{}},
+				}),
+			},
+			expectedSummary:    emptySummary,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "general error/something went wrong while summarizing price settings",
+			mockResponses: []bson.D{
+				mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 12345, Message: "some database error"}),
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedError:      "failed to summarize price settings: some database error",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.collection = mt.Coll
+			mt.AddMockResponses(test.mockResponses...)
+
+			summary, statusCode, err := db.SummarizePriceSettings()
+			if test.expectedError != "" {
+				if err == nil || err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %v, want %q", err, test.expectedError)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if statusCode != test.expectedStatusCode {
+				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
+			}
+			if !reflect.DeepEqual(summary, test.expectedSummary) {
+				t.Errorf("unexpected summary: got %+v, want %+v", summary, test.expectedSummary)
+			}
+		})
+	}
+}
+
+func TestPriceSettingsFilter(t *testing.T) {
+	vatIncluded := false
+	hasBudget := true
+	minMargin, maxMargin := 0.25, 1.0
+
+	tests := []struct {
+		name     string
+		query    models.PriceSettingsQuery
+		expected bson.M
+	}{
+		{name: "no filters", query: models.PriceSettingsQuery{}, expected: bson.M{}},
+		{
+			name:  "every filter",
+			query: models.PriceSettingsQuery{UserID: "12345", HouseholdID: "67890", VatIncluded: &vatIncluded, HasBudget: &hasBudget, MinMargin: &minMargin, MaxMargin: &maxMargin},
+			expected: bson.M{
+				"user_id":        "12345",
+				"household_id":   "67890",
+				"vat_included":   false,
+				"monthly_budget": bson.M{"$gt": 0},
+				"margin":         bson.M{"$gte": 0.25, "$lte": 1.0},
+			},
+		},
+		{
+			name:     "only maximum margin",
+			query:    models.PriceSettingsQuery{MaxMargin: &maxMargin},
+			expected: bson.M{"margin": bson.M{"$lte": 1.0}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := priceSettingsFilter(test.query)
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("priceSettingsFilter() = %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestGetConsumption(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+	from := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name               string
+		userID             string
+		mockResponses      []bson.D
+		expectedAmount     int
+		expectedStatusCode int
+		expectedError      string
+	}{
+		{
+			name:   "successful operation/consumption found",
+			userID: "12345",
+			mockResponses: []bson.D{
+				mtest.CreateCursorResponse(1, "test.consumption", mtest.FirstBatch,
+					bson.D{
+						{Key: "user_id", Value: "12345"},
+						{Key: "time", Value: from},
+						{Key: "consumption", Value: 1.25},
+					},
+					bson.D{
+						{Key: "user_id", Value: "12345"},
+						{Key: "time", Value: from.Add(time.Hour)},
+						{Key: "consumption", Value: 0.5},
+					},
+				),
+				mtest.CreateCursorResponse(0, "test.consumption", mtest.NextBatch),
+			},
+			expectedAmount:     2,
+			expectedStatusCode: http.StatusOK,
+			expectedError:      "",
+		},
+		{
+			name:               "unauthenticated user/empty user ID",
+			userID:             "",
+			mockResponses:      nil,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      "cannot get consumption from unauthenticated user",
+		},
+		{
+			name:   "internal server error: database failure",
+			userID: "12345",
+			mockResponses: []bson.D{
+				mtest.CreateCommandErrorResponse(mtest.CommandError{
+					Code:    12345,
+					Message: "some database error",
+				}),
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedError:      "failed to get consumption: some database error",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.consumptionCollection = mt.Coll
+
+			mt.AddMockResponses(test.mockResponses...)
+
+			consumption, statusCode, err := db.GetConsumption(test.userID, from, to)
+			// Validate error
+			if test.expectedError != "" {
+				if err == nil {
+					t.Errorf("expected error %q, got nil", test.expectedError)
+				} else if err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %q, want %q", err.Error(), test.expectedError)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			// Validate status code
+			if statusCode != test.expectedStatusCode {
+				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
+			}
+
+			if len(consumption) != test.expectedAmount {
+				t.Errorf("unexpected amount of consumption: got %d, want %d", len(consumption), test.expectedAmount)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// AnhCao 2024
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/AnhCaooo/stormbreaker/internal/metrics"
+	"github.com/AnhCaooo/stormbreaker/internal/tracing"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// commandMonitor records every database command to metrics, and as a span of the context which started the command
+func commandMonitor() *event.CommandMonitor {
+	// spans of running commands by their connection and id
+	var spans sync.Map
+	key := func(connectionID string, requestID int64) string {
+		return fmt.Sprintf("%s/%d", connectionID, requestID)
+	}
+	endSpan := func(connectionID string, requestID int64, err error) {
+		value, exists := spans.LoadAndDelete(key(connectionID, requestID))
+		if !exists {
+			return
+		}
+		span := value.(trace.Span)
+		tracing.RecordError(span, err)
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			_, span := tracing.Tracer().Start(ctx, fmt.Sprintf("mongo %s", e.CommandName),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("db.system", "mongodb"),
+					attribute.String("db.namespace", e.DatabaseName),
+					attribute.String("db.operation.name", e.CommandName),
+				),
+			)
+			spans.Store(key(e.ConnectionID, e.RequestID), span)
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			metrics.ObserveMongoOperation(e.CommandName, e.Duration, nil)
+			endSpan(e.ConnectionID, e.RequestID, nil)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			err := errors.New(e.Failure)
+			metrics.ObserveMongoOperation(e.CommandName, e.Duration, err)
+			endSpan(e.ConnectionID, e.RequestID, err)
+		},
+	}
+}
@@ -0,0 +1,61 @@
+// AnhCao 2024
+package db
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCommandMonitorSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	tests := []struct {
+		name           string
+		failure        string
+		expectedStatus codes.Code
+	}{
+		{name: "succeeded command", expectedStatus: codes.Unset},
+		{name: "failed command", failure: "duplicate key error", expectedStatus: codes.Error},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exporter.Reset()
+			monitor := commandMonitor()
+			ctx, parent := otel.Tracer("test").Start(context.Background(), "GET /v1/households")
+			finished := event.CommandFinishedEvent{CommandName: "find", DatabaseName: "stormbreaker", RequestID: int64(i), ConnectionID: "localhost:27017[-1]"}
+
+			monitor.Started(ctx, &event.CommandStartedEvent{CommandName: "find", DatabaseName: "stormbreaker", RequestID: int64(i), ConnectionID: "localhost:27017[-1]"})
+			if test.failure == "" {
+				monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished})
+			} else {
+				monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finished, Failure: test.failure})
+			}
+			parent.End()
+
+			spans := exporter.GetSpans()
+			if len(spans) != 2 {
+				t.Fatalf("got %d spans, want span of command and its parent", len(spans))
+			}
+			command := spans[0]
+			if command.Name != "mongo find" {
+				t.Errorf("span name = %s, want mongo find", command.Name)
+			}
+			if command.Parent.SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("span of command does not belong to the span of request")
+			}
+			if command.Status.Code != test.expectedStatus {
+				t.Errorf("span status = %v, want %v", command.Status.Code, test.expectedStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// AnhCao 2024
+package db
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// GetNotificationPreferences retrieves the notification preferences of user
+func (db Mongo) GetNotificationPreferences(userID string) (preferences *models.NotificationPreferences, statusCode int, err error) {
+	if userID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot get notification preferences from unauthenticated user")
+		return
+	}
+
+	preferences = &models.NotificationPreferences{}
+	if err = db.notificationCollection.FindOne(db.ctx, bson.M{"user_id": userID}).Decode(preferences); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, http.StatusNotFound, fmt.Errorf("failed to get notification preferences: %w", ErrNotificationPreferencesNotFound)
+		}
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get notification preferences: %s", err.Error())
+	}
+	db.logger.Info("get notification preferences successfully")
+	return preferences, http.StatusOK, nil
+}
+
+// UpsertNotificationPreferences creates or replaces the notification preferences of user
+func (db Mongo) UpsertNotificationPreferences(preferences models.NotificationPreferences) (statusCode int, err error) {
+	if preferences.UserID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot insert un-authenticated document")
+		return
+	}
+
+	filter := bson.M{"user_id": preferences.UserID}
+	opts := options.Replace().SetUpsert(true)
+	if _, err = db.notificationCollection.ReplaceOne(db.ctx, filter, preferences, opts); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to update notification preferences: %s", err.Error())
+		return
+	}
+	db.logger.Info("update notification preferences successfully")
+	return http.StatusOK, nil
+}
+
+// GetAllNotificationPreferences retrieves the notification preferences of all users.
+// Use case: scheduler queues the daily message of each user for their chosen time.
+func (db Mongo) GetAllNotificationPreferences() ([]models.NotificationPreferences, error) {
+	cursor, err := db.notificationCollection.Find(db.ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all notification preferences: %s", err.Error())
+	}
+
+	results := make([]models.NotificationPreferences, 0)
+	if err = cursor.All(db.ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to cursor all notification preferences: %s", err.Error())
+	}
+	db.logger.Info("get all notification preferences successfully", zap.Int("amount", len(results)))
+	return results, nil
+}
@@ -0,0 +1,158 @@
+// AnhCao 2024
+package db
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// GetPriceAlerts retrieves all price alert rules of household
+func (db Mongo) GetPriceAlerts(householdID string) (alerts []models.PriceAlert, statusCode int, err error) {
+	if householdID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot get price alerts from unauthenticated user")
+		return
+	}
+
+	cursor, err := db.priceAlertsCollection.Find(db.ctx, bson.M{"household_id": householdID})
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to get price alerts: %s", err.Error())
+		return
+	}
+
+	alerts = make([]models.PriceAlert, 0)
+	if err = cursor.All(db.ctx, &alerts); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to cursor all price alerts: %s", err.Error())
+		return nil, statusCode, err
+	}
+	db.logger.Info("get price alerts successfully", zap.Int("amount", len(alerts)))
+	return alerts, http.StatusOK, nil
+}
+
+// GetPriceAlert retrieves a single price alert rule of household by its id
+func (db Mongo) GetPriceAlert(householdID, alertID string) (alert *models.PriceAlert, statusCode int, err error) {
+	filter, statusCode, err := priceAlertFilter(householdID, alertID)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	alert = &models.PriceAlert{}
+	if err = db.priceAlertsCollection.FindOne(db.ctx, filter).Decode(alert); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, http.StatusNotFound, fmt.Errorf("failed to get price alert: %w", ErrPriceAlertNotFound)
+		}
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get price alert: %s", err.Error())
+	}
+	db.logger.Info("get price alert successfully")
+	return alert, http.StatusOK, nil
+}
+
+// InsertPriceAlert inserts a new price alert rule and returns it with generated id.
+// Rules without household belong to the default household of user.
+func (db Mongo) InsertPriceAlert(alert models.PriceAlert) (inserted *models.PriceAlert, statusCode int, err error) {
+	if alert.UserID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot insert un-authenticated document")
+		return
+	}
+	if alert.HouseholdID == "" {
+		alert.HouseholdID = alert.UserID
+	}
+
+	alert.ID = primitive.NewObjectID()
+	if _, err = db.priceAlertsCollection.InsertOne(db.ctx, alert); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to insert price alert: %s", err.Error())
+		return
+	}
+	db.logger.Info("create new price alert successfully")
+	return &alert, http.StatusCreated, nil
+}
+
+// UpdatePriceAlert replaces the price alert rule of household
+func (db Mongo) UpdatePriceAlert(alertID string, alert models.PriceAlert) (statusCode int, err error) {
+	if alert.UserID == "" {
+		return http.StatusUnauthorized, fmt.Errorf("cannot access price alert of unauthenticated user")
+	}
+	if alert.HouseholdID == "" {
+		alert.HouseholdID = alert.UserID
+	}
+	filter, statusCode, err := priceAlertFilter(alert.HouseholdID, alertID)
+	if err != nil {
+		return statusCode, err
+	}
+
+	alert.ID = filter["_id"].(primitive.ObjectID)
+	result, err := db.priceAlertsCollection.ReplaceOne(db.ctx, filter, alert)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to update price alert: %s", err.Error())
+		return
+	}
+	if result.MatchedCount == 0 {
+		statusCode = http.StatusNotFound
+		err = fmt.Errorf("failed to update price alert: %w", ErrPriceAlertNotFound)
+		return
+	}
+	db.logger.Info("update price alert successfully")
+	return http.StatusOK, nil
+}
+
+// DeletePriceAlert deletes the price alert rule of household
+func (db Mongo) DeletePriceAlert(householdID, alertID string) (statusCode int, err error) {
+	filter, statusCode, err := priceAlertFilter(householdID, alertID)
+	if err != nil {
+		return statusCode, err
+	}
+
+	result, err := db.priceAlertsCollection.DeleteOne(db.ctx, filter)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to delete price alert: %s", err.Error())
+		return
+	}
+	if result.DeletedCount == 0 {
+		statusCode = http.StatusNotFound
+		err = fmt.Errorf("failed to delete price alert: %w", ErrPriceAlertNotFound)
+		return
+	}
+	db.logger.Info("delete price alert successfully", zap.Int64("deleted_amount", result.DeletedCount))
+	return http.StatusOK, nil
+}
+
+// GetEnabledPriceAlerts retrieves all enabled price alert rules of all users.
+// Use case: scheduler evaluates the rules when tomorrow's prices become available.
+func (db Mongo) GetEnabledPriceAlerts() (alerts []models.PriceAlert, err error) {
+	cursor, err := db.priceAlertsCollection.Find(db.ctx, bson.M{"enabled": true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enabled price alerts: %s", err.Error())
+	}
+
+	alerts = make([]models.PriceAlert, 0)
+	if err = cursor.All(db.ctx, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to cursor all enabled price alerts: %s", err.Error())
+	}
+	db.logger.Info("get enabled price alerts successfully", zap.Int("amount", len(alerts)))
+	return alerts, nil
+}
+
+// priceAlertFilter builds the filter to find a single price alert rule which belongs to household
+func priceAlertFilter(householdID, alertID string) (filter bson.M, statusCode int, err error) {
+	if householdID == "" {
+		return nil, http.StatusUnauthorized, fmt.Errorf("cannot access price alert of unauthenticated user")
+	}
+	id, err := primitive.ObjectIDFromHex(alertID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("%w: %s", ErrInvalidPriceAlertID, alertID)
+	}
+	return bson.M{"_id": id, "household_id": householdID}, http.StatusOK, nil
+}
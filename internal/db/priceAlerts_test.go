@@ -0,0 +1,366 @@
+// AnhCao 2024
+package db
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/AnhCaooo/go-goods/log"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestDeletePriceAlert(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+
+	tests := []struct {
+		name               string
+		householdID        string
+		alertID            string
+		mockResponse       bson.D
+		expectedStatusCode int
+		expectedError      string
+	}{
+		{
+			name:        "successful deletion",
+			householdID: "12345",
+			alertID:     "6759a8f1c2a4b5e3f1d2c3b4",
+			mockResponse: bson.D{
+				{Key: "ok", Value: 1},
+				{Key: "n", Value: 1},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedError:      "",
+		},
+		{
+			name:               "empty household ID",
+			householdID:        "",
+			alertID:            "6759a8f1c2a4b5e3f1d2c3b4",
+			mockResponse:       nil,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      "cannot access price alert of unauthenticated user",
+		},
+		{
+			name:               "invalid alert ID",
+			householdID:        "12345",
+			alertID:            "not-an-object-id",
+			mockResponse:       nil,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "invalid price alert id: not-an-object-id",
+		},
+		{
+			name:        "no matched documents: alert of other household",
+			householdID: "99999",
+			alertID:     "6759a8f1c2a4b5e3f1d2c3b4",
+			mockResponse: bson.D{
+				{Key: "ok", Value: 1},
+				{Key: "n", Value: 0},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedError:      "failed to delete price alert: no matched alert was found",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.priceAlertsCollection = mt.Coll
+
+			if test.mockResponse != nil {
+				mt.AddMockResponses(test.mockResponse)
+			}
+			statusCode, err := db.DeletePriceAlert(test.householdID, test.alertID)
+
+			// Validate error
+			if test.expectedError != "" {
+				if err == nil {
+					t.Errorf("expected error %q, got nil", test.expectedError)
+				} else if err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %q, want %q", err.Error(), test.expectedError)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			// Validate status code
+			if statusCode != test.expectedStatusCode {
+				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
+			}
+		})
+	}
+}
+
+func TestInsertPriceAlert(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+
+	tests := []struct {
+		name                string
+		alert               models.PriceAlert
+		mockResponse        bson.D
+		expectedStatusCode  int
+		expectedError       string
+		expectedHouseholdID string
+	}{
+		{
+			name:                "rule without household belongs to default household",
+			alert:               models.PriceAlert{UserID: "12345", Type: models.PRICE_ALERT_CHEAPEST_HOURS, Threshold: 5, Hours: 3, Enabled: true},
+			mockResponse:        mtest.CreateSuccessResponse(),
+			expectedStatusCode:  http.StatusCreated,
+			expectedHouseholdID: "12345",
+		},
+		{
+			name:                "rule of shared household",
+			alert:               models.PriceAlert{UserID: "12345", HouseholdID: "6759a8f1c2a4b5e3f1d2c3b4", Type: models.PRICE_ALERT_ABOVE, Threshold: 15},
+			mockResponse:        mtest.CreateSuccessResponse(),
+			expectedStatusCode:  http.StatusCreated,
+			expectedHouseholdID: "6759a8f1c2a4b5e3f1d2c3b4",
+		},
+		{
+			name:               "empty user ID",
+			alert:              models.PriceAlert{Type: models.PRICE_ALERT_ABOVE, Threshold: 15},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      "cannot insert un-authenticated document",
+		},
+		{
+			name:               "database failure",
+			alert:              models.PriceAlert{UserID: "12345", Type: models.PRICE_ALERT_ABOVE, Threshold: 15},
+			mockResponse:       mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 12345, Message: "some database error"}),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedError:      "failed to insert price alert: some database error",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.priceAlertsCollection = mt.Coll
+
+			if test.mockResponse != nil {
+				mt.AddMockResponses(test.mockResponse)
+			}
+			inserted, statusCode, err := db.InsertPriceAlert(test.alert)
+
+			// Validate error
+			if test.expectedError != "" {
+				if err == nil {
+					t.Errorf("expected error %q, got nil", test.expectedError)
+				} else if err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %q, want %q", err.Error(), test.expectedError)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Validate status code
+			if statusCode != test.expectedStatusCode {
+				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
+			}
+			if test.expectedError != "" {
+				return
+			}
+
+			// Validate inserted rule
+			if inserted.ID.IsZero() {
+				t.Error("expected generated id of price alert")
+			}
+			if inserted.HouseholdID != test.expectedHouseholdID {
+				t.Errorf("unexpected household: got %q, want %q", inserted.HouseholdID, test.expectedHouseholdID)
+			}
+			document := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+			if household := document.Lookup("household_id").StringValue(); household != test.expectedHouseholdID {
+				t.Errorf("unexpected stored household: got %q, want %q", household, test.expectedHouseholdID)
+			}
+		})
+	}
+}
+
+func TestGetPriceAlerts(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+
+	alertID, _ := primitive.ObjectIDFromHex("6759a8f1c2a4b5e3f1d2c3b4")
+	tests := []struct {
+		name               string
+		householdID        string
+		mockResponses      []bson.D
+		expectedStatusCode int
+		expectedError      string
+		expectedAlerts     []models.PriceAlert
+	}{
+		{
+			name:        "successful list",
+			householdID: "12345",
+			mockResponses: []bson.D{
+				mtest.CreateCursorResponse(0, "test.price_alerts", mtest.FirstBatch, bson.D{
+					{Key: "_id", Value: alertID},
+					{Key: "user_id", Value: "12345"},
+					{Key: "household_id", Value: "12345"},
+					{Key: "type", Value: models.PRICE_ALERT_CHEAPEST_HOURS},
+					{Key: "threshold", Value: 5.0},
+					{Key: "hours", Value: 3},
+					{Key: "enabled", Value: true},
+				}),
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedAlerts: []models.PriceAlert{
+				{ID: alertID, UserID: "12345", HouseholdID: "12345", Type: models.PRICE_ALERT_CHEAPEST_HOURS, Threshold: 5, Hours: 3, Enabled: true},
+			},
+		},
+		{
+			name:               "household without rules",
+			householdID:        "12345",
+			mockResponses:      []bson.D{mtest.CreateCursorResponse(0, "test.price_alerts", mtest.FirstBatch)},
+			expectedStatusCode: http.StatusOK,
+			expectedAlerts:     []models.PriceAlert{},
+		},
+		{
+			name:               "empty household ID",
+			householdID:        "",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      "cannot get price alerts from unauthenticated user",
+		},
+		{
+			name:        "database failure",
+			householdID: "12345",
+			mockResponses: []bson.D{
+				mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 12345, Message: "some database error"}),
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedError:      "failed to get price alerts: some database error",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.priceAlertsCollection = mt.Coll
+
+			if test.mockResponses != nil {
+				mt.AddMockResponses(test.mockResponses...)
+			}
+			alerts, statusCode, err := db.GetPriceAlerts(test.householdID)
+
+			// Validate error
+			if test.expectedError != "" {
+				if err == nil {
+					t.Errorf("expected error %q, got nil", test.expectedError)
+				} else if err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %q, want %q", err.Error(), test.expectedError)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			// Validate status code
+			if statusCode != test.expectedStatusCode {
+				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
+			}
+
+			// Validate alerts
+			if !reflect.DeepEqual(alerts, test.expectedAlerts) && test.expectedError == "" {
+				t.Errorf("expected alerts: %#v but got: %#v", test.expectedAlerts, alerts)
+			}
+		})
+	}
+}
+
+func TestUpdatePriceAlert(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+
+	updated := func(matched int) bson.D {
+		return bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "n", Value: matched},
+			{Key: "nModified", Value: matched},
+		}
+	}
+	alert := models.PriceAlert{UserID: "12345", Type: models.PRICE_ALERT_BELOW, Threshold: 2, Enabled: false}
+
+	tests := []struct {
+		name               string
+		alertID            string
+		alert              models.PriceAlert
+		mockResponse       bson.D
+		expectedStatusCode int
+		expectedError      string
+	}{
+		{
+			name:               "successful update",
+			alertID:            "6759a8f1c2a4b5e3f1d2c3b4",
+			alert:              alert,
+			mockResponse:       updated(1),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "empty user ID",
+			alertID:            "6759a8f1c2a4b5e3f1d2c3b4",
+			alert:              models.PriceAlert{Type: models.PRICE_ALERT_BELOW, Threshold: 2},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      "cannot access price alert of unauthenticated user",
+		},
+		{
+			name:               "invalid alert ID",
+			alertID:            "not-an-object-id",
+			alert:              alert,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "invalid price alert id: not-an-object-id",
+		},
+		{
+			name:               "no matched documents: alert of other household",
+			alertID:            "6759a8f1c2a4b5e3f1d2c3b4",
+			alert:              alert,
+			mockResponse:       updated(0),
+			expectedStatusCode: http.StatusNotFound,
+			expectedError:      "failed to update price alert: no matched alert was found",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.priceAlertsCollection = mt.Coll
+
+			if test.mockResponse != nil {
+				mt.AddMockResponses(test.mockResponse)
+			}
+			statusCode, err := db.UpdatePriceAlert(test.alertID, test.alert)
+
+			// Validate error
+			if test.expectedError != "" {
+				if err == nil {
+					t.Errorf("expected error %q, got nil", test.expectedError)
+				} else if err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %q, want %q", err.Error(), test.expectedError)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			// Validate status code
+			if statusCode != test.expectedStatusCode {
+				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
+			}
+			if test.mockResponse == nil {
+				return
+			}
+
+			// the rule is replaced only in the default household of user, which it belongs to
+			filter := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("q").Document()
+			if household := filter.Lookup("household_id").StringValue(); household != "12345" {
+				t.Errorf("unexpected household in filter: got %q, want %q", household, "12345")
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// AnhCao 2024
+package db
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	// HISTORY_WRITE_ATTEMPTS is how many times an entry of price settings history is written before giving up
+	HISTORY_WRITE_ATTEMPTS int = 3
+	// HISTORY_WRITE_BACKOFF is the wait before the next attempt, multiplied by the number of failed attempts
+	HISTORY_WRITE_BACKOFF time.Duration = 100 * time.Millisecond
+)
+
+// GetPriceSettingsHistory retrieves all changes of household's price settings, ordered by the time from which they are in force.
+func (db Mongo) GetPriceSettingsHistory(householdID string) (history []models.PriceSettingsHistory, statusCode int, err error) {
+	if householdID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot get price settings history from unauthenticated user")
+		return
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "effective_from", Value: 1}})
+	cursor, err := db.historyCollection.Find(db.ctx, bson.M{"household_id": householdID}, opts)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to get price settings history: %s", err.Error())
+		return
+	}
+
+	history = make([]models.PriceSettingsHistory, 0)
+	if err = cursor.All(db.ctx, &history); err != nil {
+		statusCode = http.StatusInternalServerError
+		err = fmt.Errorf("failed to cursor all price settings history: %s", err.Error())
+		return nil, statusCode, err
+	}
+	db.logger.Debug("get price settings history successfully", zap.Int("amount", len(history)))
+	return history, http.StatusOK, nil
+}
+
+// nextPriceSettingsVersion returns the version of household's price settings when they are created.
+// Versions keep counting up after the settings were deleted, so that every entry of history has its own version.
+func (db Mongo) nextPriceSettingsVersion(householdID string) (version int64, err error) {
+	var latest models.PriceSettingsHistory
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+	if err = db.historyCollection.FindOne(db.ctx, bson.M{"household_id": householdID}, opts).Decode(&latest); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 1, nil
+		}
+		return 0, fmt.Errorf("failed to get the latest version of price settings: %s", err.Error())
+	}
+	return latest.Version + 1, nil
+}
+
+// recordPriceSettingsChange stores the change of household's price settings which is in force from now on.
+// Households which had settings before history was recorded have no history yet. For them, the previous settings
+// are stored first as in force since the settings were created (`previousSince`), so that past slots keep using them.
+// Every entry is identified by household and version of the settings, so that the writes are safe to retry.
+func (db Mongo) recordPriceSettingsChange(previous *models.PriceSettings, previousSince time.Time, change models.PriceSettingsHistory) (statusCode int, err error) {
+	if previous != nil {
+		count, err := db.historyCollection.CountDocuments(db.ctx, bson.M{"household_id": change.HouseholdID})
+		if err != nil {
+			return http.StatusInternalServerError, fmt.Errorf("failed to record price settings history: %s", err.Error())
+		}
+		if count == 0 {
+			baseline := models.PriceSettingsHistory{PriceSettings: *previous, EffectiveFrom: previousSince.UTC()}
+			if err = db.upsertPriceSettingsHistory(baseline); err != nil {
+				return http.StatusInternalServerError, fmt.Errorf("failed to record price settings history: %s", err.Error())
+			}
+		}
+	}
+
+	change.EffectiveFrom = time.Now().UTC()
+	if err = db.upsertPriceSettingsHistory(change); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to record price settings history: %s", err.Error())
+	}
+	return http.StatusOK, nil
+}
+
+// upsertPriceSettingsHistory stores the entry of history unless the entry of the same version is stored already.
+// Failed writes are retried, because the settings themselves have been changed already.
+func (db Mongo) upsertPriceSettingsHistory(entry models.PriceSettingsHistory) (err error) {
+	filter := bson.M{"household_id": entry.HouseholdID, "version": entry.Version}
+	update := bson.M{"$setOnInsert": entry}
+	opts := options.Update().SetUpsert(true)
+	for attempt := 1; attempt <= HISTORY_WRITE_ATTEMPTS; attempt++ {
+		if _, err = db.historyCollection.UpdateOne(db.ctx, filter, update, opts); err == nil {
+			return nil
+		}
+		db.logger.Warn("failed to write price settings history", zap.Int("attempt", attempt), zap.Error(err))
+		if attempt < HISTORY_WRITE_ATTEMPTS {
+			time.Sleep(time.Duration(attempt) * HISTORY_WRITE_BACKOFF)
+		}
+	}
+	return err
+}
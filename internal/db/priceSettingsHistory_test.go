@@ -0,0 +1,179 @@
+// AnhCao 2024
+package db
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AnhCaooo/go-goods/log"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRecordPriceSettingsChange(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+
+	createdAt := time.Date(2024, 11, 1, 8, 0, 0, 0, time.UTC)
+	previous := models.PriceSettings{UserID: "12345", HouseholdID: "12345", Marginal: 0.59, Version: 2}
+	current := models.PriceSettings{UserID: "12345", HouseholdID: "12345", Marginal: 0.75, Version: 3}
+	failure := mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 12345, Message: "some database error"})
+
+	tests := []struct {
+		name               string
+		mockResponses      []bson.D
+		expectedStatusCode int
+		expectedWrites     int
+		expectedBaseline   bool
+	}{
+		{
+			name: "first change stores the previous settings since they were created",
+			mockResponses: []bson.D{
+				mtest.CreateCursorResponse(0, "test.price_settings_history", mtest.FirstBatch, bson.D{{Key: "n", Value: 0}}),
+				mtest.CreateSuccessResponse(),
+				mtest.CreateSuccessResponse(),
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedWrites:     2,
+			expectedBaseline:   true,
+		},
+		{
+			name: "failed write is retried",
+			mockResponses: []bson.D{
+				mtest.CreateCursorResponse(0, "test.price_settings_history", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+				failure,
+				mtest.CreateSuccessResponse(),
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedWrites:     2,
+		},
+		{
+			name: "write fails in every attempt",
+			mockResponses: []bson.D{
+				mtest.CreateCursorResponse(0, "test.price_settings_history", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+				failure, failure, failure,
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedWrites:     HISTORY_WRITE_ATTEMPTS,
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.historyCollection = mt.Coll
+			mt.AddMockResponses(test.mockResponses...)
+
+			statusCode, _ := db.recordPriceSettingsChange(&previous, createdAt, models.PriceSettingsHistory{PriceSettings: current})
+			if statusCode != test.expectedStatusCode {
+				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
+			}
+
+			writes := 0
+			for _, event := range mt.GetAllStartedEvents() {
+				if event.CommandName != "update" {
+					continue
+				}
+				writes++
+				update := event.Command.Lookup("updates").Array().Index(0).Value().Document()
+				if !update.Lookup("upsert").Boolean() {
+					t.Errorf("history is not written with upsert: %v", update)
+				}
+				entry := update.Lookup("u", "$setOnInsert").Document()
+				if writes == 1 && test.expectedBaseline {
+					if version := entry.Lookup("version").AsInt64(); version != previous.Version {
+						t.Errorf("version of baseline = %d, want %d", version, previous.Version)
+					}
+					if effectiveFrom := entry.Lookup("effective_from").Time(); !effectiveFrom.Equal(createdAt) {
+						t.Errorf("baseline is in force from %s, want %s", effectiveFrom, createdAt)
+					}
+				}
+			}
+			if writes != test.expectedWrites {
+				t.Errorf("amount of writes = %d, want %d", writes, test.expectedWrites)
+			}
+		})
+	}
+}
+
+func TestDeleteAndRecreatePriceSettings(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+
+	mt.Run("deletion is recorded and versions keep counting up", func(mt *mtest.T) {
+		db := NewMongo(ctx, nil, logger)
+		db.collection = mt.Coll
+		db.historyCollection = mt.Coll
+
+		mt.AddMockResponses(
+			// deleted settings
+			bson.D{
+				{Key: "ok", Value: 1},
+				{Key: "value", Value: bson.D{
+					{Key: "user_id", Value: "12345"},
+					{Key: "household_id", Value: "12345"},
+					{Key: "margin", Value: 0.75},
+					{Key: "version", Value: int64(3)},
+				}},
+			},
+			// amount of recorded history
+			mtest.CreateCursorResponse(0, "test.price_settings_history", mtest.FirstBatch, bson.D{{Key: "n", Value: 3}}),
+			// record the deletion
+			mtest.CreateSuccessResponse(),
+		)
+		if statusCode, err := db.DeletePriceSettings("12345"); err != nil || statusCode != http.StatusOK {
+			t.Fatalf("DeletePriceSettings() = %d, %v", statusCode, err)
+		}
+		deletion := lastHistoryEntry(t, mt)
+		if !deletion.Lookup("deleted").Boolean() || deletion.Lookup("version").AsInt64() != 4 {
+			t.Errorf("unexpected deletion in history: %v", deletion)
+		}
+
+		mt.ClearEvents()
+		mt.AddMockResponses(
+			// latest entry of history is the deletion
+			mtest.CreateCursorResponse(0, "test.price_settings_history", mtest.FirstBatch, bson.D{
+				{Key: "household_id", Value: "12345"},
+				{Key: "version", Value: int64(4)},
+				{Key: "deleted", Value: true},
+			}),
+			// insert the settings
+			mtest.CreateSuccessResponse(),
+			// record the settings
+			mtest.CreateSuccessResponse(),
+		)
+		if statusCode, err := db.InsertPriceSettings(models.PriceSettings{UserID: "12345", Marginal: 0.59}); err != nil || statusCode != http.StatusCreated {
+			t.Fatalf("InsertPriceSettings() = %d, %v", statusCode, err)
+		}
+		recreated := lastHistoryEntry(t, mt)
+		if version := recreated.Lookup("version").AsInt64(); version != 5 {
+			t.Errorf("version of created settings = %d, want 5", version)
+		}
+		if _, err := recreated.LookupErr("deleted"); err == nil {
+			t.Errorf("created settings are recorded as deleted: %v", recreated)
+		}
+	})
+}
+
+// lastHistoryEntry returns the entry of the last write to history
+func lastHistoryEntry(t *testing.T, mt *mtest.T) bson.Raw {
+	t.Helper()
+	var entry bson.Raw
+	for _, event := range mt.GetAllStartedEvents() {
+		if event.CommandName != "update" {
+			continue
+		}
+		update := event.Command.Lookup("updates").Array().Index(0).Value().Document()
+		entry = update.Lookup("u", "$setOnInsert").Document()
+	}
+	if entry == nil {
+		t.Fatalf("nothing was written to history")
+	}
+	return entry
+}
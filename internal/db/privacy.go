@@ -0,0 +1,123 @@
+// AnhCao 2024
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// ExportUserData collects everything the service holds about user: households, household members, price settings,
+// settings history and consumption of households which user owns, price alerts, calendar feeds and notification preferences of user.
+func (db Mongo) ExportUserData(userID string) (export *models.UserDataExport, statusCode int, err error) {
+	if userID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot export data of unauthenticated user")
+		return
+	}
+
+	export = &models.UserDataExport{UserID: userID, ExportedAt: time.Now().UTC()}
+	if export.Households, statusCode, err = db.GetHouseholds(userID); err != nil {
+		return nil, statusCode, err
+	}
+
+	owned := bson.M{"user_id": userID}
+	memberships := bson.M{"$or": bson.A{bson.M{"user_id": userID}, bson.M{"owner_id": userID}}}
+	if export.HouseholdMembers, err = findAll[models.HouseholdMember](db.ctx, db.membersCollection, memberships); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if export.PriceSettings, err = findAll[models.PriceSettings](db.ctx, db.collection, owned); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if export.PriceSettingsHistory, err = findAll[models.PriceSettingsHistory](db.ctx, db.historyCollection, owned); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if export.Consumption, err = findAll[models.Consumption](db.ctx, db.consumptionCollection, owned); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if export.PriceAlerts, err = findAll[models.PriceAlert](db.ctx, db.priceAlertsCollection, owned); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if export.CalendarFeeds, err = findAll[models.CalendarFeed](db.ctx, db.calendarCollection, owned); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	preferences := &models.NotificationPreferences{}
+	err = db.notificationCollection.FindOne(db.ctx, owned).Decode(preferences)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to export notification preferences: %s", err.Error())
+	}
+	if err == nil {
+		export.NotificationPreferences = preferences
+	}
+
+	db.logger.Info("export user data successfully", zap.Int("households", len(export.Households)))
+	return export, http.StatusOK, nil
+}
+
+// EraseUserData erases everything the service holds about user (GDPR right to erasure): all households of user
+// together with their data and members, the price alerts and calendar feeds of user in shared households,
+// the memberships of user in other users' households, the notification preferences of user and the messages scheduled for user.
+func (db Mongo) EraseUserData(userID string) (statusCode int, err error) {
+	if userID == "" {
+		statusCode = http.StatusUnauthorized
+		err = fmt.Errorf("cannot erase data of unauthenticated user")
+		return
+	}
+
+	households, err := findAll[models.Household](db.ctx, db.householdsCollection, bson.M{"user_id": userID})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	householdIDs := bson.A{userID}
+	for _, household := range households {
+		householdIDs = append(householdIDs, household.ID)
+	}
+
+	// data of households which user owns, including the price alerts which members defined in them
+	if err = db.deleteHouseholdData(bson.M{"household_id": bson.M{"$in": householdIDs}}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	// price alerts and calendar feeds of user in households shared with user
+	if err = db.deleteHouseholdData(bson.M{"user_id": userID}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	memberships := bson.M{"$or": bson.A{bson.M{"user_id": userID}, bson.M{"owner_id": userID}}}
+	if _, err = db.membersCollection.DeleteMany(db.ctx, memberships); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to erase household members: %s", err.Error())
+	}
+	if _, err = db.householdsCollection.DeleteMany(db.ctx, bson.M{"user_id": userID}); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to erase households: %s", err.Error())
+	}
+	if _, err = db.notificationCollection.DeleteOne(db.ctx, bson.M{"user_id": userID}); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to erase notification preferences: %s", err.Error())
+	}
+	if _, err = db.scheduledCollection.DeleteMany(db.ctx, bson.M{"user_id": userID}); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to erase scheduled notifications: %s", err.Error())
+	}
+
+	db.logger.Info("erase user data successfully", zap.Int("households", len(householdIDs)))
+	return http.StatusOK, nil
+}
+
+// findAll retrieves all documents of collection which match the filter
+func findAll[T any](ctx context.Context, collection *mongo.Collection, filter bson.M) ([]T, error) {
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get documents from %s: %s", collection.Name(), err.Error())
+	}
+
+	results := make([]T, 0)
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to cursor all documents from %s: %s", collection.Name(), err.Error())
+	}
+	return results, nil
+}
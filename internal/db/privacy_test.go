@@ -0,0 +1,249 @@
+// AnhCao 2024
+package db
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/AnhCaooo/go-goods/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestEraseUserData(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+
+	deleted := func(n int) bson.D {
+		return bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "n", Value: n},
+		}
+	}
+	households := mtest.CreateCursorResponse(0, "test.households", mtest.FirstBatch, bson.D{
+		{Key: "_id", Value: "6759a8f1c2a4b5e3f1d2c3b4"},
+		{Key: "user_id", Value: "12345"},
+		{Key: "name", Value: "Summer cottage"},
+	})
+
+	tests := []struct {
+		name               string
+		userID             string
+		mockResponses      []bson.D
+		expectedStatusCode int
+		expectedError      string
+	}{
+		{
+			name:   "successful erasure of all data",
+			userID: "12345",
+			mockResponses: []bson.D{
+				households,
+				// price settings, history, consumption, price alerts, calendar feeds and budget alerts of owned households
+				deleted(2), deleted(3), deleted(48), deleted(2), deleted(1), deleted(1),
+				// the same collections for documents of user in shared households
+				deleted(0), deleted(0), deleted(0), deleted(1), deleted(1), deleted(0),
+				// members, households, notification preferences and scheduled notifications
+				deleted(2), deleted(1), deleted(1), deleted(1),
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedError:      "",
+		},
+		{
+			name:               "empty user ID",
+			userID:             "",
+			mockResponses:      nil,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      "cannot erase data of unauthenticated user",
+		},
+		{
+			name:   "database failure while erasing household members",
+			userID: "12345",
+			mockResponses: []bson.D{
+				households,
+				deleted(2), deleted(3), deleted(48), deleted(2), deleted(1), deleted(1),
+				deleted(0), deleted(0), deleted(0), deleted(1), deleted(1), deleted(0),
+				mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 12345, Message: "some database error"}),
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedError:      "failed to erase household members: some database error",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.collection = mt.Coll
+			db.historyCollection = mt.Coll
+			db.consumptionCollection = mt.Coll
+			db.priceAlertsCollection = mt.Coll
+			db.householdsCollection = mt.Coll
+			db.membersCollection = mt.Coll
+			db.notificationCollection = mt.Coll
+			db.calendarCollection = mt.Coll
+			db.budgetAlertsCollection = mt.Coll
+			db.scheduledCollection = mt.Coll
+
+			if test.mockResponses != nil {
+				mt.AddMockResponses(test.mockResponses...)
+			}
+			statusCode, err := db.EraseUserData(test.userID)
+
+			// Validate error
+			if test.expectedError != "" {
+				if err == nil {
+					t.Errorf("expected error %q, got nil", test.expectedError)
+				} else if err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %q, want %q", err.Error(), test.expectedError)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			// Validate status code
+			if statusCode != test.expectedStatusCode {
+				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
+			}
+		})
+	}
+}
+
+func TestExportUserData(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+
+	empty := func() bson.D {
+		return mtest.CreateCursorResponse(0, "test.export", mtest.FirstBatch)
+	}
+	households := mtest.CreateCursorResponse(0, "test.households", mtest.FirstBatch, bson.D{
+		{Key: "_id", Value: "6759a8f1c2a4b5e3f1d2c3b4"},
+		{Key: "user_id", Value: "12345"},
+		{Key: "name", Value: "Summer cottage"},
+	})
+	priceSettings := mtest.CreateCursorResponse(0, "test.price_settings", mtest.FirstBatch,
+		bson.D{{Key: "user_id", Value: "12345"}, {Key: "household_id", Value: "12345"}, {Key: "margin", Value: 0.59}, {Key: "version", Value: 2}},
+		bson.D{{Key: "user_id", Value: "12345"}, {Key: "household_id", Value: "6759a8f1c2a4b5e3f1d2c3b4"}, {Key: "margin", Value: 0.3}, {Key: "version", Value: 1}},
+	)
+	preferences := mtest.CreateCursorResponse(0, "test.notification_preferences", mtest.FirstBatch, bson.D{
+		{Key: "user_id", Value: "12345"},
+		{Key: "tomorrow_prices", Value: true},
+		{Key: "language", Value: "fi"},
+	})
+
+	tests := []struct {
+		name                  string
+		userID                string
+		mockResponses         []bson.D
+		expectedStatusCode    int
+		expectedError         string
+		expectedHouseholds    int
+		expectedSettings      int
+		expectedHasPreference bool
+	}{
+		{
+			name:   "successful export of all data",
+			userID: "12345",
+			mockResponses: []bson.D{
+				// owned and shared households
+				households, empty(),
+				// members, price settings, history, consumption, price alerts and calendar feeds
+				empty(), priceSettings, empty(), empty(), empty(), empty(),
+				preferences,
+			},
+			expectedStatusCode:    http.StatusOK,
+			expectedHouseholds:    2,
+			expectedSettings:      2,
+			expectedHasPreference: true,
+		},
+		{
+			name:   "user without notification preferences",
+			userID: "12345",
+			mockResponses: []bson.D{
+				households, empty(),
+				empty(), priceSettings, empty(), empty(), empty(), empty(),
+				empty(),
+			},
+			expectedStatusCode:    http.StatusOK,
+			expectedHouseholds:    2,
+			expectedSettings:      2,
+			expectedHasPreference: false,
+		},
+		{
+			name:               "empty user ID",
+			userID:             "",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      "cannot export data of unauthenticated user",
+		},
+		{
+			name:   "database failure while exporting price settings",
+			userID: "12345",
+			mockResponses: []bson.D{
+				households, empty(),
+				empty(),
+				mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 12345, Message: "some database error"}),
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedError:      "failed to get documents from TestExportUserData/database_failure_while_exporting_price_settings: some database error",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.collection = mt.Coll
+			db.historyCollection = mt.Coll
+			db.consumptionCollection = mt.Coll
+			db.priceAlertsCollection = mt.Coll
+			db.householdsCollection = mt.Coll
+			db.membersCollection = mt.Coll
+			db.notificationCollection = mt.Coll
+			db.calendarCollection = mt.Coll
+			db.budgetAlertsCollection = mt.Coll
+
+			if test.mockResponses != nil {
+				mt.AddMockResponses(test.mockResponses...)
+			}
+			export, statusCode, err := db.ExportUserData(test.userID)
+
+			// Validate error
+			if test.expectedError != "" {
+				if err == nil {
+					t.Errorf("expected error %q, got nil", test.expectedError)
+				} else if err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %q, want %q", err.Error(), test.expectedError)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Validate status code
+			if statusCode != test.expectedStatusCode {
+				t.Errorf("unexpected status code: got %d, want %d", statusCode, test.expectedStatusCode)
+			}
+			if test.expectedError != "" {
+				return
+			}
+
+			// Validate export
+			if export.UserID != test.userID {
+				t.Errorf("unexpected user ID: got %q, want %q", export.UserID, test.userID)
+			}
+			// the default household is part of the export as well
+			if len(export.Households) != test.expectedHouseholds {
+				t.Errorf("unexpected amount of households: got %d, want %d", len(export.Households), test.expectedHouseholds)
+			}
+			if len(export.PriceSettings) != test.expectedSettings {
+				t.Errorf("unexpected amount of price settings: got %d, want %d", len(export.PriceSettings), test.expectedSettings)
+			}
+			if (export.NotificationPreferences != nil) != test.expectedHasPreference {
+				t.Errorf("unexpected notification preferences: %+v", export.NotificationPreferences)
+			}
+			if export.NotificationPreferences != nil && export.NotificationPreferences.Language != "fi" {
+				t.Errorf("unexpected language: got %q, want %q", export.NotificationPreferences.Language, "fi")
+			}
+		})
+	}
+}
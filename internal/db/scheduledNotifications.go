@@ -0,0 +1,66 @@
+// AnhCao 2024
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ScheduleNotification stores the message until its delivery time.
+// A message scheduled earlier for the same user, household and routing key is replaced.
+func (db Mongo) ScheduleNotification(notification models.ScheduledNotification) error {
+	notification.ID = primitive.NilObjectID
+	notification.Attempts = 0
+	filter := bson.M{
+		"user_id":      notification.UserID,
+		"household_id": notification.HouseholdID,
+		"routing_key":  notification.RoutingKey,
+	}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := db.scheduledCollection.ReplaceOne(db.ctx, filter, notification, opts); err != nil {
+		return fmt.Errorf("failed to schedule notification: %s", err.Error())
+	}
+	return nil
+}
+
+// ClaimDueNotification takes the earliest message whose delivery time has come. The message is not deleted, but its delivery
+// is postponed by `retryAfter`, so that it is published again when the attempt fails (or the service stops meanwhile)
+// and no other instance of service publishes it at the same time. Nil is returned when no message is due.
+func (db Mongo) ClaimDueNotification(now time.Time, retryAfter time.Duration) (*models.ScheduledNotification, error) {
+	filter := bson.M{"deliver_at": bson.M{"$lte": now}}
+	update := bson.M{
+		"$set": bson.M{"deliver_at": now.Add(retryAfter)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "deliver_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	notification := &models.ScheduledNotification{}
+	if err := db.scheduledCollection.FindOneAndUpdate(db.ctx, filter, update, opts).Decode(notification); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim scheduled notification: %s", err.Error())
+	}
+	return notification, nil
+}
+
+// DeleteScheduledNotification deletes the claimed message after it was published (or given up).
+// The message is kept when it was scheduled again meanwhile (ex: tomorrow-prices message of the next day).
+func (db Mongo) DeleteScheduledNotification(notification *models.ScheduledNotification) error {
+	filter := bson.M{"_id": notification.ID, "deliver_at": notification.DeliverAt}
+	if _, err := db.scheduledCollection.DeleteOne(db.ctx, filter); err != nil {
+		return fmt.Errorf("failed to delete scheduled notification: %s", err.Error())
+	}
+	db.logger.Debug("delete scheduled notification successfully", zap.String("id", notification.ID.Hex()))
+	return nil
+}
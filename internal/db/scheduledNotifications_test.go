@@ -0,0 +1,125 @@
+// AnhCao 2024
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AnhCaooo/go-goods/log"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestClaimDueNotification(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+	ctx := context.TODO()
+	now := time.Date(2024, 12, 9, 18, 30, 0, 0, time.UTC)
+	retryAfter := 5 * time.Minute
+
+	tests := []struct {
+		name             string
+		mockResponse     bson.D
+		expectedUserID   string
+		expectedAttempts int
+		expectedError    string
+	}{
+		{
+			name: "due message is claimed",
+			mockResponse: bson.D{
+				{Key: "ok", Value: 1},
+				{Key: "value", Value: bson.D{
+					{Key: "user_id", Value: "12345"},
+					{Key: "household_id", Value: "12345"},
+					{Key: "routing_key", Value: "push_notification_key"},
+					{Key: "deliver_at", Value: now.Add(retryAfter)},
+					{Key: "attempts", Value: 1},
+				}},
+			},
+			expectedUserID:   "12345",
+			expectedAttempts: 1,
+		},
+		{
+			name: "no message is due",
+			mockResponse: bson.D{
+				{Key: "ok", Value: 1},
+				{Key: "value", Value: nil},
+			},
+		},
+		{
+			name:          "database failure",
+			mockResponse:  mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 12345, Message: "some database error"}),
+			expectedError: "failed to claim scheduled notification: some database error",
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			db := NewMongo(ctx, nil, logger)
+			db.scheduledCollection = mt.Coll
+			mt.AddMockResponses(test.mockResponse)
+
+			notification, err := db.ClaimDueNotification(now, retryAfter)
+			if test.expectedError != "" {
+				if err == nil || err.Error() != test.expectedError {
+					t.Errorf("unexpected error: got %v, want %q", err, test.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.expectedUserID == "" {
+				if notification != nil {
+					t.Errorf("expected no message, got %+v", notification)
+				}
+				return
+			}
+			if notification == nil || notification.UserID != test.expectedUserID || notification.Attempts != test.expectedAttempts {
+				t.Errorf("unexpected message: %+v", notification)
+			}
+
+			// the delivery is postponed, so that the message is published again when this attempt fails
+			command := mt.GetStartedEvent().Command
+			if deliverAt := command.Lookup("update", "$set", "deliver_at").Time(); !deliverAt.Equal(now.Add(retryAfter)) {
+				t.Errorf("delivery is postponed to %s, want %s", deliverAt, now.Add(retryAfter))
+			}
+		})
+	}
+}
+
+func TestScheduleNotification(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	logger := log.InitLogger(zapcore.DebugLevel)
+
+	mt.Run("message replaces the earlier one of user, household and routing key", func(mt *mtest.T) {
+		db := NewMongo(context.TODO(), nil, logger)
+		db.scheduledCollection = mt.Coll
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		err := db.ScheduleNotification(models.ScheduledNotification{
+			UserID:      "12345",
+			HouseholdID: "67890",
+			RoutingKey:  "push_notification_key",
+			Attempts:    3,
+			DeliverAt:   time.Date(2024, 12, 9, 18, 30, 0, 0, time.UTC),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		if !update.Lookup("upsert").Boolean() {
+			t.Errorf("message is not scheduled with upsert: %v", update)
+		}
+		if filter := update.Lookup("q").Document(); filter.Lookup("household_id").StringValue() != "67890" || filter.Lookup("routing_key").StringValue() != "push_notification_key" {
+			t.Errorf("unexpected filter: %v", filter)
+		}
+		if attempts := update.Lookup("u", "attempts").AsInt64(); attempts != 0 {
+			t.Errorf("attempts of scheduled message = %d, want 0", attempts)
+		}
+	})
+}
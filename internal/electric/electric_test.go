@@ -1,2 +1,110 @@
 // AnhCao 2024
 package electric
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.uber.org/zap"
+)
+
+// roundTripFunc stubs the external source
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// stubExternalSource replaces the external source with plain prices of today and tomorrow, one per hour.
+// It fails the test when the prices are requested with margin or VAT, because those are applied locally.
+func stubExternalSource(t *testing.T) {
+	t.Helper()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	series := models.PriceSeries{Name: "c/kWh"}
+	for hour := 0; hour < 48; hour++ {
+		slot := today.Add(time.Duration(hour) * time.Hour).Format("2006-01-02 15:04:05")
+		series.Data = append(series.Data, models.Data{
+			TimeUTC:   slot,
+			Time:      slot,
+			Price:     float64(hour) + 0.5,
+			VatFactor: 1.255,
+			IsToday:   hour < 24,
+		})
+	}
+	body, err := json.Marshal(models.PriceResponse{Data: models.PriceData{Group: "hour", Series: []models.PriceSeries{series}}, Status: "ok"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := httpClient
+	httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		query := r.URL.Query()
+		if query.Get("margin") != "0.000000" || query.Get("include_vat") != "0" {
+			t.Errorf("external source was requested with price settings: %s", r.URL.RawQuery)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}, nil
+	})}
+	t.Cleanup(func() { httpClient = previous })
+}
+
+func TestSpotPriceIsSameForEveryEndpoint(t *testing.T) {
+	stubExternalSource(t)
+	settings := &models.PriceSettings{UserID: "12345", HouseholdID: "12345", VatIncluded: true, Marginal: 0.59}
+	electric := NewElectric(context.Background(), zap.NewNop(), nil, "12345", settings, nil)
+
+	// `/v1/market-price` (and cost)
+	marketPrices, _, err := electric.FetchSpotPrice(electric.BuildTodayTomorrowRequestPayload())
+	if err != nil {
+		t.Fatalf("FetchSpotPrice() error = %v", err)
+	}
+	// `/v1/market-price/today-tomorrow` (and v2, stream, GraphQL, gRPC and calendar)
+	todayTomorrow, err := electric.FetchCurrentSpotPrice()
+	if err != nil {
+		t.Fatalf("FetchCurrentSpotPrice() error = %v", err)
+	}
+
+	todayTomorrowPrices := append(todayTomorrow.Today.Prices.Data, todayTomorrow.Tomorrow.Prices.Data...)
+	if len(todayTomorrowPrices) != len(marketPrices.Data.Series[0].Data) {
+		t.Fatalf("amount of slots = %d, want %d", len(todayTomorrowPrices), len(marketPrices.Data.Series[0].Data))
+	}
+	for i, slot := range marketPrices.Data.Series[0].Data {
+		expected := (float64(i) + 0.5 + settings.Marginal) * 1.255
+		if math.Abs(slot.Price-expected) > 1e-9 {
+			t.Errorf("market price of slot %s = %v, want %v", slot.TimeUTC, slot.Price, expected)
+		}
+		other := todayTomorrowPrices[i]
+		if other.TimeUTC != slot.TimeUTC || other.Price != slot.Price || other.IncludeVat != slot.IncludeVat {
+			t.Errorf("today-tomorrow price of slot %s = %+v, want %+v", slot.TimeUTC, other, slot)
+		}
+	}
+}
+
+func TestFetchSpotPriceWithoutSettings(t *testing.T) {
+	stubExternalSource(t)
+	electric := NewElectric(context.Background(), zap.NewNop(), nil, "stormbreaker", nil, nil)
+
+	prices, _, err := electric.FetchSpotPrice(electric.BuildTodayTomorrowRequestPayload())
+	if err != nil {
+		t.Fatalf("FetchSpotPrice() error = %v", err)
+	}
+	for i, slot := range prices.Data.Series[0].Data {
+		if slot.Price != float64(i)+0.5 || slot.IncludeVat != "0" {
+			t.Errorf("plain price of slot %d = %+v, want %v without VAT", i, slot, float64(i)+0.5)
+			break
+		}
+	}
+	if LastFetchedAt().IsZero() {
+		t.Errorf("LastFetchedAt() is zero after successful fetch")
+	}
+}
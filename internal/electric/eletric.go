@@ -2,76 +2,149 @@
 package electric
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/AnhCaooo/go-goods/encode"
 	"github.com/AnhCaooo/stormbreaker/internal/constants"
 	"github.com/AnhCaooo/stormbreaker/internal/db"
 	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/AnhCaooo/stormbreaker/internal/metrics"
 	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/AnhCaooo/stormbreaker/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// PROVIDER is the 3rd party which the spot prices are fetched from
+const PROVIDER string = "oomi"
+
+// httpClient sends the requests to external source
+var httpClient = http.DefaultClient
+
+// lastFetchedAt is the time (unix nanoseconds) of the last successful fetch of prices from external source
+var lastFetchedAt atomic.Int64
+
+// LastFetchedAt returns the time of the last successful fetch of prices from external source.
+// It is zero when no prices have been fetched since the service started.
+func LastFetchedAt() time.Time {
+	fetchedAt := lastFetchedAt.Load()
+	if fetchedAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, fetchedAt)
+}
+
 type Electric struct {
-	logger        *zap.Logger
-	mongo         *db.Mongo
-	userId        string
+	// ctx is the context (ex: of request) which the fetches from external source belong to
+	ctx    context.Context
+	logger *zap.Logger
+	mongo  *db.Mongo
+	// householdId is the household whose price settings, history and consumption are used.
+	// The id of the default household is the id of the user.
+	householdId   string
 	priceSettings *models.PriceSettings
+	// defaultPriceSettings are in force while household has no stored price settings
+	defaultPriceSettings *models.PriceSettings
 }
 
-func NewElectric(logger *zap.Logger, mongo *db.Mongo, userId string, priceSettings *models.PriceSettings) *Electric {
+func NewElectric(ctx context.Context, logger *zap.Logger, mongo *db.Mongo, householdId string, priceSettings, defaultPriceSettings *models.PriceSettings) *Electric {
 	if mongo == nil {
 		logger.Warn("MongoDB client is nil, using mock or no-op database")
 	}
 
 	return &Electric{
-		logger:        logger,
-		mongo:         mongo,
-		userId:        userId,
-		priceSettings: priceSettings,
+		ctx:                  ctx,
+		logger:               logger,
+		mongo:                mongo,
+		householdId:          householdId,
+		priceSettings:        priceSettings,
+		defaultPriceSettings: defaultPriceSettings,
 	}
 }
 
-// FetchSpotPrice fetches the spot price based on the provided request parameters.
-// It first checks if the price settings or MongoDB connection are available.
-// If not, it loads the default price settings. It then formats the request parameters
-// and makes an HTTP GET request to an external source to fetch the data.
+// FetchSpotPrice fetches the plain spot price (no margin and no VAT) from external source based on the provided request parameters,
+// then applies locally the price settings which were in force at each slot. Prices of every endpoint are computed this way,
+// so that the same slot has the same price everywhere.
+// If there is no history (or no database connection), the current price settings are applied to all slots.
+// Without price settings, the plain spot price is returned.
 func (e Electric) FetchSpotPrice(requestParameters *models.PriceRequest) (responseData *models.PriceResponse, statusCode int, err error) {
-	var settings *models.PriceSettings = e.priceSettings
-	if e.mongo == nil || settings == nil || e.userId == "stormbreaker" {
-		e.logger.Debug("load default price settings")
-		settings = e.getDefaultPriceSettings()
+	responseData, statusCode, err = e.fetchSpotPrice(requestParameters, e.plainPriceSettings())
+	if err != nil {
+		return nil, statusCode, err
 	}
 
+	history := make([]models.PriceSettingsHistory, 0)
+	if e.mongo != nil && e.householdId != "stormbreaker" {
+		history, statusCode, err = e.mongo.GetPriceSettingsHistory(e.householdId)
+		if err != nil {
+			return nil, statusCode, err
+		}
+	}
+
+	for i := range responseData.Data.Series {
+		if err = helpers.ApplyPriceSettingsHistory(&responseData.Data.Series[i], history, e.currentPriceSettings(), e.defaultSettings()); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+	}
+	return responseData, http.StatusOK, nil
+}
+
+// fetchSpotPrice formats the request parameters with given price settings
+// and makes an HTTP GET request to an external source to fetch the data.
+func (e Electric) fetchSpotPrice(requestParameters *models.PriceRequest, settings *models.PriceSettings) (responseData *models.PriceResponse, statusCode int, err error) {
 	externalUrl, err := helpers.FormatMarketPricePostReqParameters(requestParameters, settings)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
 
+	ctx, span := tracing.Tracer().Start(e.ctx, "electric.fetchSpotPrice",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("stormbreaker.provider", PROVIDER)),
+	)
+	defer span.End()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, externalUrl, nil)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, http.StatusInternalServerError, err
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
 	// Make HTTP request to the external source
-	resp, err := http.Get(externalUrl)
+	start := time.Now()
+	resp, err := httpClient.Do(req)
 	if err != nil {
+		metrics.ObserveUpstreamFetch(PROVIDER, time.Since(start), err)
+		tracing.RecordError(span, err)
 		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch data from external source (Oomi): %s", err.Error())
 	}
 	defer resp.Body.Close()
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 
 	responseData, err = encode.DecodeResponse[*models.PriceResponse](resp)
+	metrics.ObserveUpstreamFetch(PROVIDER, time.Since(start), err)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return nil, http.StatusInternalServerError, err
 	}
+	lastFetchedAt.Store(time.Now().UnixNano())
 	statusCode = http.StatusOK
 	return
 }
 
-// FetchCurrentSpotPrice retrieves the current spot price for today and tomorrow,
-// maps the data to a response structure, and writes the response to the provided
-// http.ResponseWriter. It returns the mapped response and any error encountered.
+// FetchCurrentSpotPrice retrieves the current spot price for today and tomorrow, applies the current price settings
+// and maps the data to a response structure. It returns the mapped response and any error encountered.
 // Depending on the time sending request, there could be tomorrow's price come along with today's price.
 // In practice, tomorrow's price would be available around 3pm (Finnish time) everyday.
-func (e Electric) FetchCurrentSpotPrice(w http.ResponseWriter) (todayTomorrowResponse *models.TodayTomorrowPrice, err error) {
+func (e Electric) FetchCurrentSpotPrice() (todayTomorrowResponse *models.TodayTomorrowPrice, err error) {
 	reqBody := e.BuildTodayTomorrowRequestPayload()
-	todayTomorrowPrice, _, err := e.FetchSpotPrice(reqBody)
+	todayTomorrowPrice, _, err := e.fetchSpotPrice(reqBody, e.plainPriceSettings())
 	if err != nil {
 		return nil, fmt.Errorf("%s failed to fetch data: %s", constants.Server, err.Error())
 	}
@@ -80,15 +153,78 @@ func (e Electric) FetchCurrentSpotPrice(w http.ResponseWriter) (todayTomorrowRes
 	if err != nil {
 		return nil, fmt.Errorf("%s failed to map to informative struct data: %s", constants.Server, err.Error())
 	}
-
-	if err := encode.EncodeResponse(w, http.StatusOK, todayTomorrowResponse); err != nil {
-		return nil, fmt.Errorf("%s failed to encode response data: %s", constants.Server, err.Error())
-	}
+	todayTomorrowResponse = helpers.MapPriceSettingsWithTodayTomorrowSpotPrice(e.currentPriceSettings(), todayTomorrowResponse)
 
 	e.logger.Info("[from external source] get today and tomorrow's exchange price successfully")
 	return
 }
 
+// CalculateCost calculates the actual cost of electricity for household in the given date range ("YYYY-MM-DD").
+// It joins the stored consumption of household with the hourly spot prices which have the price settings in force at each slot applied.
+func (e Electric) CalculateCost(startDate, endDate string) (response *models.CostResponse, statusCode int, err error) {
+	if e.mongo == nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("%s cannot calculate cost without database connection", constants.Server)
+	}
+
+	from, to, err := helpers.ParseDateRangeInHelsinki(startDate, endDate)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	consumption, statusCode, err := e.mongo.GetConsumption(e.householdId, from, to)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	prices, statusCode, err := e.FetchSpotPrice(&models.PriceRequest{
+		StartDate:         startDate,
+		EndDate:           endDate,
+		Group:             "hour",
+		CompareToLastYear: 0,
+	})
+	if err != nil {
+		return nil, statusCode, err
+	}
+	if len(prices.Data.Series) == 0 {
+		return nil, http.StatusInternalServerError, fmt.Errorf("%s external source returned no price series", constants.Server)
+	}
+
+	response, err = helpers.CalculateCost(startDate, endDate, prices.Data.Series[0], consumption)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	e.logger.Info("calculate cost of electricity successfully", zap.String("starttime", startDate), zap.String("endtime", endDate))
+	return response, http.StatusOK, nil
+}
+
+// ProjectMonthlyBill projects the electricity bill of household for the current month.
+// It calculates the cost from the beginning of the month until tomorrow (so that the known prices of today and tomorrow are included)
+// and projects the remaining consumption and cost of the month from it.
+func (e Electric) ProjectMonthlyBill() (projection *models.BillProjection, statusCode int, err error) {
+	now, _, err := helpers.GetCurrentTimeInHelsinki()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format(helpers.DATE_FORMAT)
+	tomorrow := now.AddDate(0, 0, 1).Format(helpers.DATE_FORMAT)
+
+	report, statusCode, err := e.CalculateCost(monthStart, tomorrow)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	var budget float64
+	if e.priceSettings != nil {
+		budget = e.priceSettings.MonthlyBudget
+	}
+	projection, err = helpers.ProjectMonthlyBill(now, report, budget)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	e.logger.Info("project monthly bill successfully", zap.String("month", projection.Month))
+	return projection, http.StatusOK, nil
+}
+
 // return as request body with date of today and data of tomorrow.
 // Usage: get request body for '/market-price/today-tomorrow'
 func (e Electric) BuildTodayTomorrowRequestPayload() *models.PriceRequest {
@@ -102,11 +238,28 @@ func (e Electric) BuildTodayTomorrowRequestPayload() *models.PriceRequest {
 	}
 }
 
-// GetDefaultPriceSettings returns a default values in case the service cannot get the price settings from database.s
-func (e Electric) getDefaultPriceSettings() *models.PriceSettings {
+// plainPriceSettings returns the settings (no margin and no VAT) which fetch the plain spot price.
+// Price settings of household, if any, are applied to the plain spot price afterwards.
+func (e Electric) plainPriceSettings() *models.PriceSettings {
 	return &models.PriceSettings{
-		UserID:      e.userId,
+		HouseholdID: e.householdId,
 		Marginal:    0.0,
 		VatIncluded: false,
 	}
 }
+
+// currentPriceSettings returns the price settings of household, or the plain price settings when household has none
+func (e Electric) currentPriceSettings() *models.PriceSettings {
+	if e.priceSettings == nil || e.householdId == "stormbreaker" {
+		return e.plainPriceSettings()
+	}
+	return e.priceSettings
+}
+
+// defaultSettings returns the default price settings, or the plain price settings when there are none
+func (e Electric) defaultSettings() *models.PriceSettings {
+	if e.defaultPriceSettings == nil || e.householdId == "stormbreaker" {
+		return e.plainPriceSettings()
+	}
+	return e.defaultPriceSettings
+}
@@ -0,0 +1,100 @@
+// AnhCao 2024
+//
+// Package events passes changes of prices and price settings within the service to the clients
+// which keep a stream (Server-Sent Events) open, so that they do not need to poll.
+package events
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+const (
+	TOMORROW_AVAILABLE_EVENT string = "tomorrow_available" // tomorrow's prices were published
+	SETTINGS_CHANGED_EVENT   string = "settings_changed"   // price settings of a household changed
+	// SUBSCRIBER_BUFFER is how many events wait for a slow subscriber before newer events are dropped
+	SUBSCRIBER_BUFFER int = 8
+)
+
+// Event is a change which subscribers are notified about. Empty HouseholdID means that the event concerns every household.
+type Event struct {
+	Name        string
+	HouseholdID string
+}
+
+// Hub delivers the published events to the subscribers of the same process
+type Hub struct {
+	logger      *zap.Logger
+	lock        sync.Mutex
+	subscribers map[chan Event]string // subscriber channel -> id of household which it subscribed to
+	closed      bool
+}
+
+// NewHub returns a new Hub instance
+func NewHub(logger *zap.Logger) *Hub {
+	return &Hub{
+		logger:      logger,
+		subscribers: make(map[chan Event]string),
+	}
+}
+
+// Subscribe returns the channel of events which concern the household, and the function to stop the subscription.
+// The channel is closed when the hub is closed.
+func (h *Hub) Subscribe(householdID string) (<-chan Event, func()) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	events := make(chan Event, SUBSCRIBER_BUFFER)
+	if h.closed {
+		close(events)
+		return events, func() {}
+	}
+	h.subscribers[events] = householdID
+	unsubscribe := func() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		if _, exists := h.subscribers[events]; exists {
+			delete(h.subscribers, events)
+			close(events)
+		}
+	}
+	return events, unsubscribe
+}
+
+// Publish delivers the event to subscribers of its household, or to every subscriber when the event has no household.
+// Publish never blocks: the event is dropped for a subscriber whose buffer is full.
+func (h *Hub) Publish(event Event) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for events, householdID := range h.subscribers {
+		if event.HouseholdID != "" && event.HouseholdID != householdID {
+			continue
+		}
+		select {
+		case events <- event:
+		default:
+			h.logger.Warn("dropped event for slow subscriber", zap.String("event", event.Name), zap.String("household_id", householdID))
+		}
+	}
+}
+
+// Subscribers returns the amount of current subscribers
+func (h *Hub) Subscribers() int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return len(h.subscribers)
+}
+
+// Close closes the channels of all subscribers, so that open streams end and the server can shut down
+func (h *Hub) Close() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.closed = true
+	for events := range h.subscribers {
+		delete(h.subscribers, events)
+		close(events)
+	}
+}
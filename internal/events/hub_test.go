@@ -0,0 +1,89 @@
+// AnhCao 2024
+package events
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestPublish(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	home, unsubscribeHome := hub.Subscribe("home")
+	defer unsubscribeHome()
+	cottage, unsubscribeCottage := hub.Subscribe("cottage")
+	defer unsubscribeCottage()
+
+	hub.Publish(Event{Name: SETTINGS_CHANGED_EVENT, HouseholdID: "home"})
+	hub.Publish(Event{Name: TOMORROW_AVAILABLE_EVENT})
+
+	tests := []struct {
+		name     string
+		events   <-chan Event
+		expected []string
+	}{
+		{name: "household event and broadcast", events: home, expected: []string{SETTINGS_CHANGED_EVENT, TOMORROW_AVAILABLE_EVENT}},
+		{name: "only broadcast", events: cottage, expected: []string{TOMORROW_AVAILABLE_EVENT}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, expected := range test.expected {
+				select {
+				case event := <-test.events:
+					if event.Name != expected {
+						t.Errorf("received %s, want %s", event.Name, expected)
+					}
+				default:
+					t.Fatalf("no event received, want %s", expected)
+				}
+			}
+			select {
+			case event := <-test.events:
+				t.Errorf("unexpected event %s", event.Name)
+			default:
+			}
+		})
+	}
+}
+
+func TestPublishDoesNotBlock(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	events, unsubscribe := hub.Subscribe("home")
+	defer unsubscribe()
+
+	for i := 0; i < SUBSCRIBER_BUFFER+3; i++ {
+		hub.Publish(Event{Name: SETTINGS_CHANGED_EVENT, HouseholdID: "home"})
+	}
+	if len(events) != SUBSCRIBER_BUFFER {
+		t.Errorf("buffered %d events, want %d", len(events), SUBSCRIBER_BUFFER)
+	}
+}
+
+func TestUnsubscribeAndClose(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	first, unsubscribe := hub.Subscribe("home")
+	second, _ := hub.Subscribe("home")
+	if hub.Subscribers() != 2 {
+		t.Fatalf("Subscribers() = %d, want 2", hub.Subscribers())
+	}
+
+	unsubscribe()
+	unsubscribe()
+	if _, ok := <-first; ok {
+		t.Errorf("channel should be closed after unsubscribe")
+	}
+
+	hub.Close()
+	if _, ok := <-second; ok {
+		t.Errorf("channel should be closed after hub is closed")
+	}
+	if hub.Subscribers() != 0 {
+		t.Errorf("Subscribers() = %d, want 0", hub.Subscribers())
+	}
+
+	late, _ := hub.Subscribe("home")
+	if _, ok := <-late; ok {
+		t.Errorf("channel should be closed when subscribing to closed hub")
+	}
+	hub.Publish(Event{Name: TOMORROW_AVAILABLE_EVENT})
+}
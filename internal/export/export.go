@@ -0,0 +1,146 @@
+// AnhCao 2024
+//
+// Package export writes price series as CSV or XLSX spreadsheet, so that they can be opened
+// in spreadsheet applications without converting JSON responses.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+const (
+	CSV_CONTENT_TYPE  string = "text/csv"
+	XLSX_CONTENT_TYPE string = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// Format represents the format of exported price series
+type Format string
+
+const (
+	JSON Format = "json"
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+// Names of exported price series, which tell the rows of series apart
+const (
+	SERIES_CURRENT   string = "current"   // prices of requested range
+	SERIES_LAST_YEAR string = "last_year" // prices of the same range a year earlier, when client requested comparison
+	SERIES_TODAY     string = "today"
+	SERIES_TOMORROW  string = "tomorrow"
+)
+
+// HEADER is the first row of exported price series
+var HEADER = []string{"time", "time_utc", "price", "vat_factor", "unit", "series"}
+
+// Series represents the prices of a series together with its name in export
+type Series struct {
+	Name   string
+	Prices models.PriceSeries
+}
+
+// MarketPriceSeries names the series of market prices. The prices of requested range are followed
+// by the prices of last year when client requested comparison.
+func MarketPriceSeries(series []models.PriceSeries) []Series {
+	named := make([]Series, 0, len(series))
+	for i, s := range series {
+		name := SERIES_CURRENT
+		if i > 0 {
+			name = SERIES_LAST_YEAR
+		}
+		named = append(named, Series{Name: name, Prices: s})
+	}
+	return named
+}
+
+// Negotiate returns the format which client prefers through `Accept` header.
+// JSON is returned when client accepts neither CSV nor XLSX.
+func Negotiate(accept string) Format {
+	format, best := JSON, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		var candidate Format
+		switch mediaType {
+		case CSV_CONTENT_TYPE:
+			candidate = CSV
+		case XLSX_CONTENT_TYPE:
+			candidate = XLSX
+		case "application/json":
+			candidate = JSON
+		default:
+			continue
+		}
+		if quality > best {
+			format, best = candidate, quality
+		}
+	}
+	return format
+}
+
+// ContentType returns the content type of format
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return CSV_CONTENT_TYPE + "; charset=utf-8"
+	case XLSX:
+		return XLSX_CONTENT_TYPE
+	}
+	return "application/json"
+}
+
+// Rows returns a row of local time, UTC time, price, VAT factor, unit and name of series for each price of series
+func Rows(series ...Series) [][]string {
+	rows := make([][]string, 0)
+	for _, s := range series {
+		for _, data := range s.Prices.Data {
+			rows = append(rows, []string{
+				data.Time,
+				data.TimeUTC,
+				strconv.FormatFloat(data.Price, 'f', -1, 64),
+				strconv.FormatFloat(data.VatFactor, 'f', -1, 64),
+				s.Prices.Name,
+				s.Name,
+			})
+		}
+	}
+	return rows
+}
+
+// Write writes the price series in given format (CSV or XLSX) with header row
+func Write(w io.Writer, format Format, series ...Series) error {
+	switch format {
+	case CSV:
+		return writeCSV(w, series...)
+	case XLSX:
+		return writeXLSX(w, series...)
+	}
+	return fmt.Errorf("unsupported export format: %s", format)
+}
+
+func writeCSV(w io.Writer, series ...Series) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(HEADER); err != nil {
+		return fmt.Errorf("failed to write csv header: %s", err.Error())
+	}
+	if err := writer.WriteAll(Rows(series...)); err != nil {
+		return fmt.Errorf("failed to write csv rows: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// AnhCao 2024
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+var series = Series{
+	Name: SERIES_CURRENT,
+	Prices: models.PriceSeries{
+		Name: "c/kWh",
+		Data: []models.Data{
+			{TimeUTC: "2024-12-08 22:00:00", Time: "2024-12-09 00:00:00", Price: 2.47, VatFactor: 1.255},
+			{TimeUTC: "2024-12-08 23:00:00", Time: "2024-12-09 01:00:00", Price: 1.9, VatFactor: 1.255},
+		},
+	},
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected Format
+	}{
+		{accept: "", expected: JSON},
+		{accept: "*/*", expected: JSON},
+		{accept: "application/json", expected: JSON},
+		{accept: "text/csv", expected: CSV},
+		{accept: "text/csv; charset=utf-8", expected: CSV},
+		{accept: XLSX_CONTENT_TYPE, expected: XLSX},
+		{accept: "application/json;q=0.5, text/csv", expected: CSV},
+		{accept: "text/csv;q=0.2, " + XLSX_CONTENT_TYPE + ";q=0.8", expected: XLSX},
+	}
+
+	for _, test := range tests {
+		t.Run(test.accept, func(t *testing.T) {
+			if got := Negotiate(test.accept); got != test.expected {
+				t.Errorf("Negotiate(%q) = %s, want %s", test.accept, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(&buffer, CSV, series); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "time,time_utc,price,vat_factor,unit,series\n" +
+		"2024-12-09 00:00:00,2024-12-08 22:00:00,2.47,1.255,c/kWh,current\n" +
+		"2024-12-09 01:00:00,2024-12-08 23:00:00,1.9,1.255,c/kWh,current\n"
+	if buffer.String() != expected {
+		t.Errorf("unexpected csv:\n%s\nwant:\n%s", buffer.String(), expected)
+	}
+}
+
+func TestWriteCSVComparedToLastYear(t *testing.T) {
+	lastYear := models.PriceSeries{
+		Name: "c/kWh",
+		Data: []models.Data{
+			{TimeUTC: "2023-12-08 22:00:00", Time: "2023-12-09 00:00:00", Price: 8.12, VatFactor: 1.24},
+		},
+	}
+	var buffer bytes.Buffer
+	if err := Write(&buffer, CSV, MarketPriceSeries([]models.PriceSeries{series.Prices, lastYear})...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "time,time_utc,price,vat_factor,unit,series\n" +
+		"2024-12-09 00:00:00,2024-12-08 22:00:00,2.47,1.255,c/kWh,current\n" +
+		"2024-12-09 01:00:00,2024-12-08 23:00:00,1.9,1.255,c/kWh,current\n" +
+		"2023-12-09 00:00:00,2023-12-08 22:00:00,8.12,1.24,c/kWh,last_year\n"
+	if buffer.String() != expected {
+		t.Errorf("unexpected csv:\n%s\nwant:\n%s", buffer.String(), expected)
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(&buffer, XLSX, series); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("xlsx is not a valid zip archive: %v", err)
+	}
+	var sheet string
+	for _, file := range archive.File {
+		if file.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		content, _ := file.Open()
+		data, _ := io.ReadAll(content)
+		sheet = string(data)
+	}
+	if !strings.Contains(sheet, `<c r="C2"><v>2.47</v></c>`) || !strings.Contains(sheet, `<t>c/kWh</t>`) || !strings.Contains(sheet, `<t>current</t>`) {
+		t.Errorf("unexpected sheet: %s", sheet)
+	}
+	if len(archive.File) != 5 {
+		t.Errorf("unexpected amount of parts in xlsx: got %d, want 5", len(archive.File))
+	}
+}
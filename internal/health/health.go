@@ -0,0 +1,126 @@
+// AnhCao 2024
+//
+// Package health reports whether the service can serve requests, for liveness and readiness probes (ex: Kubernetes).
+// The readiness is the result of dependency checks (database, message broker, etc.) which are registered to the Monitor.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	STATUS_UP            string = "up"            // the dependency works
+	STATUS_DOWN          string = "down"          // the dependency does not work
+	STATUS_READY         string = "ready"         // every dependency works
+	STATUS_NOT_READY     string = "not_ready"     // some dependency does not work
+	STATUS_SHUTTING_DOWN string = "shutting_down" // service is shutting down and does not take new requests
+
+	// CHECK_TIMEOUT is the longest time which a check may take, so that probes get the answer in time
+	CHECK_TIMEOUT time.Duration = 2 * time.Second
+	// SHUTDOWN_DRAIN_PERIOD is how long the service reports not-ready before it stops serving,
+	// so that load balancers stop sending new requests to it first
+	SHUTDOWN_DRAIN_PERIOD time.Duration = 5 * time.Second
+	// PRICE_FETCH_MAX_AGE is the longest time without successful fetch of prices from 3rd party.
+	// Prices are cached until the end of day and polled in the afternoon, so there is a fetch at least once a day.
+	PRICE_FETCH_MAX_AGE time.Duration = 26 * time.Hour
+	// SCHEDULER_HEARTBEAT_MAX_AGE is the longest time without heartbeat of scheduler, which beats every minute
+	SCHEDULER_HEARTBEAT_MAX_AGE time.Duration = 3 * time.Minute
+)
+
+// Check checks a dependency. It returns an error when the dependency does not work.
+type Check func(ctx context.Context) error
+
+// CheckResult is the result of a dependency check
+type CheckResult struct {
+	Status string `json:"status" example:"up" enums:"up,down"`               // status of the dependency
+	Error  string `json:"error,omitempty" example:"failed to ping database"` // reason why the dependency does not work
+}
+
+// Report is the readiness of service with the result of every dependency check
+type Report struct {
+	Status string                 `json:"status" example:"ready" enums:"ready,not_ready,shutting_down"` // readiness of the service
+	Checks map[string]CheckResult `json:"checks"`                                                       // result of every dependency check by its name
+}
+
+// Monitor keeps the dependency checks of service and whether it is shutting down
+type Monitor struct {
+	checks       map[string]Check
+	shuttingDown atomic.Bool
+	lock         sync.RWMutex
+}
+
+// NewMonitor returns a new Monitor instance without checks
+func NewMonitor() *Monitor {
+	return &Monitor{checks: make(map[string]Check)}
+}
+
+// AddCheck registers the check of dependency by its name. The check with the same name is replaced.
+func (m *Monitor) AddCheck(name string, check Check) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.checks[name] = check
+}
+
+// ShutDown marks the service as not ready, because it is shutting down
+func (m *Monitor) ShutDown() {
+	m.shuttingDown.Store(true)
+}
+
+// Readiness runs all checks concurrently and reports whether every dependency works.
+// While the service is shutting down, it is not ready regardless of dependencies.
+func (m *Monitor) Readiness(ctx context.Context) Report {
+	if m.shuttingDown.Load() {
+		return Report{Status: STATUS_SHUTTING_DOWN, Checks: map[string]CheckResult{}}
+	}
+
+	m.lock.RLock()
+	checks := make(map[string]Check, len(m.checks))
+	for name, check := range m.checks {
+		checks[name] = check
+	}
+	m.lock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, CHECK_TIMEOUT)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var resultsLock sync.Mutex
+	report := Report{Status: STATUS_READY, Checks: make(map[string]CheckResult, len(checks))}
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := CheckResult{Status: STATUS_UP}
+			if err := check(ctx); err != nil {
+				result = CheckResult{Status: STATUS_DOWN, Error: err.Error()}
+			}
+			resultsLock.Lock()
+			defer resultsLock.Unlock()
+			report.Checks[name] = result
+			if result.Status == STATUS_DOWN {
+				report.Status = STATUS_NOT_READY
+			}
+		}(name, check)
+	}
+	wg.Wait()
+	return report
+}
+
+// MaxAge returns the check which fails when `lastSeen` (ex: last heartbeat of a job) is older than `maxAge`.
+// Zero `lastSeen` means that nothing was seen yet, which is fine until `maxAge` has passed since `since`.
+func MaxAge(lastSeen func() time.Time, maxAge time.Duration, since time.Time, now func() time.Time) Check {
+	return func(ctx context.Context) error {
+		last := lastSeen()
+		if last.IsZero() {
+			last = since
+		}
+		if age := now().Sub(last); age > maxAge {
+			return fmt.Errorf("last seen %s ago, which is longer than %s", age.Truncate(time.Second), maxAge)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,96 @@
+// AnhCao 2024
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name           string
+		checks         map[string]Check
+		shuttingDown   bool
+		expectedStatus string
+		expectedChecks map[string]CheckResult
+	}{
+		{
+			name: "every dependency works",
+			checks: map[string]Check{
+				"mongo":    func(ctx context.Context) error { return nil },
+				"rabbitmq": func(ctx context.Context) error { return nil },
+			},
+			expectedStatus: STATUS_READY,
+			expectedChecks: map[string]CheckResult{"mongo": {Status: STATUS_UP}, "rabbitmq": {Status: STATUS_UP}},
+		},
+		{
+			name: "a dependency does not work",
+			checks: map[string]Check{
+				"mongo":    func(ctx context.Context) error { return errors.New("failed to ping database") },
+				"rabbitmq": func(ctx context.Context) error { return nil },
+			},
+			expectedStatus: STATUS_NOT_READY,
+			expectedChecks: map[string]CheckResult{"mongo": {Status: STATUS_DOWN, Error: "failed to ping database"}, "rabbitmq": {Status: STATUS_UP}},
+		},
+		{
+			name:           "shutting down",
+			checks:         map[string]Check{"mongo": func(ctx context.Context) error { return nil }},
+			shuttingDown:   true,
+			expectedStatus: STATUS_SHUTTING_DOWN,
+			expectedChecks: map[string]CheckResult{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			monitor := NewMonitor()
+			for name, check := range test.checks {
+				monitor.AddCheck(name, check)
+			}
+			if test.shuttingDown {
+				monitor.ShutDown()
+			}
+
+			got := monitor.Readiness(context.Background())
+			if got.Status != test.expectedStatus {
+				t.Errorf("status = %s, want %s", got.Status, test.expectedStatus)
+			}
+			if len(got.Checks) != len(test.expectedChecks) {
+				t.Fatalf("checks = %+v, want %+v", got.Checks, test.expectedChecks)
+			}
+			for name, expected := range test.expectedChecks {
+				if got.Checks[name] != expected {
+					t.Errorf("check %s = %+v, want %+v", name, got.Checks[name], expected)
+				}
+			}
+		})
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	start := time.Date(2024, 12, 9, 12, 0, 0, 0, time.UTC)
+	now := start.Add(10 * time.Minute)
+
+	tests := []struct {
+		name     string
+		lastSeen time.Time
+		maxAge   time.Duration
+		hasError bool
+	}{
+		{name: "recently seen", lastSeen: now.Add(-time.Minute), maxAge: 3 * time.Minute, hasError: false},
+		{name: "seen too long ago", lastSeen: now.Add(-5 * time.Minute), maxAge: 3 * time.Minute, hasError: true},
+		{name: "not seen yet after start", lastSeen: time.Time{}, maxAge: time.Hour, hasError: false},
+		{name: "not seen for too long after start", lastSeen: time.Time{}, maxAge: 5 * time.Minute, hasError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			check := MaxAge(func() time.Time { return test.lastSeen }, test.maxAge, start, func() time.Time { return now })
+			if err := check(context.Background()); (err != nil) != test.hasError {
+				t.Errorf("MaxAge() error = %v, want error %v", err, test.hasError)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+var (
+	// upper bounds (c/kWh) of the margin buckets of summary, the last bucket has no upper bound
+	MarginBounds = []float64{0.25, 0.5, 1, 2}
+	// upper bounds (EUR) of the monthly budget buckets of summary, the last bucket has no upper bound
+	BudgetBounds = []float64{50, 100, 200}
+)
+
+// RolesFromClaims returns the roles of user from the claim of access token at `path` (ex: "app_metadata.role").
+// The claim may be one role or a list of roles. No roles are returned when the claim is missing.
+func RolesFromClaims(claims map[string]interface{}, path string) []string {
+	var value interface{} = claims
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+
+	switch role := value.(type) {
+	case string:
+		if role == "" {
+			return nil
+		}
+		return []string{role}
+	case []interface{}:
+		roles := make([]string, 0, len(role))
+		for _, item := range role {
+			if name, ok := item.(string); ok && name != "" {
+				roles = append(roles, name)
+			}
+		}
+		return roles
+	}
+	return nil
+}
+
+// ParsePriceSettingsQuery parses the filters and page of price settings from query parameters:
+// `user_id`, `household_id`, `vat_included`, `has_budget`, `min_margin`, `max_margin`, `page` and `page_size`.
+func ParsePriceSettingsQuery(query url.Values) (models.PriceSettingsQuery, error) {
+	result := models.PriceSettingsQuery{
+		UserID:      query.Get("user_id"),
+		HouseholdID: query.Get("household_id"),
+		Page:        1,
+		PageSize:    models.ADMIN_DEFAULT_PAGE_SIZE,
+	}
+
+	var err error
+	if result.VatIncluded, err = optionalBool(query, "vat_included"); err != nil {
+		return result, err
+	}
+	if result.HasBudget, err = optionalBool(query, "has_budget"); err != nil {
+		return result, err
+	}
+	if result.MinMargin, err = optionalFloat(query, "min_margin"); err != nil {
+		return result, err
+	}
+	if result.MaxMargin, err = optionalFloat(query, "max_margin"); err != nil {
+		return result, err
+	}
+	if result.MinMargin != nil && result.MaxMargin != nil && *result.MinMargin > *result.MaxMargin {
+		return result, fmt.Errorf("`min_margin` should not be greater than `max_margin`")
+	}
+
+	if value := query.Get("page"); value != "" {
+		if result.Page, err = strconv.Atoi(value); err != nil || result.Page < 1 || result.Page > models.ADMIN_MAX_PAGE {
+			return result, fmt.Errorf("`page` should be an integer between 1 and %d", models.ADMIN_MAX_PAGE)
+		}
+	}
+	if value := query.Get("page_size"); value != "" {
+		if result.PageSize, err = strconv.Atoi(value); err != nil || result.PageSize < 1 || result.PageSize > models.ADMIN_MAX_PAGE_SIZE {
+			return result, fmt.Errorf("`page_size` should be an integer between 1 and %d", models.ADMIN_MAX_PAGE_SIZE)
+		}
+	}
+	return result, nil
+}
+
+// NewDistribution returns the empty buckets whose upper bounds are `bounds`, the last bucket has no upper bound.
+// The first bucket starts at `lowest`, which is the smallest value of the distribution (usually 0).
+// Ex: lowest 0 and bounds 0.5 and 1 give the buckets "0-0.5", "0.5-1" and "1+".
+func NewDistribution(lowest float64, bounds []float64) []models.DistributionBucket {
+	buckets := make([]models.DistributionBucket, len(bounds)+1)
+	lower := strconv.FormatFloat(lowest, 'f', -1, 64)
+	for i, bound := range bounds {
+		upper := strconv.FormatFloat(bound, 'f', -1, 64)
+		buckets[i].Label = fmt.Sprintf("%s-%s", lower, upper)
+		lower = upper
+	}
+	buckets[len(bounds)].Label = lower + "+"
+	return buckets
+}
+
+// BucketIndex returns the index of bucket which the value belongs to. A value belongs to the first bucket
+// whose upper bound is greater than the value, the values which are not below any bound belong to the last bucket.
+func BucketIndex(value float64, bounds []float64) int {
+	i := 0
+	for i < len(bounds) && value >= bounds[i] {
+		i++
+	}
+	return i
+}
+
+func optionalBool(query url.Values, key string) (*bool, error) {
+	value := query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("`%s` should be true or false", key)
+	}
+	return &result, nil
+}
+
+func optionalFloat(query url.Values, key string) (*float64, error) {
+	value := query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("`%s` should be a number", key)
+	}
+	return &result, nil
+}
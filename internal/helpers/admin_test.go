@@ -0,0 +1,139 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+func TestRolesFromClaims(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   map[string]interface{}
+		path     string
+		expected []string
+	}{
+		{
+			name:     "one role in nested claim",
+			claims:   map[string]interface{}{"app_metadata": map[string]interface{}{"role": "admin"}},
+			path:     "app_metadata.role",
+			expected: []string{"admin"},
+		},
+		{
+			name:     "list of roles",
+			claims:   map[string]interface{}{"app_metadata": map[string]interface{}{"roles": []interface{}{"support", "admin", 1}}},
+			path:     "app_metadata.roles",
+			expected: []string{"support", "admin"},
+		},
+		{
+			name:     "top level claim",
+			claims:   map[string]interface{}{"role": "authenticated"},
+			path:     "role",
+			expected: []string{"authenticated"},
+		},
+		{
+			name:     "missing claim",
+			claims:   map[string]interface{}{"role": "authenticated"},
+			path:     "app_metadata.role",
+			expected: nil,
+		},
+		{
+			name:     "claim is not an object",
+			claims:   map[string]interface{}{"app_metadata": "admin"},
+			path:     "app_metadata.role",
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := RolesFromClaims(test.claims, test.path)
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("RolesFromClaims() = %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestParsePriceSettingsQuery(t *testing.T) {
+	vatIncluded := true
+	minMargin := 0.5
+
+	tests := []struct {
+		name     string
+		query    url.Values
+		expected models.PriceSettingsQuery
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			query:    url.Values{},
+			expected: models.PriceSettingsQuery{Page: 1, PageSize: models.ADMIN_DEFAULT_PAGE_SIZE},
+		},
+		{
+			name:     "filters and page",
+			query:    url.Values{"user_id": {"12345"}, "vat_included": {"true"}, "min_margin": {"0.5"}, "page": {"3"}, "page_size": {"20"}},
+			expected: models.PriceSettingsQuery{UserID: "12345", VatIncluded: &vatIncluded, MinMargin: &minMargin, Page: 3, PageSize: 20},
+		},
+		{name: "invalid boolean", query: url.Values{"has_budget": {"maybe"}}, wantErr: true},
+		{name: "invalid margin", query: url.Values{"max_margin": {"abc"}}, wantErr: true},
+		{name: "min margin greater than max margin", query: url.Values{"min_margin": {"2"}, "max_margin": {"1"}}, wantErr: true},
+		{name: "page is zero", query: url.Values{"page": {"0"}}, wantErr: true},
+		{name: "page is too big", query: url.Values{"page": {"9223372036854775807"}}, wantErr: true},
+		{name: "page size is too big", query: url.Values{"page_size": {"500"}}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParsePriceSettingsQuery(test.query)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParsePriceSettingsQuery() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("ParsePriceSettingsQuery() = %+v, want %+v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDistribution(t *testing.T) {
+	expected := []models.DistributionBucket{
+		{Label: "0-0.25"},
+		{Label: "0.25-0.5"},
+		{Label: "0.5-1"},
+		{Label: "1-2"},
+		{Label: "2+"},
+	}
+	if got := NewDistribution(0, MarginBounds); !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewDistribution() = %+v, want %+v", got, expected)
+	}
+
+	// negative margins start the first bucket
+	expected[0].Label = "-0.3-0.25"
+	if got := NewDistribution(-0.3, MarginBounds); !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewDistribution() = %+v, want %+v", got, expected)
+	}
+}
+
+func TestBucketIndex(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected int
+	}{
+		{value: -0.1, expected: 0},
+		{value: 0.2, expected: 0},
+		{value: 0.25, expected: 1},
+		{value: 0.59, expected: 2},
+		{value: 1.5, expected: 3},
+		{value: 3, expected: 4},
+	}
+
+	for _, test := range tests {
+		if got := BucketIndex(test.value, MarginBounds); got != test.expected {
+			t.Errorf("BucketIndex(%v) = %d, want %d", test.value, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+// ValidatePriceAlert checks whether the given price alert rule can be evaluated
+func ValidatePriceAlert(alert *models.PriceAlert) error {
+	if !isValidFloat(alert.Threshold) {
+		return fmt.Errorf("threshold should have float value")
+	}
+
+	switch alert.Type {
+	case models.PRICE_ALERT_ABOVE, models.PRICE_ALERT_BELOW:
+		return nil
+	case models.PRICE_ALERT_CHEAPEST_HOURS:
+		if alert.Hours < 1 || alert.Hours > 24 {
+			return fmt.Errorf("hours should be between 1 and 24 for type '%s'", models.PRICE_ALERT_CHEAPEST_HOURS)
+		}
+		return nil
+	default:
+		return fmt.Errorf("type should have valid value: '%s', '%s', '%s'", models.PRICE_ALERT_ABOVE, models.PRICE_ALERT_BELOW, models.PRICE_ALERT_CHEAPEST_HOURS)
+	}
+}
+
+// EvaluatePriceAlert evaluates the price alert rule against the given prices, which are in chronological order, and
+// returns the slots which match the rule, in chronological order. No slots means the rule does not match.
+func EvaluatePriceAlert(alert *models.PriceAlert, prices []models.Data) []models.Data {
+	matched := make([]models.Data, 0)
+	switch alert.Type {
+	case models.PRICE_ALERT_ABOVE:
+		for _, price := range prices {
+			if price.Price > alert.Threshold {
+				matched = append(matched, price)
+			}
+		}
+	case models.PRICE_ALERT_BELOW:
+		for _, price := range prices {
+			if price.Price < alert.Threshold {
+				matched = append(matched, price)
+			}
+		}
+	case models.PRICE_ALERT_CHEAPEST_HOURS:
+		cheapest := make([]models.Data, len(prices))
+		copy(cheapest, prices)
+		sort.SliceStable(cheapest, func(i, j int) bool {
+			return cheapest[i].Price < cheapest[j].Price
+		})
+		// the rule counts hours, while prices can be given in 15-minute slots
+		slots := alert.Hours * int(time.Hour/SlotDuration(prices))
+		if len(cheapest) > slots {
+			cheapest = cheapest[:slots]
+		}
+		for _, price := range cheapest {
+			if price.Price < alert.Threshold {
+				matched = append(matched, price)
+			}
+		}
+		sort.SliceStable(matched, func(i, j int) bool {
+			return matched[i].TimeUTC < matched[j].TimeUTC
+		})
+	}
+	return matched
+}
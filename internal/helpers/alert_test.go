@@ -0,0 +1,133 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"testing"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+func TestValidatePriceAlert(t *testing.T) {
+	tests := []struct {
+		name    string
+		alert   models.PriceAlert
+		wantErr bool
+	}{
+		{name: "valid above rule", alert: models.PriceAlert{Type: models.PRICE_ALERT_ABOVE, Threshold: 15}, wantErr: false},
+		{name: "valid below rule", alert: models.PriceAlert{Type: models.PRICE_ALERT_BELOW, Threshold: 1}, wantErr: false},
+		{name: "valid cheapest hours rule", alert: models.PriceAlert{Type: models.PRICE_ALERT_CHEAPEST_HOURS, Threshold: 5, Hours: 3}, wantErr: false},
+		{name: "cheapest hours rule without hours", alert: models.PriceAlert{Type: models.PRICE_ALERT_CHEAPEST_HOURS, Threshold: 5}, wantErr: true},
+		{name: "unknown type", alert: models.PriceAlert{Type: "sometimes", Threshold: 5}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidatePriceAlert(&test.alert)
+			if (err != nil) != test.wantErr {
+				t.Errorf("ValidatePriceAlert() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluatePriceAlert(t *testing.T) {
+	prices := []models.Data{
+		{TimeUTC: "2024-12-08 22:00:00", Price: 4},
+		{TimeUTC: "2024-12-08 23:00:00", Price: 20},
+		{TimeUTC: "2024-12-09 00:00:00", Price: 1},
+		{TimeUTC: "2024-12-09 01:00:00", Price: 2},
+		{TimeUTC: "2024-12-09 02:00:00", Price: 16},
+	}
+
+	tests := []struct {
+		name          string
+		alert         models.PriceAlert
+		expectedTimes []string
+	}{
+		{
+			name:          "any slot exceeds threshold",
+			alert:         models.PriceAlert{Type: models.PRICE_ALERT_ABOVE, Threshold: 15},
+			expectedTimes: []string{"2024-12-08 23:00:00", "2024-12-09 02:00:00"},
+		},
+		{
+			name:          "no slot exceeds threshold",
+			alert:         models.PriceAlert{Type: models.PRICE_ALERT_ABOVE, Threshold: 25},
+			expectedTimes: []string{},
+		},
+		{
+			name:          "any slot below threshold",
+			alert:         models.PriceAlert{Type: models.PRICE_ALERT_BELOW, Threshold: 2},
+			expectedTimes: []string{"2024-12-09 00:00:00"},
+		},
+		{
+			name:          "cheapest hours below threshold in chronological order",
+			alert:         models.PriceAlert{Type: models.PRICE_ALERT_CHEAPEST_HOURS, Threshold: 3, Hours: 3},
+			expectedTimes: []string{"2024-12-09 00:00:00", "2024-12-09 01:00:00"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := EvaluatePriceAlert(&test.alert, prices)
+			if len(got) != len(test.expectedTimes) {
+				t.Fatalf("expected %d matched slots, got %d", len(test.expectedTimes), len(got))
+			}
+			for i, price := range got {
+				if price.TimeUTC != test.expectedTimes[i] {
+					t.Errorf("expected slot %s, got %s", test.expectedTimes[i], price.TimeUTC)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluatePriceAlertWithQuarterHours(t *testing.T) {
+	// prices of 3 hours in 15-minute slots
+	prices := []models.Data{
+		{TimeUTC: "2025-10-08 22:00:00", Price: 9}, {TimeUTC: "2025-10-08 22:15:00", Price: 8},
+		{TimeUTC: "2025-10-08 22:30:00", Price: 7}, {TimeUTC: "2025-10-08 22:45:00", Price: 6},
+		{TimeUTC: "2025-10-08 23:00:00", Price: 2}, {TimeUTC: "2025-10-08 23:15:00", Price: 1},
+		{TimeUTC: "2025-10-08 23:30:00", Price: 1.5}, {TimeUTC: "2025-10-08 23:45:00", Price: 3},
+		{TimeUTC: "2025-10-09 00:00:00", Price: 4}, {TimeUTC: "2025-10-09 00:15:00", Price: 5},
+		{TimeUTC: "2025-10-09 00:30:00", Price: 10}, {TimeUTC: "2025-10-09 00:45:00", Price: 12},
+	}
+
+	tests := []struct {
+		name          string
+		alert         models.PriceAlert
+		expectedTimes []string
+	}{
+		{
+			name:          "cheapest hour is 4 slots",
+			alert:         models.PriceAlert{Type: models.PRICE_ALERT_CHEAPEST_HOURS, Threshold: 20, Hours: 1},
+			expectedTimes: []string{"2025-10-08 23:00:00", "2025-10-08 23:15:00", "2025-10-08 23:30:00", "2025-10-08 23:45:00"},
+		},
+		{
+			name:  "cheapest 2 hours are 8 slots, of which those below threshold match",
+			alert: models.PriceAlert{Type: models.PRICE_ALERT_CHEAPEST_HOURS, Threshold: 5, Hours: 2},
+			expectedTimes: []string{
+				"2025-10-08 23:00:00", "2025-10-08 23:15:00", "2025-10-08 23:30:00", "2025-10-08 23:45:00",
+				"2025-10-09 00:00:00",
+			},
+		},
+		{
+			name:          "more hours than prices",
+			alert:         models.PriceAlert{Type: models.PRICE_ALERT_CHEAPEST_HOURS, Threshold: 1.6, Hours: 24},
+			expectedTimes: []string{"2025-10-08 23:15:00", "2025-10-08 23:30:00"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := EvaluatePriceAlert(&test.alert, prices)
+			if len(got) != len(test.expectedTimes) {
+				t.Fatalf("expected %d matched slots, got %d", len(test.expectedTimes), len(got))
+			}
+			for i, price := range got {
+				if price.TimeUTC != test.expectedTimes[i] {
+					t.Errorf("expected slot %s, got %s", test.expectedTimes[i], price.TimeUTC)
+				}
+			}
+		})
+	}
+}
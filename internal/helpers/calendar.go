@@ -0,0 +1,170 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+const ICALENDAR_TIME_FORMAT string = "20060102T150405Z" // layout of UTC timestamps in iCalendar (RFC 5545)
+
+// NewCalendarToken generates the secret token of calendar feed and the hash which is stored instead of it
+func NewCalendarToken() (token, tokenHash string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate calendar token: %s", err.Error())
+	}
+	token = hex.EncodeToString(b)
+	return token, HashCalendarToken(token), nil
+}
+
+// HashCalendarToken returns the hash of calendar token which is stored in database
+func HashCalendarToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// ValidateCalendarHours checks the length of periods in calendar feed. Value 0 means the default length.
+func ValidateCalendarHours(hours int) (int, error) {
+	if hours == 0 {
+		return models.DEFAULT_CALENDAR_HOURS, nil
+	}
+	if hours < 1 || hours > models.MAX_CALENDAR_HOURS {
+		return 0, fmt.Errorf("`hours` should be between 1 and %d", models.MAX_CALENDAR_HOURS)
+	}
+	return hours, nil
+}
+
+// CheapestWindow returns the consecutive slots of `hours` with the lowest average price
+func CheapestWindow(prices models.PriceSeries, hours int) (*models.PriceWindow, error) {
+	return findWindow(prices, hours, func(candidate, best float64) bool { return candidate < best })
+}
+
+// MostExpensiveWindow returns the consecutive slots of `hours` with the highest average price
+func MostExpensiveWindow(prices models.PriceSeries, hours int) (*models.PriceWindow, error) {
+	return findWindow(prices, hours, func(candidate, best float64) bool { return candidate > best })
+}
+
+// findWindow slides a window of `hours` over prices and keeps the one whose sum is better than the others.
+// The width and the end of window follow the length of slots, which is an hour or 15 minutes.
+// Nil is returned when prices do not cover `hours`.
+func findWindow(prices models.PriceSeries, hours int, isBetter func(candidate, best float64) bool) (*models.PriceWindow, error) {
+	data := prices.Data
+	slotDuration := SlotDuration(data)
+	slots := hours * int(time.Hour/slotDuration)
+	if hours < 1 || len(data) < slots {
+		return nil, nil
+	}
+
+	sum := 0.0
+	for i := 0; i < slots; i++ {
+		sum += data[i].Price
+	}
+	bestStart, bestSum := 0, sum
+	for start := 1; start+slots <= len(data); start++ {
+		sum += data[start+slots-1].Price - data[start-1].Price
+		if isBetter(sum, bestSum) {
+			bestStart, bestSum = start, sum
+		}
+	}
+
+	startTime, err := time.Parse(DATE_TIME_FORMAT, data[bestStart].TimeUTC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time of price slot: %s", err.Error())
+	}
+	return &models.PriceWindow{
+		Start:        startTime,
+		End:          startTime.Add(time.Duration(slots) * slotDuration),
+		AveragePrice: bestSum / float64(slots),
+		Unit:         prices.Name,
+	}, nil
+}
+
+// CalendarFeedURL returns the URL of calendar feed. `publicURL` is the base URL which clients use to reach the service,
+// and the path of feed is returned alone when it is not configured.
+func CalendarFeedURL(publicURL, token string) string {
+	return fmt.Sprintf("%s/v1/calendar.ics?token=%s", strings.TrimSuffix(publicURL, "/"), url.QueryEscape(token))
+}
+
+// BuildCalendar returns the iCalendar (RFC 5545) document with an event for the cheapest and the most expensive
+// period of each day which has prices available. `feedID` keeps the ids of events stable between refreshes.
+func BuildCalendar(feedID string, now time.Time, hours int, days ...models.DailyPrice) (string, error) {
+	var calendar strings.Builder
+	writeLine := func(line string) { calendar.WriteString(foldLine(line) + "\r\n") }
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//AnhCaooo//Stormbreaker//EN")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("METHOD:PUBLISH")
+	writeLine("X-WR-CALNAME:Electricity prices")
+	writeLine("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeLine("X-PUBLISHED-TTL:PT1H")
+
+	for _, day := range days {
+		if !day.Available {
+			continue
+		}
+		for _, event := range []struct {
+			kind    string
+			summary string
+			find    func(models.PriceSeries, int) (*models.PriceWindow, error)
+		}{
+			{"cheapest", "Cheapest %d h electricity", CheapestWindow},
+			{"expensive", "Most expensive %d h electricity", MostExpensiveWindow},
+		} {
+			window, err := event.find(day.Prices, hours)
+			if err != nil {
+				return "", err
+			}
+			if window == nil {
+				continue
+			}
+			writeLine("BEGIN:VEVENT")
+			writeLine(fmt.Sprintf("UID:%s-%s-%s@stormbreaker", feedID, event.kind, window.Start.Format("20060102")))
+			writeLine("DTSTAMP:" + now.UTC().Format(ICALENDAR_TIME_FORMAT))
+			writeLine("DTSTART:" + window.Start.Format(ICALENDAR_TIME_FORMAT))
+			writeLine("DTEND:" + window.End.Format(ICALENDAR_TIME_FORMAT))
+			writeLine("SUMMARY:" + escapeText(fmt.Sprintf(event.summary, hours)))
+			writeLine("DESCRIPTION:" + escapeText(fmt.Sprintf("Average price %.2f %s", window.AveragePrice, window.Unit)))
+			writeLine("TRANSP:TRANSPARENT")
+			writeLine("END:VEVENT")
+		}
+	}
+	writeLine("END:VCALENDAR")
+	return calendar.String(), nil
+}
+
+// escapeText escapes the characters which have a meaning in iCalendar text values
+func escapeText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
+
+// foldLine splits lines longer than 75 octets, continuing them on the next line which starts with a space
+func foldLine(line string) string {
+	limit := 75
+	if len(line) <= limit {
+		return line
+	}
+	var folded strings.Builder
+	for len(line) > limit {
+		cut := limit
+		// do not split a multi-byte character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		folded.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// the leading space of continuation line counts to the limit
+		limit = 74
+	}
+	folded.WriteString(line)
+	return folded.String()
+}
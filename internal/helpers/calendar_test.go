@@ -0,0 +1,144 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+func calendarPrices(prices ...float64) models.PriceSeries {
+	series := models.PriceSeries{Name: "c/kWh"}
+	start := time.Date(2024, 12, 8, 22, 0, 0, 0, time.UTC)
+	for i, price := range prices {
+		series.Data = append(series.Data, models.Data{
+			TimeUTC: start.Add(time.Duration(i) * time.Hour).Format(DATE_TIME_FORMAT),
+			Price:   price,
+		})
+	}
+	return series
+}
+
+func TestPriceWindows(t *testing.T) {
+	prices := calendarPrices(5, 4, 1, 2, 3, 9, 8, 2)
+
+	cheapest, err := CheapestWindow(prices, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cheapest.Start != time.Date(2024, 12, 9, 0, 0, 0, 0, time.UTC) || cheapest.AveragePrice != 1.5 {
+		t.Errorf("unexpected cheapest window: %+v", cheapest)
+	}
+	if cheapest.End.Sub(cheapest.Start) != 2*time.Hour {
+		t.Errorf("unexpected length of cheapest window: %s", cheapest.End.Sub(cheapest.Start))
+	}
+
+	expensive, err := MostExpensiveWindow(prices, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expensive.Start != time.Date(2024, 12, 9, 3, 0, 0, 0, time.UTC) || expensive.AveragePrice != 8.5 {
+		t.Errorf("unexpected most expensive window: %+v", expensive)
+	}
+
+	if window, _ := CheapestWindow(prices, 9); window != nil {
+		t.Errorf("expected no window when there are fewer slots than hours, got %+v", window)
+	}
+}
+
+func TestPriceWindowsWithQuarterHours(t *testing.T) {
+	// 3 hours in 15-minute slots
+	prices := models.PriceSeries{Name: "c/kWh"}
+	start := time.Date(2025, 10, 8, 22, 0, 0, 0, time.UTC)
+	for i, price := range []float64{9, 8, 7, 6, 2, 1, 1, 2, 4, 5, 10, 3} {
+		prices.Data = append(prices.Data, models.Data{
+			TimeUTC: start.Add(time.Duration(i) * 15 * time.Minute).Format(DATE_TIME_FORMAT),
+			Price:   price,
+		})
+	}
+
+	cheapest, err := CheapestWindow(prices, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cheapest.Start != time.Date(2025, 10, 8, 23, 0, 0, 0, time.UTC) || cheapest.AveragePrice != 1.5 {
+		t.Errorf("unexpected cheapest window: %+v", cheapest)
+	}
+	if cheapest.End.Sub(cheapest.Start) != time.Hour {
+		t.Errorf("unexpected length of cheapest window: %s", cheapest.End.Sub(cheapest.Start))
+	}
+
+	expensive, err := MostExpensiveWindow(prices, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expensive.Start != time.Date(2025, 10, 8, 22, 0, 0, 0, time.UTC) || expensive.End != time.Date(2025, 10, 9, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("unexpected most expensive window: %+v", expensive)
+	}
+
+	if window, _ := CheapestWindow(prices, 4); window != nil {
+		t.Errorf("expected no window when prices do not cover the hours, got %+v", window)
+	}
+}
+
+func TestCalendarFeedURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		publicURL string
+		expected  string
+	}{
+		{name: "configured public URL", publicURL: "https://api.example.com", expected: "https://api.example.com/v1/calendar.ics?token=abc123"},
+		{name: "public URL with trailing slash", publicURL: "https://example.com/stormbreaker/", expected: "https://example.com/stormbreaker/v1/calendar.ics?token=abc123"},
+		{name: "no public URL", publicURL: "", expected: "/v1/calendar.ics?token=abc123"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := CalendarFeedURL(test.publicURL, "abc123"); got != test.expected {
+				t.Errorf("CalendarFeedURL() = %q, want %q", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestBuildCalendar(t *testing.T) {
+	now := time.Date(2024, 12, 9, 12, 0, 0, 0, time.UTC)
+	today := models.DailyPrice{Available: true, Prices: calendarPrices(5, 4, 1, 2, 3, 9, 8, 2)}
+	tomorrow := models.DailyPrice{Available: false}
+
+	calendar, err := BuildCalendar("feed", now, 2, today, tomorrow)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:feed-cheapest-20241209@stormbreaker\r\n",
+		"DTSTART:20241209T000000Z\r\nDTEND:20241209T020000Z\r\n",
+		"SUMMARY:Most expensive 2 h electricity\r\n",
+		"DESCRIPTION:Average price 1.50 c/kWh\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(calendar, expected) {
+			t.Errorf("calendar does not contain %q:\n%s", expected, calendar)
+		}
+	}
+	if count := strings.Count(calendar, "BEGIN:VEVENT"); count != 2 {
+		t.Errorf("unexpected amount of events: got %d, want 2", count)
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("a", 150)
+	folded := foldLine(line)
+	for _, part := range strings.Split(folded, "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("folded line is longer than 75 octets: %q", part)
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != line {
+		t.Errorf("unfolded line differs from original")
+	}
+}
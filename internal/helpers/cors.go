@@ -0,0 +1,43 @@
+// AnhCao 2024
+package helpers
+
+import "strings"
+
+// IsAllowedOrigin checks the origin of browser request against the allowed origins.
+// An allowed origin is either "*" (any origin), an exact origin (ex: "https://app.example.com")
+// or an origin with wildcard subdomain (ex: "https://*.example.com"), which matches any subdomain but not the domain itself.
+func IsAllowedOrigin(origin string, allowedOrigins []string) bool {
+	if origin == "" {
+		return false
+	}
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		prefix, suffix, isWildcard := strings.Cut(allowed, "*")
+		if !isWildcard || !strings.HasPrefix(strings.ToLower(origin), strings.ToLower(prefix)) {
+			continue
+		}
+		rest := origin[len(prefix):]
+		if len(rest) <= len(suffix) || !strings.EqualFold(rest[len(rest)-len(suffix):], suffix) {
+			continue
+		}
+		if isValidSubdomain(rest[:len(rest)-len(suffix)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidSubdomain checks that the part of origin which wildcard matched is only subdomain labels,
+// so that wildcard cannot match a different scheme, port or path
+func isValidSubdomain(subdomain string) bool {
+	for _, c := range subdomain {
+		isLetter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !isDigit && c != '-' && c != '.' {
+			return false
+		}
+	}
+	return !strings.HasPrefix(subdomain, ".") && !strings.HasSuffix(subdomain, ".")
+}
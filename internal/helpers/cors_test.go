@@ -0,0 +1,38 @@
+// AnhCao 2024
+package helpers
+
+import "testing"
+
+func TestIsAllowedOrigin(t *testing.T) {
+	allowed := []string{"https://dashboard.stormbreaker.fi", "https://*.example.com", "http://localhost:3000"}
+
+	tests := []struct {
+		name     string
+		origin   string
+		allowed  []string
+		expected bool
+	}{
+		{name: "exact origin", origin: "https://dashboard.stormbreaker.fi", allowed: allowed, expected: true},
+		{name: "exact origin ignores case", origin: "https://Dashboard.Stormbreaker.fi", allowed: allowed, expected: true},
+		{name: "origin with port", origin: "http://localhost:3000", allowed: allowed, expected: true},
+		{name: "other port", origin: "http://localhost:8080", allowed: allowed, expected: false},
+		{name: "wildcard subdomain", origin: "https://app.example.com", allowed: allowed, expected: true},
+		{name: "wildcard nested subdomain", origin: "https://eu.app.example.com", allowed: allowed, expected: true},
+		{name: "wildcard does not match domain itself", origin: "https://example.com", allowed: allowed, expected: false},
+		{name: "wildcard does not match other scheme", origin: "http://app.example.com", allowed: allowed, expected: false},
+		{name: "wildcard does not match lookalike domain", origin: "https://app.evil-example.com", allowed: allowed, expected: false},
+		{name: "wildcard does not match other domain", origin: "https://evil.com/.example.com", allowed: allowed, expected: false},
+		{name: "wildcard does not match port", origin: "https://app.example.com:8443", allowed: allowed, expected: false},
+		{name: "any origin", origin: "https://anything.fi", allowed: []string{"*"}, expected: true},
+		{name: "missing origin", origin: "", allowed: []string{"*"}, expected: false},
+		{name: "no allowed origins", origin: "https://app.example.com", allowed: nil, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := IsAllowedOrigin(test.origin, test.allowed); got != test.expected {
+				t.Errorf("IsAllowedOrigin(%q) = %v, want %v", test.origin, got, test.expected)
+			}
+		})
+	}
+}
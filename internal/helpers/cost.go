@@ -0,0 +1,128 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+// costAccumulator sums up the cost slots which belong to the same period
+type costAccumulator struct {
+	period      string
+	consumption float64
+	cost        float64
+	priceSum    float64
+	slots       int
+}
+
+func (a *costAccumulator) add(slot models.CostSlot) {
+	a.consumption += slot.Consumption
+	a.cost += slot.Cost
+	a.priceSum += slot.Price
+	a.slots++
+}
+
+func (a *costAccumulator) summary() models.CostSummary {
+	summary := models.CostSummary{
+		Period:      a.period,
+		Consumption: a.consumption,
+		Cost:        a.cost,
+	}
+	if a.consumption > 0 {
+		// cost is in EUR and price is in c/kWh
+		summary.WeightedAveragePrice = a.cost * 100 / a.consumption
+	}
+	if a.slots > 0 {
+		summary.AveragePrice = a.priceSum / float64(a.slots)
+	}
+	return summary
+}
+
+// CalculateCost joins the consumption with the prices of each slot (price settings are applied already)
+// and adds the electricity tax. It returns the cost per slot, per day and per month, together with
+// the consumption-weighted average price and the plain average price of each period.
+// Slots are as long as the slots of prices (an hour or 15 minutes, see SlotDuration). Consumption which is
+// measured in smaller slots is summed up into the price slot it belongs to.
+func CalculateCost(
+	startDate, endDate string,
+	prices models.PriceSeries,
+	consumption []models.Consumption,
+) (*models.CostResponse, error) {
+	slotDuration := SlotDuration(prices.Data)
+	usages := make(map[string]float64, len(consumption))
+	for _, usage := range consumption {
+		slot := usage.Time.UTC().Truncate(slotDuration).Format(DATE_TIME_FORMAT)
+		usages[slot] += usage.Consumption
+	}
+
+	response := &models.CostResponse{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Currency:  models.CURRENCY,
+		Unit:      prices.Name,
+		Months:    make([]models.CostSummary, 0),
+		Days:      make([]models.CostSummary, 0),
+		Slots:     make([]models.CostSlot, 0, len(prices.Data)),
+	}
+
+	total := &costAccumulator{}
+	var days, months []*costAccumulator
+	for _, price := range prices.Data {
+		localTime, err := time.Parse(DATE_TIME_FORMAT, price.Time)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse time of price slot: %s", err.Error())
+		}
+
+		usage := usages[price.TimeUTC]
+		slotPrice := PriceWithTaxes(price)
+		slot := models.CostSlot{
+			TimeUTC:     price.TimeUTC,
+			Time:        price.Time,
+			Consumption: usage,
+			Price:       slotPrice,
+			Cost:        usage * slotPrice / 100,
+		}
+		response.Slots = append(response.Slots, slot)
+
+		day := localTime.Format(DATE_FORMAT)
+		if len(days) == 0 || days[len(days)-1].period != day {
+			days = append(days, &costAccumulator{period: day})
+		}
+		month := localTime.Format(MONTH_FORMAT)
+		if len(months) == 0 || months[len(months)-1].period != month {
+			months = append(months, &costAccumulator{period: month})
+		}
+		days[len(days)-1].add(slot)
+		months[len(months)-1].add(slot)
+		total.add(slot)
+	}
+
+	for _, day := range days {
+		response.Days = append(response.Days, day.summary())
+	}
+	for _, month := range months {
+		response.Months = append(response.Months, month.summary())
+	}
+	response.Total = total.summary()
+	return response, nil
+}
+
+// PriceWithTaxes returns the price of given slot with the electricity tax added.
+// The electricity tax is charged with VAT when VAT is included in the price of the slot.
+func PriceWithTaxes(price models.Data) float64 {
+	tax := models.ELECTRICITY_TAX
+	if price.IncludeVat == "1" {
+		tax *= vatFactor(price)
+	}
+	return price.Price + tax
+}
+
+// vatFactor returns the VAT factor of given slot. External source might leave it empty, then the default VAT factor is applied.
+func vatFactor(price models.Data) float64 {
+	if price.VatFactor > 0 {
+		return price.VatFactor
+	}
+	return models.VAT_FACTOR
+}
@@ -0,0 +1,137 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestCalculateCost(t *testing.T) {
+	consumption := []models.Consumption{
+		{UserID: "12345", Time: time.Date(2024, 11, 30, 21, 0, 0, 0, time.UTC), Consumption: 1},
+		// quarter-hour readings are summed up into the hour
+		{UserID: "12345", Time: time.Date(2024, 11, 30, 22, 0, 0, 0, time.UTC), Consumption: 1},
+		{UserID: "12345", Time: time.Date(2024, 11, 30, 22, 15, 0, 0, time.UTC), Consumption: 2},
+	}
+
+	tests := []struct {
+		name       string
+		includeVat string
+		tax        float64
+	}{
+		{
+			name:       "without VAT",
+			includeVat: "0",
+			tax:        models.ELECTRICITY_TAX,
+		},
+		{
+			name:       "with VAT applied to electricity tax",
+			includeVat: "1",
+			tax:        models.ELECTRICITY_TAX * 1.255,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prices := models.PriceSeries{
+				Name: "c/kWh",
+				Data: []models.Data{
+					{TimeUTC: "2024-11-30 21:00:00", Time: "2024-11-30 23:00:00", Price: 10, VatFactor: 1.255, IncludeVat: test.includeVat},
+					{TimeUTC: "2024-11-30 22:00:00", Time: "2024-12-01 00:00:00", Price: 2, VatFactor: 1.255, IncludeVat: test.includeVat},
+					{TimeUTC: "2024-11-30 23:00:00", Time: "2024-12-01 01:00:00", Price: 4, VatFactor: 1.255, IncludeVat: test.includeVat},
+				},
+			}
+			got, err := CalculateCost("2024-11-30", "2024-12-01", prices, consumption)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(got.Slots) != 3 || len(got.Days) != 2 || len(got.Months) != 2 {
+				t.Fatalf("unexpected amount of slots (%d), days (%d) or months (%d)", len(got.Slots), len(got.Days), len(got.Months))
+			}
+
+			if got.Slots[1].Consumption != 3 {
+				t.Errorf("expected consumption 3 in second slot, got %f", got.Slots[1].Consumption)
+			}
+			if got.Slots[2].Cost != 0 {
+				t.Errorf("expected no cost without consumption, got %f", got.Slots[2].Cost)
+			}
+
+			expectedTotalCost := (1*(10+test.tax) + 3*(2+test.tax)) / 100
+			if !almostEqual(got.Total.Cost, expectedTotalCost) {
+				t.Errorf("expected total cost %f, got %f", expectedTotalCost, got.Total.Cost)
+			}
+
+			december := got.Months[1]
+			if december.Period != "2024-12" {
+				t.Errorf("expected period 2024-12, got %s", december.Period)
+			}
+			if !almostEqual(december.WeightedAveragePrice, 2+test.tax) {
+				t.Errorf("expected weighted average price %f, got %f", 2+test.tax, december.WeightedAveragePrice)
+			}
+			if !almostEqual(december.AveragePrice, 3+test.tax) {
+				t.Errorf("expected average price %f, got %f", 3+test.tax, december.AveragePrice)
+			}
+		})
+	}
+}
+
+func TestCalculateCostQuarterHourPrices(t *testing.T) {
+	consumption := []models.Consumption{
+		{UserID: "12345", Time: time.Date(2024, 11, 30, 22, 0, 0, 0, time.UTC), Consumption: 1},
+		{UserID: "12345", Time: time.Date(2024, 11, 30, 22, 15, 0, 0, time.UTC), Consumption: 2},
+		// readings of 5 minutes are summed up into the quarter they belong to
+		{UserID: "12345", Time: time.Date(2024, 11, 30, 22, 45, 0, 0, time.UTC), Consumption: 0.5},
+		{UserID: "12345", Time: time.Date(2024, 11, 30, 22, 50, 0, 0, time.UTC), Consumption: 0.5},
+	}
+	prices := models.PriceSeries{
+		Name: "c/kWh",
+		Data: []models.Data{
+			{TimeUTC: "2024-11-30 22:00:00", Time: "2024-12-01 00:00:00", Price: 2, IncludeVat: "0"},
+			{TimeUTC: "2024-11-30 22:15:00", Time: "2024-12-01 00:15:00", Price: 4, IncludeVat: "0"},
+			{TimeUTC: "2024-11-30 22:30:00", Time: "2024-12-01 00:30:00", Price: 6, IncludeVat: "0"},
+			{TimeUTC: "2024-11-30 22:45:00", Time: "2024-12-01 00:45:00", Price: 8, IncludeVat: "0"},
+		},
+	}
+
+	got, err := CalculateCost("2024-12-01", "2024-12-01", prices, consumption)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Slots) != 4 {
+		t.Fatalf("expected 4 slots, got %d", len(got.Slots))
+	}
+
+	expectedConsumption := []float64{1, 2, 0, 1}
+	for i, slot := range got.Slots {
+		if !almostEqual(slot.Consumption, expectedConsumption[i]) {
+			t.Errorf("expected consumption %f in slot %s, got %f", expectedConsumption[i], slot.TimeUTC, slot.Consumption)
+		}
+	}
+
+	tax := models.ELECTRICITY_TAX
+	expectedTotalCost := (1*(2+tax) + 2*(4+tax) + 1*(8+tax)) / 100
+	if !almostEqual(got.Total.Cost, expectedTotalCost) {
+		t.Errorf("expected total cost %f, got %f", expectedTotalCost, got.Total.Cost)
+	}
+	if !almostEqual(got.Total.AveragePrice, 5+tax) {
+		t.Errorf("expected average price %f, got %f", 5+tax, got.Total.AveragePrice)
+	}
+}
+
+func TestCalculateCostInvalidTime(t *testing.T) {
+	prices := models.PriceSeries{
+		Name: "c/kWh",
+		Data: []models.Data{{TimeUTC: "2024-11-30 21:00:00", Time: "invalid", Price: 10}},
+	}
+	if _, err := CalculateCost("2024-11-30", "2024-11-30", prices, nil); err == nil {
+		t.Errorf("expected error for invalid time of price slot, got nil")
+	}
+}
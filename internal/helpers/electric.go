@@ -147,39 +147,67 @@ func getTomorrowPrices(response models.PriceResponse) (tomorrowPrice *models.Dai
 	return
 }
 
-// MapPriceSettingsWithTodayTomorrowSpotPrice updates the prices in the given
-// TodayTomorrowPrice struct based on the provided PriceSettings. It modifies
-// the IncludeVat and Price fields of the prices for both today and tomorrow
-// if they are available.
-//
-// Parameters:
-//   - priceSettings: A pointer to a PriceSettings struct containing the settings
-//     to be applied to the prices.
-//   - todayTomorrowPrice: A pointer to a TodayTomorrowPrice struct containing
-//     the prices for today and tomorrow.
-//
-// todo: current implementation is not correct. The data after mapping is not correct 100%
+// MapPriceSettingsWithTodayTomorrowSpotPrice applies the price settings to the plain prices (no margin and no VAT)
+// of today and tomorrow in place, the same way as ApplyPriceSettings does to any other prices, and returns them.
 func MapPriceSettingsWithTodayTomorrowSpotPrice(
 	priceSettings *models.PriceSettings,
 	todayTomorrowPrice *models.TodayTomorrowPrice,
 ) *models.TodayTomorrowPrice {
-	// Update logic for prices
-	updatePrices := func(prices []models.Data, settings *models.PriceSettings) {
-		for i := range prices {
-			prices[i].IncludeVat = fmt.Sprintf("%d", parseVatIncludedFromBoolToInt32(settings.VatIncluded))
-			prices[i].Price += settings.Marginal
+	for _, prices := range []*models.PriceSeries{&todayTomorrowPrice.Today.Prices, &todayTomorrowPrice.Tomorrow.Prices} {
+		for i := range prices.Data {
+			ApplyPriceSettings(&prices.Data[i], priceSettings)
 		}
 	}
+	return todayTomorrowPrice
+}
 
-	// Update today's prices
-	if todayTomorrowPrice.Today.Available {
-		updatePrices(todayTomorrowPrice.Today.Prices.Data, priceSettings)
+// CurrentPrice returns the price of the slot of today or tomorrow which is in force at `now`.
+// A slot lasts until the next slot begins, and the last slot lasts one hour. Nil is returned when no slot covers `now`.
+func CurrentPrice(prices *models.TodayTomorrowPrice, now time.Time) (*models.CurrentPrice, error) {
+	var current *models.CurrentPrice
+	var currentEnd time.Time
+	for _, series := range []models.PriceSeries{prices.Today.Prices, prices.Tomorrow.Prices} {
+		for _, slot := range series.Data {
+			start, err := time.Parse(DATE_TIME_FORMAT, slot.TimeUTC)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse time of price slot: %s", err.Error())
+			}
+			if current != nil && currentEnd.IsZero() {
+				// the slot before ends when this one begins
+				currentEnd = start
+			}
+			if start.After(now) {
+				continue
+			}
+			current = &models.CurrentPrice{Time: slot.Time, TimeUTC: slot.TimeUTC, Price: slot.Price, Unit: series.Name}
+			currentEnd = time.Time{}
+		}
 	}
-
-	// Update tomorrow's prices
-	if todayTomorrowPrice.Tomorrow.Available {
-		updatePrices(todayTomorrowPrice.Tomorrow.Prices.Data, priceSettings)
+	if current == nil {
+		return nil, nil
+	}
+	if currentEnd.IsZero() {
+		start, _ := time.Parse(DATE_TIME_FORMAT, current.TimeUTC)
+		currentEnd = start.Add(time.Hour)
 	}
+	if !now.Before(currentEnd) {
+		return nil, nil
+	}
+	return current, nil
+}
 
-	return todayTomorrowPrice
+// PriceStatistics returns the lowest, highest and average price of series. Nil is returned when the series has no prices.
+func PriceStatistics(prices models.PriceSeries) *models.PriceStats {
+	if len(prices.Data) == 0 {
+		return nil
+	}
+	stats := &models.PriceStats{Min: prices.Data[0].Price, Max: prices.Data[0].Price, Unit: prices.Name}
+	sum := 0.0
+	for _, slot := range prices.Data {
+		stats.Min = min(stats.Min, slot.Price)
+		stats.Max = max(stats.Max, slot.Price)
+		sum += slot.Price
+	}
+	stats.Average = sum / float64(len(prices.Data))
+	return stats
 }
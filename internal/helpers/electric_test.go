@@ -111,3 +111,87 @@ func TestGetTodayAndTomorrowDateAsString(t *testing.T) {
 		t.Errorf("Expected tomorrow: %s, but got: %s", expectedTomorrow, tomorrow)
 	}
 }
+
+func TestCurrentPrice(t *testing.T) {
+	prices := &models.TodayTomorrowPrice{
+		Today: models.DailyPrice{Available: true, Prices: models.PriceSeries{Name: "c/kWh", Data: []models.Data{
+			{TimeUTC: "2024-12-09 10:00:00", Time: "2024-12-09 12:00:00", Price: 5},
+			{TimeUTC: "2024-12-09 11:00:00", Time: "2024-12-09 13:00:00", Price: 6},
+		}}},
+		Tomorrow: models.DailyPrice{Available: true, Prices: models.PriceSeries{Name: "c/kWh", Data: []models.Data{
+			{TimeUTC: "2024-12-09 22:00:00", Time: "2024-12-10 00:00:00", Price: 1},
+		}}},
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected *models.CurrentPrice
+	}{
+		{
+			name:     "first slot",
+			now:      time.Date(2024, 12, 9, 10, 30, 0, 0, time.UTC),
+			expected: &models.CurrentPrice{TimeUTC: "2024-12-09 10:00:00", Time: "2024-12-09 12:00:00", Price: 5, Unit: "c/kWh"},
+		},
+		{
+			name:     "start of slot",
+			now:      time.Date(2024, 12, 9, 11, 0, 0, 0, time.UTC),
+			expected: &models.CurrentPrice{TimeUTC: "2024-12-09 11:00:00", Time: "2024-12-09 13:00:00", Price: 6, Unit: "c/kWh"},
+		},
+		{
+			name:     "slot lasts until the next one",
+			now:      time.Date(2024, 12, 9, 21, 59, 0, 0, time.UTC),
+			expected: &models.CurrentPrice{TimeUTC: "2024-12-09 11:00:00", Time: "2024-12-09 13:00:00", Price: 6, Unit: "c/kWh"},
+		},
+		{
+			name:     "slot of tomorrow",
+			now:      time.Date(2024, 12, 9, 22, 15, 0, 0, time.UTC),
+			expected: &models.CurrentPrice{TimeUTC: "2024-12-09 22:00:00", Time: "2024-12-10 00:00:00", Price: 1, Unit: "c/kWh"},
+		},
+		{name: "before first slot", now: time.Date(2024, 12, 9, 9, 59, 0, 0, time.UTC), expected: nil},
+		{name: "after last slot", now: time.Date(2024, 12, 9, 23, 0, 0, 0, time.UTC), expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := CurrentPrice(prices, test.now)
+			if err != nil {
+				t.Fatalf("CurrentPrice() error = %v", err)
+			}
+			if (got == nil) != (test.expected == nil) || (got != nil && *got != *test.expected) {
+				t.Errorf("CurrentPrice() = %+v, want %+v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestPriceStatistics(t *testing.T) {
+	tests := []struct {
+		name     string
+		prices   models.PriceSeries
+		expected *models.PriceStats
+	}{
+		{
+			name: "several slots",
+			prices: models.PriceSeries{Name: "c/kWh", Data: []models.Data{
+				{Price: 4}, {Price: -1}, {Price: 12}, {Price: 5},
+			}},
+			expected: &models.PriceStats{Min: -1, Max: 12, Average: 5, Unit: "c/kWh"},
+		},
+		{
+			name:     "single slot",
+			prices:   models.PriceSeries{Name: "c/kWh", Data: []models.Data{{Price: 3}}},
+			expected: &models.PriceStats{Min: 3, Max: 3, Average: 3, Unit: "c/kWh"},
+		},
+		{name: "no slots", prices: models.PriceSeries{Name: "c/kWh"}, expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := PriceStatistics(test.prices)
+			if (got == nil) != (test.expected == nil) || (got != nil && *got != *test.expected) {
+				t.Errorf("PriceStatistics() = %+v, want %+v", got, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+// SettingsAt returns the price settings which were in force at given time.
+// `history` needs to be ordered by effective time. If the time is before the first change,
+// the earliest known settings are returned. If there is no history at all, `current` is returned.
+// While settings were deleted, `defaults` were in force.
+func SettingsAt(history []models.PriceSettingsHistory, at time.Time, current, defaults *models.PriceSettings) *models.PriceSettings {
+	if len(history) == 0 {
+		return current
+	}
+
+	entry := &history[0]
+	for i := range history {
+		if history[i].EffectiveFrom.After(at) {
+			break
+		}
+		entry = &history[i]
+	}
+	if entry.Deleted {
+		return defaults
+	}
+	return &entry.PriceSettings
+}
+
+// ApplyPriceSettings applies the price settings to a plain spot price (no margin and no VAT included).
+// The margin is added to the spot price, and VAT is applied to both of them if user has VAT included.
+func ApplyPriceSettings(price *models.Data, settings *models.PriceSettings) {
+	price.Price += settings.Marginal
+	if settings.VatIncluded {
+		price.Price *= vatFactor(*price)
+	}
+	price.IncludeVat = fmt.Sprintf("%d", parseVatIncludedFromBoolToInt32(settings.VatIncluded))
+}
+
+// ApplyPriceSettingsHistory applies to each slot of plain spot prices the price settings which were in force at that slot.
+func ApplyPriceSettingsHistory(
+	prices *models.PriceSeries,
+	history []models.PriceSettingsHistory,
+	current, defaults *models.PriceSettings,
+) error {
+	for i := range prices.Data {
+		slotTime, err := time.Parse(DATE_TIME_FORMAT, prices.Data[i].TimeUTC)
+		if err != nil {
+			return fmt.Errorf("failed to parse time of price slot: %s", err.Error())
+		}
+		ApplyPriceSettings(&prices.Data[i], SettingsAt(history, slotTime, current, defaults))
+	}
+	return nil
+}
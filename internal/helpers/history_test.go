@@ -0,0 +1,105 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+func TestApplyPriceSettingsHistory(t *testing.T) {
+	history := []models.PriceSettingsHistory{
+		{
+			PriceSettings: models.PriceSettings{UserID: "12345", Marginal: 0.5, VatIncluded: false},
+			EffectiveFrom: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			PriceSettings: models.PriceSettings{UserID: "12345", Marginal: 1, VatIncluded: true},
+			EffectiveFrom: time.Date(2024, 12, 9, 12, 30, 0, 0, time.UTC),
+		},
+	}
+	current := &models.PriceSettings{UserID: "12345", Marginal: 2, VatIncluded: false}
+	defaults := &models.PriceSettings{UserID: "12345", Marginal: 3, VatIncluded: false}
+	// settings were deleted and created again
+	recreated := append(history,
+		models.PriceSettingsHistory{
+			PriceSettings: models.PriceSettings{UserID: "12345", Version: 3},
+			EffectiveFrom: time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC),
+			Deleted:       true,
+		},
+		models.PriceSettingsHistory{
+			PriceSettings: models.PriceSettings{UserID: "12345", Marginal: 2, VatIncluded: false, Version: 4},
+			EffectiveFrom: time.Date(2024, 12, 11, 0, 0, 0, 0, time.UTC),
+		},
+	)
+
+	tests := []struct {
+		name               string
+		history            []models.PriceSettingsHistory
+		timeUTC            string
+		expectedPrice      float64
+		expectedIncludeVat string
+	}{
+		{
+			name:               "slot before first change uses earliest known settings",
+			history:            history,
+			timeUTC:            "2024-11-30 10:00:00",
+			expectedPrice:      10.5,
+			expectedIncludeVat: "0",
+		},
+		{
+			name:               "slot before second change",
+			history:            history,
+			timeUTC:            "2024-12-09 12:00:00",
+			expectedPrice:      10.5,
+			expectedIncludeVat: "0",
+		},
+		{
+			name:               "slot after second change",
+			history:            history,
+			timeUTC:            "2024-12-09 13:00:00",
+			expectedPrice:      11 * 1.255,
+			expectedIncludeVat: "1",
+		},
+		{
+			name:               "slot after deletion uses default settings",
+			history:            recreated,
+			timeUTC:            "2024-12-10 13:00:00",
+			expectedPrice:      13,
+			expectedIncludeVat: "0",
+		},
+		{
+			name:               "slot after settings were created again",
+			history:            recreated,
+			timeUTC:            "2024-12-11 13:00:00",
+			expectedPrice:      12,
+			expectedIncludeVat: "0",
+		},
+		{
+			name:               "no history uses current settings",
+			history:            nil,
+			timeUTC:            "2024-12-09 13:00:00",
+			expectedPrice:      12,
+			expectedIncludeVat: "0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prices := models.PriceSeries{
+				Name: "c/kWh",
+				Data: []models.Data{{TimeUTC: test.timeUTC, Price: 10, VatFactor: 1.255}},
+			}
+			if err := ApplyPriceSettingsHistory(&prices, test.history, current, defaults); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !almostEqual(prices.Data[0].Price, test.expectedPrice) {
+				t.Errorf("expected price %f, got %f", test.expectedPrice, prices.Data[0].Price)
+			}
+			if prices.Data[0].IncludeVat != test.expectedIncludeVat {
+				t.Errorf("expected includeVat %s, got %s", test.expectedIncludeVat, prices.Data[0].IncludeVat)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"fmt"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+// ValidateHouseholdMember checks whether the owner of household can invite the given member
+func ValidateHouseholdMember(member *models.HouseholdMember) error {
+	if member.UserID == "" {
+		return fmt.Errorf("user_id of the invited user is required")
+	}
+	if member.UserID == member.OwnerID {
+		return fmt.Errorf("owner cannot invite themselves to the household")
+	}
+	return ValidateHouseholdRole(member.Role)
+}
+
+// ValidateHouseholdRole checks whether the given role can be granted to a member of household
+func ValidateHouseholdRole(role string) error {
+	switch role {
+	case models.HOUSEHOLD_ROLE_VIEWER, models.HOUSEHOLD_ROLE_EDITOR:
+		return nil
+	default:
+		return fmt.Errorf("role should have valid value: '%s', '%s'", models.HOUSEHOLD_ROLE_VIEWER, models.HOUSEHOLD_ROLE_EDITOR)
+	}
+}
+
+// CanEditHousehold reports whether the given role allows to change price settings and price alerts of household
+func CanEditHousehold(role string) bool {
+	return role == models.HOUSEHOLD_ROLE_OWNER || role == models.HOUSEHOLD_ROLE_EDITOR
+}
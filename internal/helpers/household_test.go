@@ -0,0 +1,46 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"testing"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+func TestValidateHouseholdMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		member  models.HouseholdMember
+		wantErr bool
+	}{
+		{
+			name:    "valid viewer",
+			member:  models.HouseholdMember{OwnerID: "12345", UserID: "67890", Role: models.HOUSEHOLD_ROLE_VIEWER},
+			wantErr: false,
+		},
+		{
+			name:    "missing invited user",
+			member:  models.HouseholdMember{OwnerID: "12345", Role: models.HOUSEHOLD_ROLE_EDITOR},
+			wantErr: true,
+		},
+		{
+			name:    "owner invites themselves",
+			member:  models.HouseholdMember{OwnerID: "12345", UserID: "12345", Role: models.HOUSEHOLD_ROLE_EDITOR},
+			wantErr: true,
+		},
+		{
+			name:    "owner role cannot be granted",
+			member:  models.HouseholdMember{OwnerID: "12345", UserID: "67890", Role: models.HOUSEHOLD_ROLE_OWNER},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateHouseholdMember(&test.member)
+			if (err != nil) != test.wantErr {
+				t.Errorf("ValidateHouseholdMember() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PENDING_PRICES_MAX_AGE is how long clients may reuse today's prices after 14:00 while tomorrow's prices are not published yet
+const PENDING_PRICES_MAX_AGE time.Duration = 5 * time.Minute
+
+// PAST_PRICES_MAX_AGE is how long clients may reuse the market prices of the days which have passed
+const PAST_PRICES_MAX_AGE time.Duration = 24 * time.Hour
+
+// Freshness describes the validity of a response for HTTP caching (RFC 9111)
+type Freshness struct {
+	LastModified time.Time // time when the content of response last changed
+	Expires      time.Time // time when the content of response is expected to change
+}
+
+// ContentETag returns the strong entity tag of response body. The same body always gets the same tag.
+func ContentETag(body []byte) string {
+	hash := sha256.Sum256(body)
+	return fmt.Sprintf(`"%s"`, hex.EncodeToString(hash[:16]))
+}
+
+// SetCacheHeaders sets `ETag`, `Last-Modified` and `Cache-Control` of response. The response is private to the user
+// and may be reused until it expires.
+func SetCacheHeaders(header http.Header, etag string, freshness Freshness, now time.Time) {
+	maxAge := int(freshness.Expires.Sub(now).Seconds())
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	header.Set("ETag", etag)
+	if !freshness.LastModified.IsZero() {
+		header.Set("Last-Modified", freshness.LastModified.UTC().Format(http.TimeFormat))
+	}
+	header.Set("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge))
+}
+
+// IsNotModified reports whether client has the current version of response already, so `304 Not Modified` can be returned.
+// `If-None-Match` takes precedence over `If-Modified-Since` when client sends both.
+func IsNotModified(header http.Header, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			// weak comparison: "W/" prefix is ignored
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince := header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	// Last-Modified header has only one second precision
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// TodayTomorrowPricesExpiry returns when today's and tomorrow's prices are expected to change. Once tomorrow's prices are
+// available, they do not change until the end of day (23:59). Before that they change at 14:00 when tomorrow's prices are published,
+// or soon after it when publishing is late. The times are on the day of `now` in Finnish time.
+func TodayTomorrowPricesExpiry(now time.Time, tomorrowAvailable bool) (time.Time, error) {
+	location, err := loadHelsinkiLocation()
+	if err != nil {
+		return now, err
+	}
+	year, month, day := now.In(location).Date()
+	if tomorrowAvailable {
+		return time.Date(year, month, day, 23, 59, 0, 0, location).UTC(), nil
+	}
+	publishTime := time.Date(year, month, day, 14, 0, 0, 0, location).UTC()
+	if now.Before(publishTime) {
+		return publishTime, nil
+	}
+	return now.Add(PENDING_PRICES_MAX_AGE), nil
+}
+
+// MarketPricesFreshness returns the validity of market prices until `endDate` (YYYY-MM-DD). Prices of the days which have passed
+// in Finnish time do not change, neither do the price settings which were in force on those days, so they may be reused for
+// `PAST_PRICES_MAX_AGE`. False is returned when the range reaches today, because its prices change when new prices are published.
+func MarketPricesFreshness(endDate string, now time.Time) (Freshness, bool) {
+	location, err := loadHelsinkiLocation()
+	if err != nil {
+		return Freshness{}, false
+	}
+	end, err := time.ParseInLocation(DATE_FORMAT, endDate, location)
+	if err != nil || now.Before(end.AddDate(0, 0, 1)) {
+		return Freshness{}, false
+	}
+	return Freshness{Expires: now.Add(PAST_PRICES_MAX_AGE)}, true
+}
@@ -0,0 +1,157 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestContentETag(t *testing.T) {
+	first := ContentETag([]byte(`{"today":{}}`))
+	if first != ContentETag([]byte(`{"today":{}}`)) {
+		t.Errorf("ContentETag() of the same body should be the same")
+	}
+	if first == ContentETag([]byte(`{"tomorrow":{}}`)) {
+		t.Errorf("ContentETag() of different bodies should be different")
+	}
+	if len(first) != 34 || first[0] != '"' || first[len(first)-1] != '"' {
+		t.Errorf("ContentETag() = %s, want quoted strong entity tag", first)
+	}
+}
+
+func TestSetCacheHeaders(t *testing.T) {
+	now := time.Date(2024, 12, 9, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		freshness    Freshness
+		cacheControl string
+		lastModified string
+	}{
+		{
+			name:         "max-age until expiry",
+			freshness:    Freshness{LastModified: now.Add(-time.Hour), Expires: now.Add(2 * time.Hour)},
+			cacheControl: "private, max-age=7200",
+			lastModified: "Mon, 09 Dec 2024 11:00:00 GMT",
+		},
+		{
+			name:         "expired response is revalidated",
+			freshness:    Freshness{Expires: now.Add(-time.Minute)},
+			cacheControl: "private, max-age=0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			header := http.Header{}
+			SetCacheHeaders(header, `"abc"`, test.freshness, now)
+			if got := header.Get("ETag"); got != `"abc"` {
+				t.Errorf("ETag = %s, want \"abc\"", got)
+			}
+			if got := header.Get("Cache-Control"); got != test.cacheControl {
+				t.Errorf("Cache-Control = %s, want %s", got, test.cacheControl)
+			}
+			if got := header.Get("Last-Modified"); got != test.lastModified {
+				t.Errorf("Last-Modified = %s, want %s", got, test.lastModified)
+			}
+		})
+	}
+}
+
+func TestIsNotModified(t *testing.T) {
+	lastModified := time.Date(2024, 12, 9, 11, 0, 0, 500, time.UTC)
+	tests := []struct {
+		name     string
+		header   map[string]string
+		expected bool
+	}{
+		{name: "no conditional headers", header: map[string]string{}, expected: false},
+		{name: "matching entity tag", header: map[string]string{"If-None-Match": `"abc"`}, expected: true},
+		{name: "matching entity tag in list", header: map[string]string{"If-None-Match": `"xyz", W/"abc"`}, expected: true},
+		{name: "any entity tag", header: map[string]string{"If-None-Match": "*"}, expected: true},
+		{name: "different entity tag", header: map[string]string{"If-None-Match": `"xyz"`}, expected: false},
+		{
+			name:     "entity tag takes precedence over date",
+			header:   map[string]string{"If-None-Match": `"xyz"`, "If-Modified-Since": "Mon, 09 Dec 2024 12:00:00 GMT"},
+			expected: false,
+		},
+		{name: "not modified since", header: map[string]string{"If-Modified-Since": "Mon, 09 Dec 2024 11:00:00 GMT"}, expected: true},
+		{name: "modified since", header: map[string]string{"If-Modified-Since": "Mon, 09 Dec 2024 10:59:59 GMT"}, expected: false},
+		{name: "invalid date", header: map[string]string{"If-Modified-Since": "yesterday"}, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			header := http.Header{}
+			for key, value := range test.header {
+				header.Set(key, value)
+			}
+			if got := IsNotModified(header, `"abc"`, lastModified); got != test.expected {
+				t.Errorf("IsNotModified() = %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestTodayTomorrowPricesExpiry(t *testing.T) {
+	// 2024-12-09 in Finnish time (UTC+2)
+	endOfDay := time.Date(2024, 12, 9, 21, 59, 0, 0, time.UTC)
+	publishTime := time.Date(2024, 12, 9, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		now               time.Time
+		tomorrowAvailable bool
+		expected          time.Time
+	}{
+		{name: "tomorrow's prices are available", now: time.Date(2024, 12, 9, 13, 0, 0, 0, time.UTC), tomorrowAvailable: true, expected: endOfDay},
+		{name: "before tomorrow's prices are published", now: time.Date(2024, 12, 9, 8, 0, 0, 0, time.UTC), expected: publishTime},
+		{name: "publishing is late", now: time.Date(2024, 12, 9, 12, 30, 0, 0, time.UTC), expected: time.Date(2024, 12, 9, 12, 35, 0, 0, time.UTC)},
+		{
+			name:              "after midnight in Finnish time but not in UTC",
+			now:               time.Date(2024, 12, 9, 22, 30, 0, 0, time.UTC),
+			tomorrowAvailable: false,
+			expected:          time.Date(2024, 12, 10, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expires, err := TodayTomorrowPricesExpiry(test.now, test.tomorrowAvailable)
+			if err != nil {
+				t.Fatalf("TodayTomorrowPricesExpiry() error = %v", err)
+			}
+			if !expires.Equal(test.expected) {
+				t.Errorf("TodayTomorrowPricesExpiry() = %v, want %v", expires, test.expected)
+			}
+		})
+	}
+}
+
+func TestMarketPricesFreshness(t *testing.T) {
+	tests := []struct {
+		name      string
+		endDate   string
+		now       time.Time
+		cacheable bool
+	}{
+		{name: "past days", endDate: "2024-12-08", now: time.Date(2024, 12, 9, 10, 0, 0, 0, time.UTC), cacheable: true},
+		{name: "range reaches today", endDate: "2024-12-09", now: time.Date(2024, 12, 9, 10, 0, 0, 0, time.UTC), cacheable: false},
+		{name: "future days", endDate: "2024-12-31", now: time.Date(2024, 12, 9, 10, 0, 0, 0, time.UTC), cacheable: false},
+		// 2024-12-08 22:30 UTC is already 2024-12-09 in Finnish time
+		{name: "end date passed in Finnish time", endDate: "2024-12-08", now: time.Date(2024, 12, 8, 22, 30, 0, 0, time.UTC), cacheable: true},
+		{name: "invalid date", endDate: "08.12.2024", now: time.Date(2024, 12, 9, 10, 0, 0, 0, time.UTC), cacheable: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			freshness, cacheable := MarketPricesFreshness(test.endDate, test.now)
+			if cacheable != test.cacheable {
+				t.Fatalf("MarketPricesFreshness() cacheable = %v, want %v", cacheable, test.cacheable)
+			}
+			if cacheable && !freshness.Expires.Equal(test.now.Add(PAST_PRICES_MAX_AGE)) {
+				t.Errorf("MarketPricesFreshness() expires = %v, want %v", freshness.Expires, test.now.Add(PAST_PRICES_MAX_AGE))
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+const CLOCK_FORMAT string = "15:04" // layout of HH:MM
+
+// DefaultNotificationPreferences returns the preferences which are applied when user has not stored any:
+// the daily tomorrow-prices message is delivered as soon as prices are available, in English.
+func DefaultNotificationPreferences(userID string) *models.NotificationPreferences {
+	return &models.NotificationPreferences{
+		UserID:         userID,
+		TomorrowPrices: true,
+		Language:       "en",
+	}
+}
+
+// MergeNotificationPreferences decodes the JSON body on top of the current preferences (stored or default ones),
+// so that fields which are omitted from the body keep their current values.
+func MergeNotificationPreferences(current *models.NotificationPreferences, body io.Reader) (*models.NotificationPreferences, error) {
+	merged := *current
+	if err := json.NewDecoder(body).Decode(&merged); err != nil {
+		return nil, fmt.Errorf("failed to decode request body: %w", err)
+	}
+	return &merged, nil
+}
+
+// ValidateNotificationPreferences checks whether the given notification preferences are valid
+func ValidateNotificationPreferences(preferences *models.NotificationPreferences) error {
+	if preferences.DeliveryTime != "" {
+		if _, err := time.Parse(CLOCK_FORMAT, preferences.DeliveryTime); err != nil {
+			return fmt.Errorf("delivery_time should have value in correct format 'HH:MM'")
+		}
+	}
+
+	quietHours := preferences.QuietHours
+	if (quietHours.Start == "") != (quietHours.End == "") {
+		return fmt.Errorf("quiet_hours should have both start and end or neither")
+	}
+	if quietHours.Start != "" {
+		if _, err := time.Parse(CLOCK_FORMAT, quietHours.Start); err != nil {
+			return fmt.Errorf("quiet_hours.start should have value in correct format 'HH:MM'")
+		}
+		if _, err := time.Parse(CLOCK_FORMAT, quietHours.End); err != nil {
+			return fmt.Errorf("quiet_hours.end should have value in correct format 'HH:MM'")
+		}
+	}
+
+	if !slices.Contains(models.SUPPORTED_LANGUAGES, preferences.Language) {
+		return fmt.Errorf("language should have valid value: %v", models.SUPPORTED_LANGUAGES)
+	}
+	return nil
+}
+
+// NextDeliveryTime returns the time when a message should be delivered to user according to the notification preferences.
+// `now` needs to be in Finnish time. The message is delivered at the next occurrence of the preferred delivery time
+// (or right away if there is none), and postponed to the end of quiet hours if it falls into them.
+func NextDeliveryTime(now time.Time, preferences *models.NotificationPreferences) time.Time {
+	deliverAt := now
+	if preferences.DeliveryTime != "" {
+		deliverAt = nextOccurrence(now, preferences.DeliveryTime)
+	}
+
+	quietHours := preferences.QuietHours
+	if quietHours.Start == "" || quietHours.End == "" {
+		return deliverAt
+	}
+	quietStart, quietEnd := clockMinutes(quietHours.Start), clockMinutes(quietHours.End)
+	minute := deliverAt.Hour()*60 + deliverAt.Minute()
+	isQuiet := false
+	if quietStart <= quietEnd {
+		isQuiet = minute >= quietStart && minute < quietEnd
+	} else {
+		// quiet hours wrap around midnight
+		isQuiet = minute >= quietStart || minute < quietEnd
+	}
+	if isQuiet {
+		deliverAt = nextOccurrence(deliverAt, quietHours.End)
+	}
+	return deliverAt
+}
+
+// nextOccurrence returns the first time at given clock ("HH:MM") which is not before `from`
+func nextOccurrence(from time.Time, clock string) time.Time {
+	minutes := clockMinutes(clock)
+	next := time.Date(from.Year(), from.Month(), from.Day(), minutes/60, minutes%60, 0, 0, from.Location())
+	if next.Before(from) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// clockMinutes returns the amount of minutes since midnight of a validated clock ("HH:MM")
+func clockMinutes(clock string) int {
+	parsed, _ := time.Parse(CLOCK_FORMAT, clock)
+	return parsed.Hour()*60 + parsed.Minute()
+}
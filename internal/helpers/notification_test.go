@@ -0,0 +1,147 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+func TestValidateNotificationPreferences(t *testing.T) {
+	tests := []struct {
+		name        string
+		preferences models.NotificationPreferences
+		wantErr     bool
+	}{
+		{
+			name:        "valid preferences",
+			preferences: models.NotificationPreferences{DeliveryTime: "18:30", QuietHours: models.QuietHours{Start: "22:00", End: "07:00"}, Language: "fi"},
+			wantErr:     false,
+		},
+		{
+			name:        "invalid delivery time",
+			preferences: models.NotificationPreferences{DeliveryTime: "6pm", Language: "en"},
+			wantErr:     true,
+		},
+		{
+			name:        "quiet hours without end",
+			preferences: models.NotificationPreferences{QuietHours: models.QuietHours{Start: "22:00"}, Language: "en"},
+			wantErr:     true,
+		},
+		{
+			name:        "unsupported language",
+			preferences: models.NotificationPreferences{Language: "de"},
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateNotificationPreferences(&test.preferences)
+			if (err != nil) != test.wantErr {
+				t.Errorf("ValidateNotificationPreferences() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNextDeliveryTime(t *testing.T) {
+	now := time.Date(2024, 12, 9, 14, 10, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		preferences models.NotificationPreferences
+		expected    time.Time
+	}{
+		{
+			name:        "no preferred time delivers right away",
+			preferences: models.NotificationPreferences{},
+			expected:    now,
+		},
+		{
+			name:        "preferred time later today",
+			preferences: models.NotificationPreferences{DeliveryTime: "18:30"},
+			expected:    time.Date(2024, 12, 9, 18, 30, 0, 0, time.UTC),
+		},
+		{
+			name:        "preferred time passed already today",
+			preferences: models.NotificationPreferences{DeliveryTime: "08:00"},
+			expected:    time.Date(2024, 12, 10, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "preferred time in quiet hours around midnight",
+			preferences: models.NotificationPreferences{DeliveryTime: "23:00", QuietHours: models.QuietHours{Start: "22:00", End: "07:00"}},
+			expected:    time.Date(2024, 12, 10, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "right away in quiet hours during the day",
+			preferences: models.NotificationPreferences{QuietHours: models.QuietHours{Start: "12:00", End: "16:00"}},
+			expected:    time.Date(2024, 12, 9, 16, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := NextDeliveryTime(now, &test.preferences)
+			if !got.Equal(test.expected) {
+				t.Errorf("expected delivery at %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestMergeNotificationPreferences(t *testing.T) {
+	stored := &models.NotificationPreferences{UserID: "user", TomorrowPrices: true, DeliveryTime: "18:30", Language: "fi"}
+	tests := []struct {
+		name    string
+		current *models.NotificationPreferences
+		body    string
+		want    models.NotificationPreferences
+		wantErr bool
+	}{
+		{
+			name:    "omitted language keeps the default",
+			current: DefaultNotificationPreferences("user"),
+			body:    `{"delivery_time":"07:00"}`,
+			want:    models.NotificationPreferences{UserID: "user", TomorrowPrices: true, DeliveryTime: "07:00", Language: "en"},
+		},
+		{
+			name:    "omitted tomorrow_prices keeps the stored value",
+			current: stored,
+			body:    `{"language":"sv"}`,
+			want:    models.NotificationPreferences{UserID: "user", TomorrowPrices: true, DeliveryTime: "18:30", Language: "sv"},
+		},
+		{
+			name:    "given fields replace the stored values",
+			current: stored,
+			body:    `{"tomorrow_prices":false,"delivery_time":""}`,
+			want:    models.NotificationPreferences{UserID: "user", TomorrowPrices: false, Language: "fi"},
+		},
+		{
+			name:    "malformed body",
+			current: stored,
+			body:    `{"language":`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := MergeNotificationPreferences(test.current, strings.NewReader(test.body))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("MergeNotificationPreferences() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if *got != test.want {
+				t.Errorf("MergeNotificationPreferences() = %+v, want %+v", *got, test.want)
+			}
+		})
+	}
+	if stored.Language != "fi" {
+		t.Errorf("MergeNotificationPreferences() modified the current preferences")
+	}
+}
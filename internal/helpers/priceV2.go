@@ -0,0 +1,153 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+// Adapters between the internal representation of prices (the format of external source, served as API v1)
+// and the models of API v2, so that both versions are served from the same data.
+
+// ToTodayTomorrowPriceV2 converts today's and tomorrow's prices to API v2. `now` decides the dates of today and tomorrow.
+func ToTodayTomorrowPriceV2(prices *models.TodayTomorrowPrice, now time.Time) (*models.TodayTomorrowPriceV2, error) {
+	location, err := loadHelsinkiLocation()
+	if err != nil {
+		return nil, err
+	}
+	today := now.In(location)
+
+	todayV2, err := toDailyPriceV2(prices.Today, today, location)
+	if err != nil {
+		return nil, err
+	}
+	tomorrowV2, err := toDailyPriceV2(prices.Tomorrow, today.AddDate(0, 0, 1), location)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TodayTomorrowPriceV2{Today: *todayV2, Tomorrow: *tomorrowV2}, nil
+}
+
+// ToMarketPriceV2 converts the market prices of a time range to API v2
+func ToMarketPriceV2(prices *models.PriceResponse) (*models.MarketPriceV2, error) {
+	location, err := loadHelsinkiLocation()
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]models.PriceSeriesV2, 0, len(prices.Data.Series))
+	for _, s := range prices.Data.Series {
+		seriesV2, err := toPriceSeriesV2(s, prices.Data.Group, location)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, *seriesV2)
+	}
+	return &models.MarketPriceV2{Group: prices.Data.Group, Series: series}, nil
+}
+
+// FromPriceRequestV2 converts the request of market prices from API v2 to the request of external source
+func FromPriceRequestV2(request models.PriceRequestV2) models.PriceRequest {
+	priceRequest := models.PriceRequest{
+		StartDate: request.StartDate,
+		EndDate:   request.EndDate,
+		Group:     request.Group,
+	}
+	if request.CompareToLastYear {
+		priceRequest.CompareToLastYear = 1
+	}
+	return priceRequest
+}
+
+func toDailyPriceV2(daily models.DailyPrice, day time.Time, location *time.Location) (*models.DailyPriceV2, error) {
+	series, err := toPriceSeriesV2(daily.Prices, "hour", location)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DailyPriceV2{Date: day.Format(DATE_FORMAT), Available: daily.Available, Prices: *series}, nil
+}
+
+// toPriceSeriesV2 converts the series to API v2. The timestamps of slots get the offset of Finnish time.
+func toPriceSeriesV2(series models.PriceSeries, group string, location *time.Location) (*models.PriceSeriesV2, error) {
+	resolution := priceResolution(group, series.Data)
+	slots := make([]models.PriceSlotV2, 0, len(series.Data))
+	for _, data := range series.Data {
+		start, err := time.ParseInLocation(DATE_TIME_FORMAT, data.TimeUTC, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse time of price slot: %s", err.Error())
+		}
+		start = start.In(location)
+		slots = append(slots, models.PriceSlotV2{
+			Start:       start,
+			End:         slotEnd(start, resolution),
+			Price:       data.Price,
+			VatIncluded: data.IncludeVat == "1",
+			VatFactor:   data.VatFactor,
+		})
+	}
+	return &models.PriceSeriesV2{
+		Unit:       series.Name,
+		Currency:   models.CURRENCY,
+		Resolution: resolution,
+		Prices:     slots,
+	}, nil
+}
+
+// priceResolution returns the length of slots of group. Hourly prices of external source are given in quarters
+// of hour since the day-ahead market moved to 15-minute slots, which is recognized from the first two slots.
+func priceResolution(group string, data []models.Data) string {
+	switch group {
+	case "day":
+		return models.RESOLUTION_DAY
+	case "week":
+		return models.RESOLUTION_WEEK
+	case "month":
+		return models.RESOLUTION_MONTH
+	case "year":
+		return models.RESOLUTION_YEAR
+	}
+	if len(data) >= 2 && slotDurationBetween(data[0].TimeUTC, data[1].TimeUTC) == 15*time.Minute {
+		return models.RESOLUTION_QUARTER_HOUR
+	}
+	return models.RESOLUTION_HOUR
+}
+
+// slotDurationBetween returns the length of hourly slots from the times (in DATE_TIME_FORMAT) of two consecutive slots:
+// a quarter of hour when they are 15 minutes apart, otherwise an hour
+func slotDurationBetween(first, second string) time.Duration {
+	firstTime, errFirst := time.Parse(DATE_TIME_FORMAT, first)
+	secondTime, errSecond := time.Parse(DATE_TIME_FORMAT, second)
+	if errFirst == nil && errSecond == nil && secondTime.Sub(firstTime) == 15*time.Minute {
+		return 15 * time.Minute
+	}
+	return time.Hour
+}
+
+// SlotDuration returns the length of slots of hourly prices: a quarter of hour since the day-ahead market
+// moved to 15-minute slots, otherwise an hour
+func SlotDuration(data []models.Data) time.Duration {
+	if priceResolution("hour", data) == models.RESOLUTION_QUARTER_HOUR {
+		return 15 * time.Minute
+	}
+	return time.Hour
+}
+
+// slotEnd returns the end of slot which begins at `start`. Calendar units follow Finnish time, so that days
+// which change daylight saving time last 23 or 25 hours.
+func slotEnd(start time.Time, resolution string) time.Time {
+	switch resolution {
+	case models.RESOLUTION_QUARTER_HOUR:
+		return start.Add(15 * time.Minute)
+	case models.RESOLUTION_DAY:
+		return start.AddDate(0, 0, 1)
+	case models.RESOLUTION_WEEK:
+		return start.AddDate(0, 0, 7)
+	case models.RESOLUTION_MONTH:
+		return start.AddDate(0, 1, 0)
+	case models.RESOLUTION_YEAR:
+		return start.AddDate(1, 0, 0)
+	}
+	return start.Add(time.Hour)
+}
@@ -0,0 +1,118 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+func TestToTodayTomorrowPriceV2(t *testing.T) {
+	prices := &models.TodayTomorrowPrice{
+		Today: models.DailyPrice{Available: true, Prices: models.PriceSeries{Name: "c/kWh", Data: []models.Data{
+			{TimeUTC: "2024-12-08 22:00:00", Time: "2024-12-09 00:00:00", Price: 2.47, VatFactor: 1.255, IncludeVat: "1"},
+			{TimeUTC: "2024-12-08 23:00:00", Time: "2024-12-09 01:00:00", Price: 1.5, VatFactor: 1.255, IncludeVat: "1"},
+		}}},
+		Tomorrow: models.DailyPrice{Available: false, Prices: models.PriceSeries{Name: "c/kWh"}},
+	}
+
+	got, err := ToTodayTomorrowPriceV2(prices, time.Date(2024, 12, 9, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ToTodayTomorrowPriceV2() error = %v", err)
+	}
+	if got.Today.Date != "2024-12-09" || got.Tomorrow.Date != "2024-12-10" {
+		t.Errorf("dates = %s, %s, want 2024-12-09, 2024-12-10", got.Today.Date, got.Tomorrow.Date)
+	}
+	if got.Tomorrow.Available || len(got.Tomorrow.Prices.Prices) != 0 {
+		t.Errorf("tomorrow = %+v, want unavailable without prices", got.Tomorrow)
+	}
+
+	series := got.Today.Prices
+	if series.Unit != "c/kWh" || series.Currency != models.CURRENCY || series.Resolution != models.RESOLUTION_HOUR {
+		t.Errorf("series = %s %s %s, want c/kWh %s %s", series.Unit, series.Currency, series.Resolution, models.CURRENCY, models.RESOLUTION_HOUR)
+	}
+	first := series.Prices[0]
+	if start := first.Start.Format(time.RFC3339); start != "2024-12-09T00:00:00+02:00" {
+		t.Errorf("start = %s, want 2024-12-09T00:00:00+02:00", start)
+	}
+	if end := first.End.Format(time.RFC3339); end != "2024-12-09T01:00:00+02:00" {
+		t.Errorf("end = %s, want 2024-12-09T01:00:00+02:00", end)
+	}
+	if first.Price != 2.47 || !first.VatIncluded || first.VatFactor != 1.255 {
+		t.Errorf("slot = %+v, want price 2.47 with VAT 1.255 included", first)
+	}
+}
+
+func TestToMarketPriceV2(t *testing.T) {
+	tests := []struct {
+		name          string
+		group         string
+		data          []models.Data
+		expectedRes   string
+		expectedStart string
+		expectedEnd   string
+	}{
+		{
+			name:          "quarters of hour",
+			group:         "hour",
+			data:          []models.Data{{TimeUTC: "2025-10-01 09:00:00"}, {TimeUTC: "2025-10-01 09:15:00"}},
+			expectedRes:   models.RESOLUTION_QUARTER_HOUR,
+			expectedStart: "2025-10-01T12:00:00+03:00",
+			expectedEnd:   "2025-10-01T12:15:00+03:00",
+		},
+		{
+			name:          "single hour",
+			group:         "hour",
+			data:          []models.Data{{TimeUTC: "2024-12-08 22:00:00"}},
+			expectedRes:   models.RESOLUTION_HOUR,
+			expectedStart: "2024-12-09T00:00:00+02:00",
+			expectedEnd:   "2024-12-09T01:00:00+02:00",
+		},
+		{
+			name:          "day which changes daylight saving time",
+			group:         "day",
+			data:          []models.Data{{TimeUTC: "2024-10-26 21:00:00"}},
+			expectedRes:   models.RESOLUTION_DAY,
+			expectedStart: "2024-10-27T00:00:00+03:00",
+			expectedEnd:   "2024-10-28T00:00:00+02:00",
+		},
+		{
+			name:          "month",
+			group:         "month",
+			data:          []models.Data{{TimeUTC: "2024-11-30 22:00:00"}},
+			expectedRes:   models.RESOLUTION_MONTH,
+			expectedStart: "2024-12-01T00:00:00+02:00",
+			expectedEnd:   "2025-01-01T00:00:00+02:00",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prices := &models.PriceResponse{Data: models.PriceData{Group: test.group, Series: []models.PriceSeries{{Name: "c/kWh", Data: test.data}}}}
+			got, err := ToMarketPriceV2(prices)
+			if err != nil {
+				t.Fatalf("ToMarketPriceV2() error = %v", err)
+			}
+			series := got.Series[0]
+			if got.Group != test.group || series.Resolution != test.expectedRes {
+				t.Errorf("group, resolution = %s, %s, want %s, %s", got.Group, series.Resolution, test.group, test.expectedRes)
+			}
+			slot := series.Prices[0]
+			if start := slot.Start.Format(time.RFC3339); start != test.expectedStart {
+				t.Errorf("start = %s, want %s", start, test.expectedStart)
+			}
+			if end := slot.End.Format(time.RFC3339); end != test.expectedEnd {
+				t.Errorf("end = %s, want %s", end, test.expectedEnd)
+			}
+		})
+	}
+}
+
+func TestFromPriceRequestV2(t *testing.T) {
+	got := FromPriceRequestV2(models.PriceRequestV2{StartDate: "2024-12-11", EndDate: "2024-12-31", Group: "day", CompareToLastYear: true})
+	expected := models.PriceRequest{StartDate: "2024-12-11", EndDate: "2024-12-31", Group: "day", CompareToLastYear: 1}
+	if got != expected {
+		t.Errorf("FromPriceRequestV2() = %+v, want %+v", got, expected)
+	}
+}
@@ -0,0 +1,84 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+// ProjectMonthlyBill projects the electricity bill of the month which `now` (Finnish time) belongs to.
+// The projection is built from the cost report of the month so far:
+//   - consumption and cost to date are taken from the slots which have consumption measured
+//   - the remaining consumption is the typical consumption of each slot of the day, averaged over the days measured so far
+//
+// Slots are as long as the slots of the report (an hour or 15 minutes).
+//   - the remaining slots are priced with the known prices (today and tomorrow) if available,
+//     otherwise with the average price of the month so far as a forecast
+func ProjectMonthlyBill(now time.Time, report *models.CostResponse, budget float64) (*models.BillProjection, error) {
+	month := now.Format(MONTH_FORMAT)
+	projection := &models.BillProjection{
+		Month:         month,
+		Currency:      models.CURRENCY,
+		MonthlyBudget: budget,
+	}
+
+	slotDuration := time.Hour
+	if len(report.Slots) >= 2 {
+		slotDuration = slotDurationBetween(report.Slots[0].Time, report.Slots[1].Time)
+	}
+	// typical consumption of each slot of the day, indexed by the slot of the day
+	profile := make([]float64, 24*time.Hour/slotDuration)
+	slotOfDay := func(slotTime time.Time) int {
+		sinceMidnight := time.Duration(slotTime.Hour())*time.Hour + time.Duration(slotTime.Minute())*time.Minute
+		return int(sinceMidnight / slotDuration)
+	}
+
+	measuredDays := make(map[string]bool)
+	knownPrices := make(map[string]float64)
+	var priceSum float64
+	var lastMeasured time.Time
+	for _, slot := range report.Slots {
+		slotTime, err := time.Parse(DATE_TIME_FORMAT, slot.Time)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse time of cost slot: %s", err.Error())
+		}
+		if slotTime.Format(MONTH_FORMAT) != month {
+			continue
+		}
+
+		knownPrices[slot.Time] = slot.Price
+		priceSum += slot.Price
+		if slot.Consumption <= 0 {
+			continue
+		}
+		projection.ConsumptionToDate += slot.Consumption
+		projection.CostToDate += slot.Cost
+		profile[slotOfDay(slotTime)] += slot.Consumption
+		measuredDays[slotTime.Format(DATE_FORMAT)] = true
+		if slotTime.After(lastMeasured) {
+			lastMeasured = slotTime
+		}
+	}
+
+	projection.ProjectedConsumption = projection.ConsumptionToDate
+	projection.ProjectedCost = projection.CostToDate
+	if len(measuredDays) > 0 && len(knownPrices) > 0 {
+		forecastPrice := priceSum / float64(len(knownPrices))
+		nextMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		for slotTime := lastMeasured.Add(slotDuration); slotTime.Before(nextMonth); slotTime = slotTime.Add(slotDuration) {
+			usage := profile[slotOfDay(slotTime)] / float64(len(measuredDays))
+			price, isKnown := knownPrices[slotTime.Format(DATE_TIME_FORMAT)]
+			if !isKnown {
+				price = forecastPrice
+			}
+			projection.ProjectedConsumption += usage
+			// cost is in EUR and price is in c/kWh
+			projection.ProjectedCost += usage * price / 100
+		}
+	}
+
+	projection.BudgetExceeded = budget > 0 && projection.ProjectedCost > budget
+	return projection, nil
+}
@@ -0,0 +1,120 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+func TestProjectMonthlyBill(t *testing.T) {
+	// February 2025 has 28 days. Consumption is measured for the first day only.
+	now := time.Date(2025, 2, 2, 12, 0, 0, 0, time.UTC)
+	slots := make([]models.CostSlot, 0)
+	for hour := 0; hour < 48; hour++ {
+		slotTime := time.Date(2025, 2, 1, hour, 0, 0, 0, time.UTC)
+		slot := models.CostSlot{
+			Time:  slotTime.Format(DATE_TIME_FORMAT),
+			Price: 10,
+		}
+		if hour < 24 {
+			slot.Consumption = 1
+			slot.Cost = 0.1
+		}
+		slots = append(slots, slot)
+	}
+	// slot of the next month is ignored
+	slots = append(slots, models.CostSlot{Time: "2025-03-01 00:00:00", Price: 1000, Consumption: 1000, Cost: 1000})
+	report := &models.CostResponse{Slots: slots}
+
+	tests := []struct {
+		name             string
+		budget           float64
+		expectedExceeded bool
+	}{
+		{name: "no budget", budget: 0, expectedExceeded: false},
+		{name: "budget is crossed", budget: 50, expectedExceeded: true},
+		{name: "budget is not crossed", budget: 100, expectedExceeded: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ProjectMonthlyBill(now, report, test.budget)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Month != "2025-02" {
+				t.Errorf("expected month 2025-02, got %s", got.Month)
+			}
+			if !almostEqual(got.ConsumptionToDate, 24) || !almostEqual(got.CostToDate, 2.4) {
+				t.Errorf("unexpected consumption (%f) or cost (%f) to date", got.ConsumptionToDate, got.CostToDate)
+			}
+			// 28 days with 24 kWh per day at 10 c/kWh
+			if !almostEqual(got.ProjectedConsumption, 672) {
+				t.Errorf("expected projected consumption 672, got %f", got.ProjectedConsumption)
+			}
+			if !almostEqual(got.ProjectedCost, 67.2) {
+				t.Errorf("expected projected cost 67.2, got %f", got.ProjectedCost)
+			}
+			if got.BudgetExceeded != test.expectedExceeded {
+				t.Errorf("expected budget exceeded %v, got %v", test.expectedExceeded, got.BudgetExceeded)
+			}
+		})
+	}
+}
+
+func TestProjectMonthlyBillQuarterHourSlots(t *testing.T) {
+	// February 2025 has 28 days. Consumption is measured for the first day only,
+	// in the first quarter of each hour.
+	now := time.Date(2025, 2, 2, 12, 0, 0, 0, time.UTC)
+	slots := make([]models.CostSlot, 0)
+	for quarter := 0; quarter < 2*96; quarter++ {
+		slotTime := time.Date(2025, 2, 1, 0, 15*quarter, 0, 0, time.UTC)
+		slot := models.CostSlot{
+			Time:  slotTime.Format(DATE_TIME_FORMAT),
+			Price: 10,
+		}
+		if quarter < 96 && quarter%4 == 0 {
+			slot.Consumption = 1
+			slot.Cost = 0.1
+		}
+		// known prices of the second day are cheaper in the last quarter of each hour
+		if quarter >= 96 && quarter%4 == 3 {
+			slot.Price = 2
+		}
+		slots = append(slots, slot)
+	}
+	report := &models.CostResponse{Slots: slots}
+
+	got, err := ProjectMonthlyBill(now, report, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !almostEqual(got.ConsumptionToDate, 24) || !almostEqual(got.CostToDate, 2.4) {
+		t.Errorf("unexpected consumption (%f) or cost (%f) to date", got.ConsumptionToDate, got.CostToDate)
+	}
+	// 28 days with 24 kWh per day, consumed in the first quarters only
+	if !almostEqual(got.ProjectedConsumption, 672) {
+		t.Errorf("expected projected consumption 672, got %f", got.ProjectedConsumption)
+	}
+	// the forecast price is the average of known prices: (96*10 + 72*10 + 24*2) / 192 = 9
+	// the second day is priced with known prices (10 in first quarters), remaining 26 days with the forecast price
+	expectedCost := 2.4 + 2.4 + 26*24*9.0/100
+	if !almostEqual(got.ProjectedCost, expectedCost) {
+		t.Errorf("expected projected cost %f, got %f", expectedCost, got.ProjectedCost)
+	}
+}
+
+func TestProjectMonthlyBillWithoutConsumption(t *testing.T) {
+	now := time.Date(2025, 2, 2, 12, 0, 0, 0, time.UTC)
+	report := &models.CostResponse{Slots: []models.CostSlot{{Time: "2025-02-01 00:00:00", Price: 10}}}
+
+	got, err := ProjectMonthlyBill(now, report, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ProjectedConsumption != 0 || got.ProjectedCost != 0 || got.BudgetExceeded {
+		t.Errorf("expected empty projection, got %#v", got)
+	}
+}
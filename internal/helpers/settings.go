@@ -0,0 +1,71 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+// MERGE_PATCH_CONTENT_TYPE is the media type of JSON Merge Patch (RFC 7396)
+const MERGE_PATCH_CONTENT_TYPE string = "application/merge-patch+json"
+
+// ValidatePriceSettingsPatch checks that the fields which are present in the patch have valid values.
+// Patch without any field is rejected by the database.
+func ValidatePriceSettingsPatch(patch models.PriceSettingsPatch) error {
+	if patch.Marginal != nil && !isValidFloat(*patch.Marginal) {
+		return fmt.Errorf("`margin` should be a valid number")
+	}
+	if patch.MonthlyBudget != nil && (!isValidFloat(*patch.MonthlyBudget) || *patch.MonthlyBudget < 0) {
+		return fmt.Errorf("`monthly_budget` should be a positive number or 0")
+	}
+	return nil
+}
+
+// ApplyPriceSettingsPatch returns the price settings after the fields which are present in the patch were changed
+func ApplyPriceSettingsPatch(settings models.PriceSettings, patch models.PriceSettingsPatch) models.PriceSettings {
+	if patch.VatIncluded != nil {
+		settings.VatIncluded = *patch.VatIncluded
+	}
+	if patch.Marginal != nil {
+		settings.Marginal = *patch.Marginal
+	}
+	if patch.MonthlyBudget != nil {
+		settings.MonthlyBudget = *patch.MonthlyBudget
+	}
+	return settings
+}
+
+// VersionETag returns the strong entity tag of a resource at given version. Ex: "3"
+func VersionETag(version int64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// ParseIfMatch reads the version which client expects from `If-Match` header.
+// Empty header and "*" mean that client does not expect any version, so nil is returned.
+func ParseIfMatch(header string) (version *int64, err error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	var value int64
+	if len(header) > 2 && strings.HasPrefix(header, `"`) && strings.HasSuffix(header, `"`) {
+		value, err = strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	} else {
+		err = fmt.Errorf("not a strong entity tag")
+	}
+	if err != nil || value < 0 {
+		return nil, fmt.Errorf("`If-Match` header should be the entity tag received through `ETag` header. Ex: \"3\"")
+	}
+	return &value, nil
+}
+
+// IsMergePatch reports whether `Content-Type` header is JSON Merge Patch. Parameters like charset are ignored.
+func IsMergePatch(header string) bool {
+	mediaType, _, err := mime.ParseMediaType(header)
+	return err == nil && mediaType == MERGE_PATCH_CONTENT_TYPE
+}
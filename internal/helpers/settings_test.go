@@ -0,0 +1,90 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"testing"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+func TestApplyPriceSettingsPatch(t *testing.T) {
+	margin := 0.75
+	vatIncluded := false
+	settings := models.PriceSettings{UserID: "12345", HouseholdID: "12345", VatIncluded: true, Marginal: 0.59, MonthlyBudget: 50, Version: 2}
+
+	tests := []struct {
+		name     string
+		patch    models.PriceSettingsPatch
+		expected models.PriceSettings
+	}{
+		{
+			name:     "only margin keeps VAT and budget",
+			patch:    models.PriceSettingsPatch{Marginal: &margin},
+			expected: models.PriceSettings{UserID: "12345", HouseholdID: "12345", VatIncluded: true, Marginal: 0.75, MonthlyBudget: 50, Version: 2},
+		},
+		{
+			name:     "VAT can be turned off explicitly",
+			patch:    models.PriceSettingsPatch{VatIncluded: &vatIncluded},
+			expected: models.PriceSettings{UserID: "12345", HouseholdID: "12345", VatIncluded: false, Marginal: 0.59, MonthlyBudget: 50, Version: 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ApplyPriceSettingsPatch(settings, test.patch)
+			if got != test.expected {
+				t.Errorf("ApplyPriceSettingsPatch() = %+v, want %+v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected *int64
+		wantErr  bool
+	}{
+		{name: "no header", header: "", expected: nil},
+		{name: "any version", header: "*", expected: nil},
+		{name: "strong entity tag", header: `"3"`, expected: func() *int64 { v := int64(3); return &v }()},
+		{name: "weak entity tag", header: `W/"3"`, wantErr: true},
+		{name: "unquoted version", header: "3", wantErr: true},
+		{name: "not a version", header: `"abc"`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseIfMatch(test.header)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseIfMatch() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if (got == nil) != (test.expected == nil) || (got != nil && *got != *test.expected) {
+				t.Errorf("ParseIfMatch() = %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestIsMergePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected bool
+	}{
+		{name: "merge patch", header: "application/merge-patch+json", expected: true},
+		{name: "merge patch with charset", header: "application/merge-patch+json; charset=utf-8", expected: true},
+		{name: "media type is case insensitive", header: "Application/Merge-Patch+JSON", expected: true},
+		{name: "plain json", header: "application/json", expected: false},
+		{name: "no header", header: "", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := IsMergePatch(test.header); got != test.expected {
+				t.Errorf("IsMergePatch(%q) = %v, want %v", test.header, got, test.expected)
+			}
+		})
+	}
+}
@@ -57,6 +57,29 @@ func GetCurrentTimeInHelsinki() (time.Time, *time.Location, error) {
 	return currentTime, location, nil
 }
 
+// ParseDateRangeInHelsinki validates the given date range in format "YYYY-MM-DD" and
+// returns the beginning of start date and the end of end date (exclusive) in Helsinki time.
+// Use case examples: query the stored data that belongs to the requested days
+func ParseDateRangeInHelsinki(startDate, endDate string) (from time.Time, to time.Time, err error) {
+	if _, err = isValidDateRange(startDate, endDate); err != nil {
+		return
+	}
+	location, err := loadHelsinkiLocation()
+	if err != nil {
+		return
+	}
+	// dates were validated above
+	from, _ = time.ParseInLocation(DATE_FORMAT, startDate, location)
+	to, _ = time.ParseInLocation(DATE_FORMAT, endDate, location)
+	to = to.AddDate(0, 0, 1)
+	return from, to, nil
+}
+
+// NextQuarter returns the time when the next quarter of hour begins, ex: 12:15 for 12:07
+func NextQuarter(now time.Time) time.Time {
+	return now.Truncate(15 * time.Minute).Add(15 * time.Minute)
+}
+
 func loadHelsinkiLocation() (*time.Location, error) {
 	location, err := time.LoadLocation("Europe/Helsinki")
 	if err != nil {
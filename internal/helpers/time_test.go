@@ -0,0 +1,27 @@
+// AnhCao 2024
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextQuarter(t *testing.T) {
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected time.Time
+	}{
+		{name: "within quarter", now: time.Date(2024, 12, 9, 12, 7, 30, 0, time.UTC), expected: time.Date(2024, 12, 9, 12, 15, 0, 0, time.UTC)},
+		{name: "at start of quarter", now: time.Date(2024, 12, 9, 12, 15, 0, 0, time.UTC), expected: time.Date(2024, 12, 9, 12, 30, 0, 0, time.UTC)},
+		{name: "next hour", now: time.Date(2024, 12, 9, 12, 59, 59, 0, time.UTC), expected: time.Date(2024, 12, 9, 13, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := NextQuarter(test.now); !got.Equal(test.expected) {
+				t.Errorf("NextQuarter() = %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
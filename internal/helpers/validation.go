@@ -7,7 +7,11 @@ import (
 	"time"
 )
 
-const DATE_FORMAT string = "2006-01-02" // this is just the layout of YYYY-MM-DD
+const (
+	DATE_FORMAT      string = "2006-01-02"          // this is just the layout of YYYY-MM-DD
+	DATE_TIME_FORMAT string = "2006-01-02 15:04:05" // layout of timestamps returned from external source (Oomi)
+	MONTH_FORMAT     string = "2006-01"             // layout of YYYY-MM
+)
 
 func isValidFloat(value float64) bool {
 	// Check if value is not NaN and not infinite
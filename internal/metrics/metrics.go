@@ -0,0 +1,222 @@
+// AnhCao 2024
+//
+// Package metrics keeps the Prometheus metrics of service (HTTP requests, gRPC calls, 3rd party fetches, cache, database,
+// message broker, scheduler and the current spot price), which are exposed through `/metrics`.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	NAMESPACE string = "stormbreaker"
+
+	OUTCOME_SUCCESS string = "success"
+	OUTCOME_FAILURE string = "failure"
+	OUTCOME_SKIPPED string = "skipped" // job ran, but there was nothing to do (ex: tomorrow's prices are not published yet)
+
+	CACHE_HIT  string = "hit"
+	CACHE_MISS string = "miss"
+)
+
+// Registry holds the metrics of service. Its own registry is used instead of the global one,
+// so that only the metrics of service, Go runtime and process are exposed.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+	grpcCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "grpc_calls_total",
+		Help:      "Number of gRPC calls by method and status code.",
+	}, []string{"method", "code"})
+	grpcCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "grpc_call_duration_seconds",
+		Help:      "Latency of gRPC calls by method and status code. Streaming calls last until the stream ends.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+	upstreamFetchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "upstream_fetch_duration_seconds",
+		Help:      "Latency of fetching prices from 3rd party by provider.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"provider"})
+	upstreamFetchErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "upstream_fetch_errors_total",
+		Help:      "Number of failed fetches of prices from 3rd party by provider.",
+	}, []string{"provider"})
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "cache_lookups_total",
+		Help:      "Number of cache lookups by class of key and result (hit or miss).",
+	}, []string{"key_class", "result"})
+	mongoOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "mongo_operation_duration_seconds",
+		Help:      "Latency of database commands by command name and outcome.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"command", "outcome"})
+	rabbitMQPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "rabbitmq_published_messages_total",
+		Help:      "Number of messages published to RabbitMQ by exchange, routing key and outcome.",
+	}, []string{"exchange", "routing_key", "outcome"})
+	rabbitMQConsumed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "rabbitmq_consumed_messages_total",
+		Help:      "Number of messages consumed from RabbitMQ by queue, routing key and outcome.",
+	}, []string{"queue", "routing_key", "outcome"})
+	schedulerJobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "scheduler_job_runs_total",
+		Help:      "Number of runs of scheduling jobs by job and outcome.",
+	}, []string{"job", "outcome"})
+	spotPrice = &spotPriceCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(NAMESPACE, "", "spot_price"),
+			"Current plain spot price of electric in Finland (no margin and no VAT).",
+			[]string{"unit"}, nil,
+		),
+	}
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpRequestDuration,
+		grpcCalls,
+		grpcCallDuration,
+		upstreamFetchDuration,
+		upstreamFetchErrors,
+		cacheLookups,
+		mongoOperationDuration,
+		rabbitMQPublished,
+		rabbitMQConsumed,
+		schedulerJobRuns,
+		spotPrice,
+	)
+}
+
+// Handler returns the HTTP handler which exposes the metrics in Prometheus format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveHTTPRequest records the HTTP request of `route` (the path template, ex: /v1/households/{id})
+func ObserveHTTPRequest(route, method string, statusCode int, duration time.Duration) {
+	status := strconv.Itoa(statusCode)
+	httpRequests.WithLabelValues(route, method, status).Inc()
+	httpRequestDuration.WithLabelValues(route, method, status).Observe(duration.Seconds())
+}
+
+// ObserveGRPCCall records the gRPC call of `method` (the full name, ex: /stormbreaker.v1.Stormbreaker/GetPriceSettings)
+func ObserveGRPCCall(method, code string, duration time.Duration) {
+	grpcCalls.WithLabelValues(method, code).Inc()
+	grpcCallDuration.WithLabelValues(method, code).Observe(duration.Seconds())
+}
+
+// ObserveUpstreamFetch records the fetch of prices from 3rd party `provider`
+func ObserveUpstreamFetch(provider string, duration time.Duration, err error) {
+	upstreamFetchDuration.WithLabelValues(provider).Observe(duration.Seconds())
+	if err != nil {
+		upstreamFetchErrors.WithLabelValues(provider).Inc()
+	}
+}
+
+// ObserveCacheLookup records the lookup of cache key of class `keyClass`
+func ObserveCacheLookup(keyClass string, hit bool) {
+	result := CACHE_MISS
+	if hit {
+		result = CACHE_HIT
+	}
+	cacheLookups.WithLabelValues(keyClass, result).Inc()
+}
+
+// ObserveMongoOperation records the database command
+func ObserveMongoOperation(command string, duration time.Duration, err error) {
+	mongoOperationDuration.WithLabelValues(command, outcome(err)).Observe(duration.Seconds())
+}
+
+// ObserveRabbitMQPublish records the message published to RabbitMQ
+func ObserveRabbitMQPublish(exchange, routingKey string, err error) {
+	rabbitMQPublished.WithLabelValues(exchange, routingKey, outcome(err)).Inc()
+}
+
+// ObserveRabbitMQConsume records the message consumed from RabbitMQ
+func ObserveRabbitMQConsume(queue, routingKey string, err error) {
+	rabbitMQConsumed.WithLabelValues(queue, routingKey, outcome(err)).Inc()
+}
+
+// ObserveSchedulerJob records the run of scheduling job with its outcome (OUTCOME_SUCCESS, OUTCOME_FAILURE or OUTCOME_SKIPPED)
+func ObserveSchedulerJob(job, outcome string) {
+	schedulerJobRuns.WithLabelValues(job, outcome).Inc()
+}
+
+// SetSpotPrices keeps the latest plain prices for today and tomorrow, from which the current spot price is exposed
+func SetSpotPrices(prices *models.TodayTomorrowPrice) {
+	spotPrice.lock.Lock()
+	defer spotPrice.lock.Unlock()
+	spotPrice.prices = prices
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return OUTCOME_FAILURE
+	}
+	return OUTCOME_SUCCESS
+}
+
+// spotPriceCollector exposes the price of slot which is in force at the time of scrape,
+// so that the gauge follows the slots without anyone setting it. Nothing is exposed when no slot covers the time.
+type spotPriceCollector struct {
+	desc   *prometheus.Desc
+	prices *models.TodayTomorrowPrice
+	now    func() time.Time
+	lock   sync.Mutex
+}
+
+func (c *spotPriceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *spotPriceCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.Lock()
+	prices := c.prices
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	c.lock.Unlock()
+	if prices == nil {
+		return
+	}
+
+	current, err := helpers.CurrentPrice(prices, now().UTC())
+	if err != nil || current == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, current.Price, current.Unit)
+}
@@ -0,0 +1,66 @@
+// AnhCao 2024
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSpotPriceCollector(t *testing.T) {
+	prices := &models.TodayTomorrowPrice{
+		Today: models.DailyPrice{Available: true, Prices: models.PriceSeries{Name: "c/kWh", Data: []models.Data{
+			{TimeUTC: "2024-12-09 10:00:00", Time: "2024-12-09 12:00:00", Price: 5},
+			{TimeUTC: "2024-12-09 11:00:00", Time: "2024-12-09 13:00:00", Price: 6},
+		}}},
+	}
+
+	tests := []struct {
+		name     string
+		prices   *models.TodayTomorrowPrice
+		now      time.Time
+		expected string
+	}{
+		{
+			name:   "price of current slot",
+			prices: prices,
+			now:    time.Date(2024, 12, 9, 11, 30, 0, 0, time.UTC),
+			expected: `
+# HELP stormbreaker_spot_price Current plain spot price of electric in Finland (no margin and no VAT).
+# TYPE stormbreaker_spot_price gauge
+stormbreaker_spot_price{unit="c/kWh"} 6
+`,
+		},
+		{name: "no slot covers the time", prices: prices, now: time.Date(2024, 12, 9, 9, 0, 0, 0, time.UTC), expected: ""},
+		{name: "no prices yet", prices: nil, now: time.Date(2024, 12, 9, 11, 30, 0, 0, time.UTC), expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collector := &spotPriceCollector{desc: spotPrice.desc, prices: test.prices, now: func() time.Time { return test.now }}
+			if err := testutil.CollectAndCompare(collector, strings.NewReader(test.expected)); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestObserveHTTPRequest(t *testing.T) {
+	before := testutil.ToFloat64(httpRequests.WithLabelValues("/v1/households/{id}", "GET", "404"))
+	ObserveHTTPRequest("/v1/households/{id}", "GET", 404, 10*time.Millisecond)
+	if got := testutil.ToFloat64(httpRequests.WithLabelValues("/v1/households/{id}", "GET", "404")); got != before+1 {
+		t.Errorf("http_requests_total = %v, want %v", got, before+1)
+	}
+}
+
+func TestObserveGRPCCall(t *testing.T) {
+	method := "/stormbreaker.v1.Stormbreaker/GetPriceSettings"
+	before := testutil.ToFloat64(grpcCalls.WithLabelValues(method, "NotFound"))
+	ObserveGRPCCall(method, "NotFound", 10*time.Millisecond)
+	if got := testutil.ToFloat64(grpcCalls.WithLabelValues(method, "NotFound")); got != before+1 {
+		t.Errorf("grpc_calls_total = %v, want %v", got, before+1)
+	}
+}
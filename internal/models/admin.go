@@ -0,0 +1,51 @@
+// AnhCao 2024
+package models
+
+const (
+	DEFAULT_ROLE_CLAIM string = "app_metadata.role"
+	DEFAULT_ADMIN_ROLE string = "admin"
+
+	ADMIN_DEFAULT_PAGE_SIZE int = 50
+	ADMIN_MAX_PAGE_SIZE     int = 200
+	// pages after this are not served, so that the amount of skipped settings stays small
+	ADMIN_MAX_PAGE int = 10000
+)
+
+// PriceSettingsQuery represents the filters and page of price settings which admin lists.
+// Nil filters are not applied.
+type PriceSettingsQuery struct {
+	UserID      string   // settings of the user
+	HouseholdID string   // settings of the household
+	VatIncluded *bool    // settings which include VAT or not
+	HasBudget   *bool    // settings which have a monthly budget or not
+	MinMargin   *float64 // settings whose margin is at least this
+	MaxMargin   *float64 // settings whose margin is at most this
+	Page        int      // page number, starts from 1
+	PageSize    int      // amount of settings per page
+}
+
+// PriceSettingsPage represents a page of price settings of all users
+type PriceSettingsPage struct {
+	Items    []PriceSettings `json:"items"`                  // price settings of the page
+	Page     int             `json:"page" example:"1"`       // page number, starts from 1
+	PageSize int             `json:"page_size" example:"50"` // amount of settings per page
+	Total    int64           `json:"total" example:"120"`    // amount of settings which match the filters
+}
+
+// DistributionBucket represents the amount of price settings whose value is in the range of bucket
+type DistributionBucket struct {
+	Label string `json:"label" example:"0.5-1"` // range of values. Ex: "0.5-1" is from 0.5 up to (but not including) 1
+	Count int    `json:"count" example:"42"`    // amount of price settings in the range
+}
+
+// PriceSettingsSummary represents the amount of users and the distribution of their price settings
+type PriceSettingsSummary struct {
+	Users              int                  `json:"users" example:"100"`           // amount of users who have price settings
+	Households         int                  `json:"households" example:"120"`      // amount of households which have price settings
+	VatIncluded        int                  `json:"vat_included" example:"110"`    // amount of price settings which include VAT
+	WithBudget         int                  `json:"with_budget" example:"30"`      // amount of price settings which have a monthly budget
+	AverageMargin      float64              `json:"average_margin" example:"0.62"` // average margin (c/kWh) of the settings which have a margin
+	AverageBudget      float64              `json:"average_budget" example:"75.5"` // average monthly budget (EUR) of the settings which have a budget
+	MarginDistribution []DistributionBucket `json:"margin_distribution"`           // amount of price settings by margin (c/kWh), "none" when no margin is set. The first range starts at the smallest margin when it is negative
+	BudgetDistribution []DistributionBucket `json:"budget_distribution"`           // amount of price settings by monthly budget (EUR), "none" when no budget is set
+}
@@ -0,0 +1,29 @@
+// AnhCao 2024
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const (
+	PRICE_ALERT_ABOVE          string = "above"          // notify when any slot tomorrow exceeds the threshold
+	PRICE_ALERT_BELOW          string = "below"          // notify when any slot tomorrow is below the threshold
+	PRICE_ALERT_CHEAPEST_HOURS string = "cheapest_hours" // notify about the cheapest hours tomorrow which are below the threshold
+)
+
+// PriceAlert represents the schema for the price_alerts collection. It is a rule which user defines to get notified about tomorrow's prices.
+type PriceAlert struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id" swaggertype:"string" example:"6759a8f1c2a4b5e3f1d2c3b4"` // id of the alert rule
+	UserID      string             `bson:"user_id" json:"user_id" example:"123456789"`                                      // id of the user. The clients (web, mobile) does not need to provide `user_id` because the service will read through `access_token`.
+	HouseholdID string             `bson:"household_id" json:"household_id" example:"123456789"`                            // id of the household whose prices are evaluated. The clients (web, mobile) select the household through query `household_id`.
+	Type        string             `bson:"type" json:"type" example:"above" enums:"above,below,cheapest_hours"`             // type of the rule
+	Threshold   float64            `bson:"threshold" json:"threshold" example:"15"`                                         // price threshold in c/kWh (user's price settings applied)
+	Hours       int                `bson:"hours,omitempty" json:"hours,omitempty" example:"3"`                              // amount of cheapest hours. Only required for type 'cheapest_hours'
+	Enabled     bool               `bson:"enabled" json:"enabled" example:"true"`                                           // indicates whether the rule is evaluated or not
+}
+
+// Represents a struct of data that will be used to send as producing message to RabbitMQ when user's price alert rule matches tomorrow's prices.
+type PriceAlertMessage struct {
+	UserID    string      `json:"user_id"`   // UserID represents the user who should be notified
+	Alert     PriceAlert  `json:"alert"`     // Alert represents the rule which matched
+	Prices    PriceSeries `json:"prices"`    // Prices represents the slots of tomorrow which matched the rule
+	TimeStamp string      `json:"timestamp"` // TimeStamp represents the time when the message is produced.
+}
@@ -0,0 +1,42 @@
+// AnhCao 2024
+package models
+
+import "time"
+
+const (
+	// DEFAULT_CALENDAR_HOURS is the length of the cheapest and most expensive periods in calendar feed when user does not choose it
+	DEFAULT_CALENDAR_HOURS int = 3
+	// MAX_CALENDAR_HOURS is the longest period in calendar feed. Longer periods would cover most of the day.
+	MAX_CALENDAR_HOURS int = 12
+)
+
+// CalendarFeed represents the schema for the calendar_feeds collection. It is the subscription of user to the
+// iCalendar feed of cheap and expensive periods of a household. Calendar applications cannot send access token,
+// so the feed is identified by a secret token in its URL. Only the hash of the token is stored.
+type CalendarFeed struct {
+	TokenHash   string    `bson:"token_hash" json:"-"`                                         // SHA-256 hash of the secret token in the URL of the feed
+	UserID      string    `bson:"user_id" json:"user_id" example:"123456789"`                  // id of the user who subscribed to the feed
+	HouseholdID string    `bson:"household_id" json:"household_id" example:"123456789"`        // id of the household whose prices are in the feed
+	Hours       int       `bson:"hours" json:"hours" example:"3"`                              // length (hours) of the cheapest and most expensive periods
+	CreatedAt   time.Time `bson:"created_at" json:"created_at" example:"2024-12-09T12:00:00Z"` // time when the token was created
+}
+
+// CalendarFeedRequest represents the request body when user subscribes to the calendar feed
+type CalendarFeedRequest struct {
+	Hours int `json:"hours" example:"3"` // length (hours) of the cheapest and most expensive periods. Value 0 means the default (3 hours).
+}
+
+// CalendarFeedResponse represents the subscription to calendar feed. The token is only returned once when it is created.
+type CalendarFeedResponse struct {
+	CalendarFeed
+	Token string `json:"token" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`                                            // secret token of the feed
+	URL   string `json:"url" example:"https://localhost:5001/v1/calendar.ics?token=9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"` // URL which user subscribes to in calendar application. Relative to the host of API when public URL of service is not configured
+}
+
+// PriceWindow represents consecutive price slots, ex: the cheapest 3 hours of a day
+type PriceWindow struct {
+	Start        time.Time // start of the first slot in UTC
+	End          time.Time // end of the last slot in UTC
+	AveragePrice float64   // average price of the slots (user's price settings applied)
+	Unit         string    // unit of price, ex: c/kWh
+}
@@ -1,21 +1,41 @@
 // AnhCao 2024
 package models
 
+import (
+	"fmt"
+	"net"
+	"net/url"
+)
+
 // Config represents the configuration structure for the application.
-// It includes settings for the server, database, Supabase, and message broker.
+// It includes settings for the server, database, Supabase, message broker, the default price settings of new users,
+// the rate limits of API, CORS and tracing.
 type Config struct {
-	Server        Server   `yaml:"server"`
-	Database      Database `yaml:"database"`
-	Supabase      Supabase `yaml:"supabase"`
-	MessageBroker Broker   `yaml:"message_broker"`
+	Server               Server                `yaml:"server"`
+	Database             Database              `yaml:"database"`
+	Supabase             Supabase              `yaml:"supabase"`
+	MessageBroker        Broker                `yaml:"message_broker"`
+	DefaultPriceSettings PriceSettingsDefaults `yaml:"default_price_settings"`
+	RateLimit            RateLimit             `yaml:"rate_limit"`
+	CORS                 CORS                  `yaml:"cors"`
+	Tracing              Tracing               `yaml:"tracing"`
+	Metrics              Metrics               `yaml:"metrics"`
 }
 
 // Server represents the configuration settings for the server.
 // It includes the port and host information required to run the server.
 // The fields are annotated for YAML parsing.
 type Server struct {
-	Port string `yaml:"port"`
-	Host string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Host     string `yaml:"host"`
+	GrpcPort string `yaml:"grpc_port"` // port of gRPC API for internal backend services. gRPC API is not served when empty.
+	// Certificate and private key (PEM files) of gRPC API. gRPC API is served in plaintext when empty,
+	// which is only meant for the private network of backend services.
+	GrpcCertFile string `yaml:"grpc_cert_file"`
+	GrpcKeyFile  string `yaml:"grpc_key_file"`
+	// Base URL which clients use to reach the service (ex: "https://api.example.com"), for links which the service returns
+	// (ex: URL of calendar feed). Links are relative to the host of API when empty.
+	PublicURL string `yaml:"public_url"`
 }
 
 // Broker represents the configuration settings for connecting to a broker.
@@ -56,9 +76,176 @@ type Supabase struct {
 
 type auth struct {
 	JwtSecret string `yaml:"jwt_secret"`
+	// Claim of access token which carries the role (or list of roles) of user. Nested claims are separated by dot.
+	// Ex: "app_metadata.role", which only the service role of Supabase can change. "app_metadata.role" when empty.
+	RoleClaim string `yaml:"role_claim"`
+	// Role which may use the admin API. "admin" when empty.
+	AdminRole string `yaml:"admin_role"`
+}
+
+// PriceSettingsDefaults represents the price settings which are created for a household
+// when it is accessed for the first time and has no price settings yet.
+type PriceSettingsDefaults struct {
+	// Indicates whether tax is included to price stats or not.
+	VatIncluded bool `yaml:"vat_included"`
+	// Amount of margin (c/kWh) applied to price stats.
+	Margin float64 `yaml:"margin"`
+	// Amount of money (EUR) planned to spend on electricity per month. Value 0 means no budget is set.
+	MonthlyBudget float64 `yaml:"monthly_budget"`
+}
+
+// For returns the default price settings of household which is owned by user
+func (d PriceSettingsDefaults) For(userID, householdID string) PriceSettings {
+	return PriceSettings{
+		UserID:        userID,
+		HouseholdID:   householdID,
+		VatIncluded:   d.VatIncluded,
+		Marginal:      d.Margin,
+		MonthlyBudget: d.MonthlyBudget,
+	}
 }
 
-// todo: validate configuration
+// RateLimit represents the limits of requests per IP address, which apply before the access token is verified,
+// and per user, which apply once the user is known. Requests whose path does not match any group are not limited.
+type RateLimit struct {
+	Groups []RateLimitGroup `yaml:"groups"`
+	// Addresses (ex: "10.0.0.1") or networks (ex: "10.0.0.0/8") of reverse proxies and load balancers in front of the service.
+	// The address of client is read from `X-Forwarded-For` header only when the request comes through them,
+	// otherwise clients could send a new address in every request to avoid the limits.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// RateLimitGroup represents the token bucket limit of a group of routes. The routes of group share the same bucket.
+type RateLimitGroup struct {
+	// Name of the group which is logged when a request is rejected.
+	Name string `yaml:"name"`
+	// Prefixes of paths which belong to the group. The first group which matches the path of request applies.
+	PathPrefixes []string `yaml:"path_prefixes"`
+	// Amount of requests per minute which client can make on average.
+	RequestsPerMinute float64 `yaml:"requests_per_minute"`
+	// Amount of requests which client can make at once.
+	Burst int `yaml:"burst"`
+	// Amount of requests per minute which an IP address can make on average. `requests_per_minute` applies when 0.
+	// Requests of many users can come from the same address (ex: office network), so this is usually higher.
+	IPRequestsPerMinute float64 `yaml:"ip_requests_per_minute"`
+	// Amount of requests which an IP address can make at once. `burst` applies when 0.
+	IPBurst int `yaml:"ip_burst"`
+}
+
+// IPLimit returns the requests per minute and burst which apply to IP address
+func (g RateLimitGroup) IPLimit() (requestsPerMinute float64, burst int) {
+	requestsPerMinute, burst = g.RequestsPerMinute, g.Burst
+	if g.IPRequestsPerMinute != 0 {
+		requestsPerMinute = g.IPRequestsPerMinute
+	}
+	if g.IPBurst != 0 {
+		burst = g.IPBurst
+	}
+	return requestsPerMinute, burst
+}
+
+// CORS represents which browser applications on other origins may call the API (Cross-Origin Resource Sharing).
+// Cross-origin requests are not allowed when no origin is configured.
+type CORS struct {
+	// Origins which may call the API. Ex: "https://dashboard.example.com", "https://*.example.com" (any subdomain) or "*" (any origin).
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	// Methods which may be used. The methods of API are allowed when empty.
+	AllowedMethods []string `yaml:"allowed_methods"`
+	// Request headers which may be sent. The headers which API reads are allowed when empty.
+	AllowedHeaders []string `yaml:"allowed_headers"`
+	// Response headers which browser applications may read, in addition to the safelisted ones.
+	ExposedHeaders []string `yaml:"exposed_headers"`
+	// Indicates whether browser may send credentials (cookies, `Authorization` header set by browser).
+	AllowCredentials bool `yaml:"allow_credentials"`
+	// How long (seconds) browser may cache the result of preflight request. Value 0 means browser's default.
+	MaxAge int `yaml:"max_age"`
+}
+
+// Tracing represents the export of OpenTelemetry spans to OTLP collector (ex: OpenTelemetry Collector, Jaeger, Tempo).
+type Tracing struct {
+	// Indicates whether spans are exported. Spans are no-op when disabled.
+	Enabled bool `yaml:"enabled"`
+	// Host and port of OTLP/HTTP endpoint of collector. Ex: "localhost:4318"
+	Endpoint string `yaml:"endpoint"`
+	// Indicates whether spans are exported without TLS.
+	Insecure bool `yaml:"insecure"`
+	// Name of service in traces. "stormbreaker" when empty.
+	ServiceName string `yaml:"service_name"`
+	// Share of new traces which are sampled, between 0 and 1. All traces are sampled when 0.
+	// Traces which are started by caller follow the decision of caller.
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// Metrics represents the endpoint which Prometheus scrapes. It is served on its own port,
+// so that it is not reachable through the port of API which clients use.
+type Metrics struct {
+	// Port of `/metrics`. Metrics are not served when empty.
+	Port string `yaml:"port"`
+	// Token which Prometheus sends as `Authorization: Bearer <token>` (ex: `authorization.credentials_file` of scrape config).
+	// Anyone who reaches the port may read the metrics when empty.
+	Token string `yaml:"token"`
+}
+
+// Validate checks the configuration settings which would make the service misbehave instead of failing to start
 func (c *Config) Validate() error {
+	if err := c.Server.Validate(); err != nil {
+		return err
+	}
+	if err := c.CORS.Validate(); err != nil {
+		return err
+	}
+	if c.Metrics.Port != "" && (c.Metrics.Port == c.Server.Port || c.Metrics.Port == c.Server.GrpcPort) {
+		return fmt.Errorf("metrics: `port` should differ from the ports of API")
+	}
+	return c.RateLimit.Validate()
+}
+
+// Validate checks that any origin ("*") is not allowed together with credentials, because then every website
+// could make requests with the credentials of user
+func (c CORS) Validate() error {
+	if !c.AllowCredentials {
+		return nil
+	}
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			return fmt.Errorf("cors: `allowed_origins` should list the origins instead of \"*\" when `allow_credentials` is true")
+		}
+	}
+	return nil
+}
+
+// Validate checks that public URL is an absolute HTTP(S) URL, because links which are built from it are opened outside the service
+func (s Server) Validate() error {
+	if (s.GrpcCertFile == "") != (s.GrpcKeyFile == "") {
+		return fmt.Errorf("server: `grpc_cert_file` and `grpc_key_file` should be set together")
+	}
+	if s.PublicURL == "" {
+		return nil
+	}
+	publicURL, err := url.Parse(s.PublicURL)
+	if err != nil || (publicURL.Scheme != "https" && publicURL.Scheme != "http") || publicURL.Host == "" ||
+		publicURL.RawQuery != "" || publicURL.Fragment != "" {
+		return fmt.Errorf("server: `public_url` %q should be an absolute http(s) URL without query", s.PublicURL)
+	}
+	return nil
+}
+
+// Validate checks that every group allows requests, because a bucket without tokens would reject every request,
+// and that trusted proxies are valid addresses or networks
+func (r RateLimit) Validate() error {
+	for _, group := range r.Groups {
+		ipRequestsPerMinute, ipBurst := group.IPLimit()
+		if group.Burst <= 0 || ipBurst <= 0 {
+			return fmt.Errorf("rate_limit: `burst` and `ip_burst` of group %q should be greater than 0", group.Name)
+		}
+		if group.RequestsPerMinute <= 0 || ipRequestsPerMinute <= 0 {
+			return fmt.Errorf("rate_limit: `requests_per_minute` and `ip_requests_per_minute` of group %q should be greater than 0", group.Name)
+		}
+	}
+	for _, proxy := range r.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("rate_limit: trusted proxy %q is not an IP address or network", proxy)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,121 @@
+// AnhCao 2024
+package models
+
+import "testing"
+
+func TestValidateRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		rateLimit RateLimit
+		wantErr   bool
+	}{
+		{
+			name: "valid limits",
+			rateLimit: RateLimit{
+				Groups:         []RateLimitGroup{{Name: "prices", RequestsPerMinute: 60, Burst: 10, IPRequestsPerMinute: 600, IPBurst: 100}},
+				TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1", "::1"},
+			},
+			wantErr: false,
+		},
+		{
+			name:      "limits of IP address default to the limits of user",
+			rateLimit: RateLimit{Groups: []RateLimitGroup{{Name: "prices", RequestsPerMinute: 60, Burst: 10}}},
+			wantErr:   false,
+		},
+		{
+			name:      "zero burst",
+			rateLimit: RateLimit{Groups: []RateLimitGroup{{Name: "prices", RequestsPerMinute: 60, Burst: 0}}},
+			wantErr:   true,
+		},
+		{
+			name:      "negative burst of IP address",
+			rateLimit: RateLimit{Groups: []RateLimitGroup{{Name: "prices", RequestsPerMinute: 60, Burst: 10, IPBurst: -1}}},
+			wantErr:   true,
+		},
+		{
+			name:      "zero requests per minute",
+			rateLimit: RateLimit{Groups: []RateLimitGroup{{Name: "prices", Burst: 10}}},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid trusted proxy",
+			rateLimit: RateLimit{TrustedProxies: []string{"proxy.example.com"}},
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.rateLimit.Validate(); (err != nil) != test.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateServer(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  Server
+		wantErr bool
+	}{
+		{name: "no public URL", server: Server{}, wantErr: false},
+		{name: "public URL", server: Server{PublicURL: "https://api.example.com"}, wantErr: false},
+		{name: "public URL with path", server: Server{PublicURL: "https://example.com/stormbreaker/"}, wantErr: false},
+		{name: "public URL without scheme", server: Server{PublicURL: "api.example.com"}, wantErr: true},
+		{name: "public URL with other scheme", server: Server{PublicURL: "ftp://api.example.com"}, wantErr: true},
+		{name: "public URL with query", server: Server{PublicURL: "https://api.example.com?token=1"}, wantErr: true},
+		{name: "gRPC certificate and key", server: Server{GrpcCertFile: "tls.crt", GrpcKeyFile: "tls.key"}, wantErr: false},
+		{name: "gRPC certificate without key", server: Server{GrpcCertFile: "tls.crt"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.server.Validate(); (err != nil) != test.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCORS(t *testing.T) {
+	tests := []struct {
+		name    string
+		cors    CORS
+		wantErr bool
+	}{
+		{name: "no origins", cors: CORS{AllowCredentials: true}, wantErr: false},
+		{name: "any origin without credentials", cors: CORS{AllowedOrigins: []string{"*"}}, wantErr: false},
+		{name: "listed origins with credentials", cors: CORS{AllowedOrigins: []string{"https://app.example.com", "https://*.example.com"}, AllowCredentials: true}, wantErr: false},
+		{name: "any origin with credentials", cors: CORS{AllowedOrigins: []string{"https://app.example.com", "*"}, AllowCredentials: true}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.cors.Validate(); (err != nil) != test.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMetricsPort(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{name: "metrics are not served", config: Config{Server: Server{Port: "5001"}}, wantErr: false},
+		{name: "own port", config: Config{Server: Server{Port: "5001"}, Metrics: Metrics{Port: "9090"}}, wantErr: false},
+		{name: "port of API", config: Config{Server: Server{Port: "5001"}, Metrics: Metrics{Port: "5001"}}, wantErr: true},
+		{name: "port of gRPC API", config: Config{Server: Server{Port: "5001", GrpcPort: "5002"}, Metrics: Metrics{Port: "5002"}}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.config.Validate(); (err != nil) != test.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// AnhCao 2024
+package models
+
+import "time"
+
+// Represents the amount of electricity that user consumed in a specific hour
+type Consumption struct {
+	UserID      string    `bson:"user_id" json:"user_id" example:"123456789"`           // id of the user who owns the metering data
+	HouseholdID string    `bson:"household_id" json:"household_id" example:"123456789"` // id of the household (metering point) where the electricity was consumed
+	Time        time.Time `bson:"time" json:"time" example:"2024-12-08T22:00:00Z"`      // start of the consumption slot in UTC
+	Consumption float64   `bson:"consumption" json:"consumption" example:"1.25"`        // consumed energy in kWh
+}
+
+// Represents the actual cost of electricity in a single time slot
+type CostSlot struct {
+	TimeUTC     string  `json:"time_utc" example:"2024-12-08 22:00:00"` // timestamp in UTC format
+	Time        string  `json:"time" example:"2024-12-09 00:00:00"`     // the local time (Finnish time)
+	Consumption float64 `json:"consumption" example:"1.25"`             // consumed energy in kWh
+	Price       float64 `json:"price" example:"5.34"`                   // price of the slot with margin, VAT (if included) and electricity tax in c/kWh
+	Cost        float64 `json:"cost" example:"0.0668"`                  // cost of the slot in EUR
+}
+
+// Represents the aggregated cost of electricity over a period (day, month or whole requested range)
+type CostSummary struct {
+	Period               string  `json:"period" example:"2024-12"`              // Period is "YYYY-MM-DD" for a day, "YYYY-MM" for a month and empty for the whole range
+	Consumption          float64 `json:"consumption" example:"412.5"`           // consumed energy in kWh
+	Cost                 float64 `json:"cost" example:"27.41"`                  // cost in EUR
+	WeightedAveragePrice float64 `json:"weighted_average_price" example:"6.64"` // consumption-weighted average price that user actually paid in c/kWh
+	AveragePrice         float64 `json:"average_price" example:"7.12"`          // plain average of all slot prices in the period in c/kWh
+}
+
+// Represents the response of actual cost calculation in a given date range
+type CostResponse struct {
+	StartDate string        `json:"starttime" example:"2024-12-01"` // StartDate is in format "YYYY-MM-DD"
+	EndDate   string        `json:"endtime" example:"2024-12-31"`   // EndDate is in format "YYYY-MM-DD"
+	Currency  string        `json:"currency" example:"EUR"`         // currency of all costs
+	Unit      string        `json:"unit" example:"c/kWh"`           // unit of all prices
+	Total     CostSummary   `json:"total"`
+	Months    []CostSummary `json:"months"`
+	Days      []CostSummary `json:"days"`
+	Slots     []CostSlot    `json:"slots"`
+}
+
+// Represents the projection of electricity bill for the current month
+type BillProjection struct {
+	Month                string  `json:"month" example:"2024-12"`               // Month is in format "YYYY-MM"
+	Currency             string  `json:"currency" example:"EUR"`                // currency of all costs
+	ConsumptionToDate    float64 `json:"consumption_to_date" example:"212.5"`   // consumed energy in kWh so far
+	CostToDate           float64 `json:"cost_to_date" example:"14.12"`          // cost in EUR so far
+	ProjectedConsumption float64 `json:"projected_consumption" example:"430.1"` // expected consumed energy in kWh at the end of the month
+	ProjectedCost        float64 `json:"projected_cost" example:"28.93"`        // expected cost in EUR at the end of the month
+	MonthlyBudget        float64 `json:"monthly_budget" example:"25"`           // budget in EUR from user's price settings. Value 0 means no budget is set.
+	BudgetExceeded       bool    `json:"budget_exceeded" example:"true"`        // indicates whether the projected cost crosses the monthly budget
+}
+
+// Represents a struct of data that will be sent as producing message to RabbitMQ when the projected bill crosses user's monthly budget.
+type BudgetAlertMessage struct {
+	UserID      string         `json:"user_id"`      // UserID represents the user who should be warned
+	HouseholdID string         `json:"household_id"` // HouseholdID represents the household whose projected bill crosses the budget
+	Projection  BillProjection `json:"projection"`   // Projection represents the projected bill which crosses the budget
+	TimeStamp   string         `json:"timestamp"`    // TimeStamp represents the time when the message is produced.
+}
+
+// BudgetAlert represents the schema for the BudgetAlerts collection. It records that the household was warned about its budget in the month,
+// so that the household is warned at most once per month even when the service restarts.
+type BudgetAlert struct {
+	HouseholdID string    `bson:"household_id"` // id of the household which was warned
+	Month       string    `bson:"month"`        // month of the warning (ex: 2024-12)
+	AlertedAt   time.Time `bson:"alerted_at"`   // time when the warning was sent
+}
@@ -1,12 +1,24 @@
 // AnhCao 2024
 package models
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
 const (
 	BASE_URL     string = "https://oomi.fi/wp-json"
 	SPOT_PRICE   string = "spot-price"
 	GET_V1       string = "v1/get"
 	CLIENT_ERROR string = "client"
 	SERVER_ERROR string = "server"
+	// ELECTRICITY_TAX is the Finnish electricity tax (class I) in c/kWh without VAT
+	ELECTRICITY_TAX float64 = 2.253
+	// VAT_FACTOR is the default VAT (25.5%) applied to electricity when external source does not provide it
+	VAT_FACTOR float64 = 1.255
+	CURRENCY   string  = "EUR"
 )
 
 // Represents single electric data at specific time
@@ -20,6 +32,22 @@ type Data struct {
 	IncludeVat   string  `json:"includeVat" example:"1" enums:"0,1"`      // IncludeVat is legacy property that return string value and value "0" means no VAT included and string "1" is included
 }
 
+// CurrentPrice represents the price of the slot which is in force at the moment
+type CurrentPrice struct {
+	Time    string  `json:"time" example:"2024-12-09 00:00:00"`     // start of the slot in Finnish time
+	TimeUTC string  `json:"time_utc" example:"2024-12-08 22:00:00"` // start of the slot in UTC
+	Price   float64 `json:"price" example:"2.47"`                   // price of the slot with price settings of user applied
+	Unit    string  `json:"unit" example:"c/kWh"`                   // unit of electric price
+}
+
+// PriceStats represents the lowest, highest and average price of a series
+type PriceStats struct {
+	Min     float64 `json:"min" example:"0.59"`     // lowest price of the series
+	Max     float64 `json:"max" example:"12.47"`    // highest price of the series
+	Average float64 `json:"average" example:"4.02"` // average price of the series
+	Unit    string  `json:"unit" example:"c/kWh"`   // unit of electric price
+}
+
 // Represents a series of electric data with the name of unit (ex: c/kwh)
 type PriceSeries struct {
 	Name string `json:"name" example:"c/kWh"` // unit of electric price
@@ -60,13 +88,57 @@ type DailyPrice struct {
 
 // PriceSettings represents the schema for the PriceSettings collection
 type PriceSettings struct {
-	UserID      string  `bson:"user_id" json:"user_id" example:"123456789"`      // id of the user. When sends as request, the clients (web, mobile) does not need to provide `user_id` because the service will read through `access_token`.
-	VatIncluded bool    `bson:"vat_included" json:"vat_included" example:"true"` // indicates whether tax is included to price stats or not
-	Marginal    float64 `bson:"margin" json:"margin" example:"0.59"`             // amount of margin applied to price stats
+	UserID        string  `bson:"user_id" json:"user_id" example:"123456789"`           // id of the user. When sends as request, the clients (web, mobile) does not need to provide `user_id` because the service will read through `access_token`.
+	HouseholdID   string  `bson:"household_id" json:"household_id" example:"123456789"` // id of the household which the settings belong to. The clients (web, mobile) select the household through query `household_id`; empty value means the default household.
+	VatIncluded   bool    `bson:"vat_included" json:"vat_included" example:"true"`      // indicates whether tax is included to price stats or not
+	Marginal      float64 `bson:"margin" json:"margin" example:"0.59"`                  // amount of margin applied to price stats
+	MonthlyBudget float64 `bson:"monthly_budget" json:"monthly_budget" example:"50"`    // amount of money (EUR) user plans to spend on electricity per month. Value 0 means no budget is set.
+	Version       int64   `bson:"version" json:"version" example:"3"`                   // increases on every change of the settings. It is returned as `ETag` so that clients can send it back through `If-Match`. The clients (web, mobile) does not need to provide it.
+}
+
+// PriceSettingsPatch represents the request body (JSON Merge Patch, RFC 7396) when clients change the price settings.
+// Only the fields which are present in the request body are changed, the others keep their current values.
+type PriceSettingsPatch struct {
+	UserID        string   `json:"user_id,omitempty" example:"123456789"` // id of the user. The clients (web, mobile) does not need to provide `user_id` because the service will read through `access_token`.
+	VatIncluded   *bool    `json:"vat_included,omitempty" example:"true"` // indicates whether tax is included to price stats or not
+	Marginal      *float64 `json:"margin,omitempty" example:"0.59"`       // amount of margin applied to price stats
+	MonthlyBudget *float64 `json:"monthly_budget,omitempty" example:"50"` // amount of money (EUR) user plans to spend on electricity per month. Value 0 removes the budget.
+}
+
+// UnmarshalJSON rejects `null` values. In JSON Merge Patch `null` removes the field, but price settings always have
+// all fields, so clients send a value instead (ex: `"monthly_budget": 0` removes the budget).
+func (p *PriceSettingsPatch) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, name := range []string{"vat_included", "margin", "monthly_budget"} {
+		if value, ok := fields[name]; ok && bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			return fmt.Errorf("`%s` cannot be null, send a value instead", name)
+		}
+	}
+
+	type patch PriceSettingsPatch
+	return json.Unmarshal(data, (*patch)(p))
+}
+
+// PriceSettingsHistory represents the schema for the price_settings_history collection.
+// Every change of user's price settings is stored with the time from which it is in force.
+// Deletion of settings is stored too, so that the default price settings are in force until settings are created again.
+// Versions keep counting up over deletions, so every entry of household has its own version.
+type PriceSettingsHistory struct {
+	PriceSettings `bson:",inline"`
+	EffectiveFrom time.Time `bson:"effective_from" json:"effective_from" example:"2024-12-09T12:00:00Z"` // time from which the settings are in force
+	Deleted       bool      `bson:"deleted,omitempty" json:"deleted,omitempty" example:"false"`          // settings were deleted, so the default price settings are in force from this time
 }
 
 // Represents a struct of data that will be used to send as producing message to RabbitMQ.
 type NewPricesMessage struct {
-	Data      TodayTomorrowPrice `json:"data"`      // Data represents the price of today and tomorrow
-	TimeStamp string             `json:"timestamp"` // TimeStamp represents the time when the message is produced. This will help `notification-service` to decide whether to push notifications or not.
+	Data      TodayTomorrowPrice `json:"data"`                // Data represents the price of today and tomorrow
+	TimeStamp string             `json:"timestamp"`           // TimeStamp represents the time when the message is produced. This will help `notification-service` to decide whether to push notifications or not.
+	UserID    string             `json:"user_id,omitempty"`   // UserID represents the user who receives the message. Empty value means the message is for all users.
+	Household *Household         `json:"household,omitempty"` // Household represents the household whose price settings are applied to the prices.
+	Language  string             `json:"language,omitempty"`  // Language represents the preferred language of the user who receives the message.
+	// ExcludedUserIDs represents the users who do not receive the message meant for all users, because they get their own message or do not want it.
+	ExcludedUserIDs []string `json:"excluded_user_ids,omitempty"`
 }
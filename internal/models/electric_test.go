@@ -0,0 +1,39 @@
+// AnhCao 2024
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalPriceSettingsPatch(t *testing.T) {
+	margin := 0.75
+	vatIncluded := false
+
+	tests := []struct {
+		name     string
+		body     string
+		expected PriceSettingsPatch
+		wantErr  bool
+	}{
+		{name: "only margin", body: `{"margin": 0.75}`, expected: PriceSettingsPatch{Marginal: &margin}},
+		{name: "VAT can be turned off", body: `{"vat_included": false}`, expected: PriceSettingsPatch{VatIncluded: &vatIncluded}},
+		{name: "null margin", body: `{"margin": null}`, wantErr: true},
+		{name: "null budget", body: `{"vat_included": true, "monthly_budget": null}`, wantErr: true},
+		{name: "not an object", body: `[0.75]`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var patch PriceSettingsPatch
+			err := json.Unmarshal([]byte(test.body), &patch)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(patch, test.expected) {
+				t.Errorf("Unmarshal() = %+v, want %+v", patch, test.expected)
+			}
+		})
+	}
+}
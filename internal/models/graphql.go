@@ -0,0 +1,9 @@
+// AnhCao 2024
+package models
+
+// GraphQLRequest represents the request body of GraphQL query
+type GraphQLRequest struct {
+	Query         string                 `json:"query" example:"{ household { priceSettings { margin } todayTomorrow { today { stats { average } } } } }"` // GraphQL document
+	OperationName string                 `json:"operationName,omitempty"`                                                                                  // operation to execute when the document has several
+	Variables     map[string]interface{} `json:"variables,omitempty"`                                                                                      // values of the variables in the document
+}
@@ -0,0 +1,40 @@
+// AnhCao 2024
+package models
+
+import "time"
+
+// DEFAULT_HOUSEHOLD_NAME is the name of the household which every user has from the beginning.
+// The default household has the same id as the user, so that data stored before households existed belongs to it.
+const DEFAULT_HOUSEHOLD_NAME string = "Home"
+
+// Household represents the schema for the households collection. It is a home or a metering point of user
+// (ex: a home and a summer cottage) which has its own contract, DSO, price settings, consumption and price alerts.
+type Household struct {
+	ID              string `bson:"_id" json:"id" example:"6759a8f1c2a4b5e3f1d2c3b4"`                                            // id of the household. The id of the default household is the id of the user.
+	UserID          string `bson:"user_id" json:"user_id" example:"123456789"`                                                  // id of the user who owns the household. The clients (web, mobile) does not need to provide `user_id` because the service will read through `access_token`.
+	Name            string `bson:"name" json:"name" example:"Summer cottage"`                                                   // name of the household which user chose
+	MeteringPointID string `bson:"metering_point_id,omitempty" json:"metering_point_id,omitempty" example:"643007574000123456"` // id of the metering point (GSRN) of the household
+	DSO             string `bson:"dso,omitempty" json:"dso,omitempty" example:"Caruna"`                                         // distribution system operator of the household
+	IsDefault       bool   `bson:"-" json:"is_default" example:"false"`                                                         // indicates whether it is the default household of user
+	Role            string `bson:"-" json:"role" example:"owner" enums:"owner,editor,viewer"`                                   // role of the requesting user in the household
+}
+
+const (
+	HOUSEHOLD_ROLE_OWNER  string = "owner"  // owns the household, manages its members
+	HOUSEHOLD_ROLE_EDITOR string = "editor" // sees and changes price settings and price alerts of the household
+	HOUSEHOLD_ROLE_VIEWER string = "viewer" // sees prices and costs of the household
+
+	HOUSEHOLD_MEMBER_PENDING  string = "pending"  // invited user has not accepted the invitation yet
+	HOUSEHOLD_MEMBER_ACCEPTED string = "accepted" // invited user is a member of the household
+)
+
+// HouseholdMember represents the schema for the household_members collection. It is the invitation
+// which the owner of household sends to another user, and the membership once the user accepts it.
+type HouseholdMember struct {
+	HouseholdID string    `bson:"household_id" json:"household_id" example:"6759a8f1c2a4b5e3f1d2c3b4"` // id of the shared household
+	OwnerID     string    `bson:"owner_id" json:"owner_id" example:"123456789"`                        // id of the user who owns the household and invited the member
+	UserID      string    `bson:"user_id" json:"user_id" example:"987654321"`                          // id of the invited user (Supabase user id)
+	Role        string    `bson:"role" json:"role" example:"viewer" enums:"viewer,editor"`             // role of the member in the household
+	Status      string    `bson:"status" json:"status" example:"pending" enums:"pending,accepted"`     // status of the invitation. The clients (web, mobile) does not need to provide it.
+	InvitedAt   time.Time `bson:"invited_at" json:"invited_at" example:"2024-12-09T12:00:00Z"`         // time when the owner invited the user
+}
@@ -0,0 +1,45 @@
+// AnhCao 2024
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Supported languages of notifications
+var SUPPORTED_LANGUAGES = []string{"en", "fi", "sv"}
+
+// NotificationPreferences represents the schema for the notification_preferences collection.
+// It is stored next to user's PriceSettings and decides whether and when the daily tomorrow-prices message is delivered to user.
+type NotificationPreferences struct {
+	UserID         string     `bson:"user_id" json:"user_id" example:"123456789"`             // id of the user. The clients (web, mobile) does not need to provide `user_id` because the service will read through `access_token`.
+	TomorrowPrices bool       `bson:"tomorrow_prices" json:"tomorrow_prices" example:"true"`  // indicates whether user wants the daily tomorrow-prices message at all
+	DeliveryTime   string     `bson:"delivery_time" json:"delivery_time" example:"18:30"`     // preferred local (Finnish) delivery time in format "HH:MM". Empty value means as soon as prices are available
+	QuietHours     QuietHours `bson:"quiet_hours" json:"quiet_hours"`                         // period when no message is delivered
+	Language       string     `bson:"language" json:"language" example:"fi" enums:"en,fi,sv"` // language of the message
+}
+
+// QuietHours represents a period of local (Finnish) time in format "HH:MM" when user does not want to be disturbed.
+// The period can wrap around midnight (ex: from "22:00" to "07:00"). Empty values mean no quiet hours.
+type QuietHours struct {
+	Start string `bson:"start" json:"start" example:"22:00"`
+	End   string `bson:"end" json:"end" example:"07:00"`
+}
+
+// ScheduledNotification represents the schema for the scheduled_notifications collection.
+// A message waits there for the delivery time which user prefers, so that it is not lost when the service restarts.
+type ScheduledNotification struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	UserID      string             `bson:"user_id"`      // id of the user who receives the message
+	HouseholdID string             `bson:"household_id"` // id of the household which the message is about
+	Exchange    string             `bson:"exchange"`     // exchange to publish the message to
+	RoutingKey  string             `bson:"routing_key"`  // routing key of the message
+	Body        []byte             `bson:"body"`         // content of the message
+	DeliverAt   time.Time          `bson:"deliver_at"`   // time when the message is published (again, after a failed attempt)
+	Attempts    int                `bson:"attempts"`     // amount of attempts to publish the message
+	// RequestID is the id of request which caused the message, empty for messages of scheduler
+	RequestID string `bson:"request_id,omitempty"`
+	// TraceContext carries the trace (W3C `traceparent`) of request which caused the message, so that publishing it continues the trace
+	TraceContext map[string]string `bson:"trace_context,omitempty"`
+}
@@ -0,0 +1,58 @@
+// AnhCao 2024
+package models
+
+import "time"
+
+// Resolutions (ISO 8601 durations) of price slots in API v2
+const (
+	RESOLUTION_QUARTER_HOUR string = "PT15M"
+	RESOLUTION_HOUR         string = "PT1H"
+	RESOLUTION_DAY          string = "P1D"
+	RESOLUTION_WEEK         string = "P1W"
+	RESOLUTION_MONTH        string = "P1M"
+	RESOLUTION_YEAR         string = "P1Y"
+)
+
+// PriceSlotV2 represents the price of electric in a time slot (API v2)
+type PriceSlotV2 struct {
+	Start       time.Time `json:"start" example:"2024-12-09T00:00:00+02:00"` // start of the slot in Finnish time (RFC 3339)
+	End         time.Time `json:"end" example:"2024-12-09T01:00:00+02:00"`   // end of the slot (exclusive) in Finnish time (RFC 3339)
+	Price       float64   `json:"price" example:"2.47"`                      // price of the slot with price settings of user applied
+	VatIncluded bool      `json:"vat_included" example:"true"`               // indicates whether VAT is included to the price
+	VatFactor   float64   `json:"vat_factor" example:"1.255"`                // VAT which applies to the price
+}
+
+// PriceSeriesV2 represents a series of price slots with the same unit and resolution (API v2)
+type PriceSeriesV2 struct {
+	Unit       string        `json:"unit" example:"c/kWh"`                                         // unit of price
+	Currency   string        `json:"currency" example:"EUR"`                                       // currency of price
+	Resolution string        `json:"resolution" example:"PT1H" enums:"PT15M,PT1H,P1D,P1W,P1M,P1Y"` // length of each slot (ISO 8601 duration)
+	Prices     []PriceSlotV2 `json:"prices"`
+}
+
+// DailyPriceV2 represents the prices of a day and whether they are available yet (API v2)
+type DailyPriceV2 struct {
+	Date      string        `json:"date" example:"2024-12-09"` // the day in Finnish time
+	Available bool          `json:"available" example:"true"`  // tomorrow's prices are available after they are published around 14:00
+	Prices    PriceSeriesV2 `json:"prices"`
+}
+
+// TodayTomorrowPriceV2 represents today's and tomorrow's prices (API v2)
+type TodayTomorrowPriceV2 struct {
+	Today    DailyPriceV2 `json:"today"`
+	Tomorrow DailyPriceV2 `json:"tomorrow"`
+}
+
+// MarketPriceV2 represents the market prices in a time range (API v2)
+type MarketPriceV2 struct {
+	Group  string          `json:"group" example:"hour" enums:"hour,day,week,month,year"` // grouping of prices which client requested
+	Series []PriceSeriesV2 `json:"series"`                                                // prices of the range, followed by the prices of last year when client requested comparison
+}
+
+// PriceRequestV2 represents the request body when client fetches market prices in a time range (API v2)
+type PriceRequestV2 struct {
+	StartDate         string `json:"start_date" example:"2024-12-11"`                       // first day of range in format "YYYY-MM-DD"
+	EndDate           string `json:"end_date" example:"2024-12-31"`                         // last day of range in format "YYYY-MM-DD"
+	Group             string `json:"group" example:"hour" enums:"hour,day,week,month,year"` // grouping of prices
+	CompareToLastYear bool   `json:"compare_to_last_year" example:"false"`                  // also returns the prices of the same range last year
+}
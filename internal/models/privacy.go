@@ -0,0 +1,27 @@
+// AnhCao 2024
+package models
+
+import "time"
+
+// UserDataExport represents everything the service holds about a user (GDPR data portability).
+// The price settings, settings history and consumption are the ones of households which user owns,
+// the price alerts are the ones which user defined in any household.
+type UserDataExport struct {
+	UserID                  string                   `json:"user_id" example:"123456789"`                // id of the user who requested the export
+	ExportedAt              time.Time                `json:"exported_at" example:"2024-12-09T12:00:00Z"` // time when the export was created
+	Households              []Household              `json:"households"`                                 // households which user owns or is a member of
+	HouseholdMembers        []HouseholdMember        `json:"household_members"`                          // invitations and memberships which user sent or received
+	PriceSettings           []PriceSettings          `json:"price_settings"`                             // price settings of households which user owns
+	PriceSettingsHistory    []PriceSettingsHistory   `json:"price_settings_history"`                     // changes of price settings of households which user owns
+	Consumption             []Consumption            `json:"consumption"`                                // consumption of households which user owns
+	PriceAlerts             []PriceAlert             `json:"price_alerts"`                               // price alerts which user defined
+	CalendarFeeds           []CalendarFeed           `json:"calendar_feeds"`                             // calendar feeds which user subscribed to, without their tokens
+	NotificationPreferences *NotificationPreferences `json:"notification_preferences,omitempty"`         // notification preferences of user, if user set them
+}
+
+// UserDataErasedMessage is published to message broker once all data of user was erased,
+// so that other services can confirm the erasure request to user.
+type UserDataErasedMessage struct {
+	UserID   string    `json:"user_id" example:"123456789"`              // id of the user whose data was erased
+	ErasedAt time.Time `json:"erased_at" example:"2024-12-09T12:00:00Z"` // time when the data was erased
+}
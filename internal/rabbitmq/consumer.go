@@ -4,10 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/AnhCaooo/stormbreaker/internal/db"
+	"github.com/AnhCaooo/stormbreaker/internal/metrics"
 	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"github.com/AnhCaooo/stormbreaker/internal/tracing"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -15,6 +21,7 @@ const (
 	USER_NOTIFICATIONS_EXCHANGE string = "user_notifications_exchange"
 	USER_CREATE_KEY             string = "user.create"
 	USER_DELETE_KEY             string = "user.delete"
+	USER_DATA_ERASED_KEY        string = "user.data_erased" // published once user erased all own data through `DELETE /v1/me`
 	USER_CREATION_QUEUE         string = "user_creation_queue"
 	USER_DELETION_QUEUE         string = "user_deletion_queue"
 )
@@ -122,30 +129,72 @@ func (c *Consumer) Listen(stopChan <-chan struct{}, errChan chan<- error) {
 				return
 			}
 
-			// Process message
-			switch msg.RoutingKey {
-			case USER_CREATE_KEY:
-				c.logger.Info(fmt.Sprintf("[worker_%d] received a user created message", c.workerID))
-				var newPriceSettings models.PriceSettings
-				json.Unmarshal(msg.Body, &newPriceSettings)
-				_, err := c.mongo.InsertPriceSettings(newPriceSettings)
-				if err != nil {
-					errMsg := fmt.Errorf("[worker_%d] error inserting price settings: %s", c.workerID, err.Error())
-					errChan <- errMsg
-				}
-			case USER_DELETE_KEY:
-				var deletedPriceSettings models.PriceSettings
-				json.Unmarshal(msg.Body, &deletedPriceSettings)
-				c.logger.Info(fmt.Sprintf("[worker_%d] received a user deleted message. UserID: %s", c.workerID, deletedPriceSettings.UserID))
-				_, err := c.mongo.DeletePriceSettings(deletedPriceSettings.UserID)
-				if err != nil {
-					errMsg := fmt.Errorf("[worker_%d] error delete price settings: %s", c.workerID, err.Error())
-					errChan <- errMsg
-				}
-			default:
-				c.logger.Info(fmt.Sprintf("[worker_%d] received an message from undefined routing key: '%s' with message: %v", c.workerID, msg.RoutingKey, msg.Body))
-			}
+			c.process(msg, errChan)
+		}
+	}
+}
+
+// process handles the message by its routing key. The span of processing continues the trace of publisher
+// from the headers of message, so that the spans of database belong to it.
+func (c *Consumer) process(msg amqp.Delivery, errChan chan<- error) {
+	ctx := otel.GetTextMapPropagator().Extract(c.ctx, headerCarrier(msg.Headers))
+	ctx, span := tracing.Tracer().Start(ctx, fmt.Sprintf("process %s", c.queue.Name),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", msg.Exchange),
+			attribute.String("messaging.rabbitmq.destination.routing_key", msg.RoutingKey),
+			attribute.String("messaging.message.conversation_id", correlationID(msg)),
+		),
+	)
+	defer span.End()
 
+	// logs of message carry the id of request which caused it in other service
+	logger := c.logger.With(zap.String("correlation_id", correlationID(msg)))
+	mongo := c.mongo.WithLogger(logger).WithContext(ctx)
+
+	// Process message
+	switch msg.RoutingKey {
+	case USER_CREATE_KEY:
+		logger.Info(fmt.Sprintf("[worker_%d] received a user created message", c.workerID))
+		var newPriceSettings models.PriceSettings
+		json.Unmarshal(msg.Body, &newPriceSettings)
+		statusCode, err := mongo.InsertPriceSettings(newPriceSettings)
+		if statusCode == http.StatusConflict {
+			// message was delivered again, or user created price settings before this message arrived
+			logger.Info(fmt.Sprintf("[worker_%d] price settings of user exist already", c.workerID))
+			err = nil
+		} else if err != nil {
+			errMsg := fmt.Errorf("[worker_%d] error inserting price settings: %s", c.workerID, err.Error())
+			errChan <- errMsg
+		}
+		metrics.ObserveRabbitMQConsume(c.queue.Name, msg.RoutingKey, err)
+		tracing.RecordError(span, err)
+	case USER_DELETE_KEY:
+		var deletedPriceSettings models.PriceSettings
+		json.Unmarshal(msg.Body, &deletedPriceSettings)
+		logger.Info(fmt.Sprintf("[worker_%d] received a user deleted message. UserID: %s", c.workerID, deletedPriceSettings.UserID))
+		// remove all households of user together with their data, and the memberships of user in other households
+		_, err := mongo.EraseUserData(deletedPriceSettings.UserID)
+		if err != nil {
+			errMsg := fmt.Errorf("[worker_%d] error erase user data: %s", c.workerID, err.Error())
+			errChan <- errMsg
 		}
+		metrics.ObserveRabbitMQConsume(c.queue.Name, msg.RoutingKey, err)
+		tracing.RecordError(span, err)
+	default:
+		logger.Info(fmt.Sprintf("[worker_%d] received an message from undefined routing key: '%s' with message: %v", c.workerID, msg.RoutingKey, msg.Body))
+	}
+}
+
+// correlationID returns the id of request which caused the message, from its correlation id or `X-Request-ID` header.
+// Empty value is returned when the publisher did not send it.
+func correlationID(msg amqp.Delivery) string {
+	if msg.CorrelationId != "" {
+		return msg.CorrelationId
+	}
+	if requestID, ok := msg.Headers[REQUEST_ID_HEADER].(string); ok {
+		return requestID
 	}
+	return ""
 }
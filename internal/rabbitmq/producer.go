@@ -4,13 +4,24 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/metrics"
+	"github.com/AnhCaooo/stormbreaker/internal/tracing"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 const (
 	PUSH_NOTIFICATION_EXCHANGE string = "price_notifications"
 	PUSH_NOTIFICATION_KEY      string = "price_notification_key"
+	PRICE_ALERT_KEY            string = "price_alert_key"
+	BUDGET_ALERT_EXCHANGE      string = "budget_notifications"
+	BUDGET_ALERT_KEY           string = "budget_alert_key"
+	// REQUEST_ID_HEADER carries the id of request which caused the message, so that consumers can log it
+	REQUEST_ID_HEADER string = "X-Request-ID"
 )
 
 type Producer struct {
@@ -28,27 +39,61 @@ type Producer struct {
 	workerID int
 }
 
-// ProduceMessage publishes a message to the queue
+// ProduceMessage publishes a message to the queue.
+// When the context of producer belongs to a request, the id of request is sent as correlation id and `X-Request-ID` header.
+// The trace context is sent in headers, so that the spans of consumers belong to the same trace.
 func (p *Producer) ProduceMessage(message []byte) error {
 	if p.channel == nil {
 		return fmt.Errorf("[worker_%d] channel is nil, ensure connection is established", p.workerID)
 	}
 
+	ctx, span := tracing.Tracer().Start(p.ctx, fmt.Sprintf("publish %s", p.exchange),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", p.exchange),
+			attribute.String("messaging.rabbitmq.destination.routing_key", p.routingKey),
+		),
+	)
+	defer span.End()
+
+	publishing := newPublishing(ctx, message)
+	logger := p.logger
+	if publishing.CorrelationId != "" {
+		logger = logger.With(zap.String(constants.RequestIdField, publishing.CorrelationId))
+	}
+
 	mandatory, immediate := false, false
 	err := p.channel.PublishWithContext(
-		p.ctx,        // context
+		ctx,          // context
 		p.exchange,   // exchange
 		p.routingKey, // routing key
 		mandatory,    // mandatory
 		immediate,    // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        message,
-		})
+		publishing,
+	)
+	metrics.ObserveRabbitMQPublish(p.exchange, p.routingKey, err)
+	tracing.RecordError(span, err)
 	if err != nil {
 		return fmt.Errorf("[worker_%d] failed to publish message: %s", p.workerID, err.Error())
 	}
-	p.logger.Info(fmt.Sprintf("[worker_%d] message was produced successfully", p.workerID))
+	logger.Info(fmt.Sprintf("[worker_%d] message was produced successfully", p.workerID))
 	return nil
 
 }
+
+// newPublishing returns the message with the id of request (as correlation id and `X-Request-ID` header)
+// and the trace context of ctx in headers
+func newPublishing(ctx context.Context, message []byte) amqp.Publishing {
+	publishing := amqp.Publishing{
+		ContentType: "application/json",
+		Body:        message,
+		Headers:     amqp.Table{},
+	}
+	if requestID, ok := ctx.Value(constants.RequestIdKey).(string); ok {
+		publishing.CorrelationId = requestID
+		publishing.Headers[REQUEST_ID_HEADER] = requestID
+	}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(publishing.Headers))
+	return publishing
+}
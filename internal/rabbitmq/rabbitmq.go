@@ -78,6 +78,11 @@ func (r *RabbitMQ) EstablishConnection() (err error) {
 	return nil
 }
 
+// IsConnected reports whether the connection with RabbitMQ server is established and still open
+func (r *RabbitMQ) IsConnected() bool {
+	return r.connection != nil && !r.connection.IsClosed()
+}
+
 func (r *RabbitMQ) getURI() string {
 	return fmt.Sprintf("amqp://%s:%s@%s:%s/", r.config.Username, r.config.Password, r.config.Host, r.config.Port)
 }
@@ -117,7 +122,13 @@ func (r *RabbitMQ) CloseConnection() {
 //
 // The function uses a wait group to signal completion and logs the status of the producer initialization and message production.
 func (r *RabbitMQ) StartProducer(workerID int, exchange, routingKey string, message []byte) error {
-	msgProducer, err := r.newProducer(workerID, exchange, routingKey)
+	return r.StartProducerWithContext(r.ctx, workerID, exchange, routingKey, message)
+}
+
+// StartProducerWithContext is like StartProducer, but the message belongs to given context (ex: restored by RestoreMessageContext),
+// so that it carries the id of request and trace context of it.
+func (r *RabbitMQ) StartProducerWithContext(ctx context.Context, workerID int, exchange, routingKey string, message []byte) error {
+	msgProducer, err := r.newProducer(ctx, workerID, exchange, routingKey)
 	if err != nil {
 		errMsg := fmt.Errorf("[worker_%d] %s", workerID, err.Error())
 		return errMsg
@@ -131,7 +142,7 @@ func (r *RabbitMQ) StartProducer(workerID int, exchange, routingKey string, mess
 }
 
 // NewProducer retrieves connection client, then opens channel and build producer instance
-func (r *RabbitMQ) newProducer(workerID int, exchange, routingKey string) (*Producer, error) {
+func (r *RabbitMQ) newProducer(ctx context.Context, workerID int, exchange, routingKey string) (*Producer, error) {
 	// create a new channel
 	ch, err := r.connection.Channel()
 	if err != nil {
@@ -154,7 +165,7 @@ func (r *RabbitMQ) newProducer(workerID int, exchange, routingKey string) (*Prod
 	r.channels = append(r.channels, ch)
 	return &Producer{
 		channel:    ch,
-		ctx:        r.ctx,
+		ctx:        ctx,
 		exchange:   exchange,
 		logger:     r.logger,
 		routingKey: routingKey,
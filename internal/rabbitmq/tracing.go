@@ -0,0 +1,53 @@
+package rabbitmq
+
+import (
+	"context"
+
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// headerCarrier carries the trace context in the headers of AMQP message
+type headerCarrier amqp.Table
+
+func (c headerCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// SaveMessageContext returns the id of request and the trace context of ctx, so that a message which is
+// published later (ex: by the dispatching job of scheduler) still belongs to the request which caused it
+func SaveMessageContext(ctx context.Context) (requestID string, traceContext map[string]string) {
+	requestID, _ = ctx.Value(constants.RequestIdKey).(string)
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return requestID, nil
+	}
+	return requestID, carrier
+}
+
+// RestoreMessageContext returns ctx with the id of request and the trace context which were saved by SaveMessageContext
+func RestoreMessageContext(ctx context.Context, requestID string, traceContext map[string]string) context.Context {
+	if requestID != "" {
+		ctx = context.WithValue(ctx, constants.RequestIdKey, requestID)
+	}
+	if len(traceContext) > 0 {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(traceContext))
+	}
+	return ctx
+}
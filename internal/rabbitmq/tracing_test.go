@@ -0,0 +1,86 @@
+package rabbitmq
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AnhCaooo/stormbreaker/internal/constants"
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHeaderCarrier(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	propagator := propagation.TraceContext{}
+
+	// publisher sends the trace context in headers next to the id of request
+	ctx, publish := provider.Tracer("test").Start(context.Background(), "publish user_notifications_exchange")
+	headers := amqp.Table{REQUEST_ID_HEADER: "0f52c8dd5872b066e097d65204bc4483"}
+	propagator.Inject(ctx, headerCarrier(headers))
+	publish.End()
+
+	if _, ok := headers["traceparent"].(string); !ok {
+		t.Fatalf("trace context was not injected into headers: %v", headers)
+	}
+
+	// consumer continues the trace of publisher
+	ctx = propagator.Extract(context.Background(), headerCarrier(headers))
+	_, process := provider.Tracer("test").Start(ctx, "process user_creation_queue", trace.WithSpanKind(trace.SpanKindConsumer))
+	process.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[1].SpanContext.TraceID() != spans[0].SpanContext.TraceID() || spans[1].Parent.SpanID() != spans[0].SpanContext.SpanID() {
+		t.Errorf("span of consumer does not continue the trace of publisher")
+	}
+	if got := headerCarrier(headers).Get(REQUEST_ID_HEADER); got != "0f52c8dd5872b066e097d65204bc4483" {
+		t.Errorf("other headers were changed: %s", got)
+	}
+}
+
+func TestDispatchedMessageKeepsRequestContext(t *testing.T) {
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previousPropagator) })
+	provider := sdktrace.NewTracerProvider()
+
+	// request queues the message
+	ctx := context.WithValue(context.Background(), constants.RequestIdKey, "0f52c8dd5872b066e097d65204bc4483")
+	ctx, request := provider.Tracer("test").Start(ctx, "DELETE /v1/me")
+	requestID, traceContext := SaveMessageContext(ctx)
+	request.End()
+	stored, err := bson.Marshal(models.ScheduledNotification{Exchange: USER_NOTIFICATIONS_EXCHANGE, RequestID: requestID, TraceContext: traceContext})
+	if err != nil {
+		t.Fatalf("failed to store scheduled message: %v", err)
+	}
+
+	// scheduler dispatches the message in its own context
+	var notification models.ScheduledNotification
+	if err = bson.Unmarshal(stored, &notification); err != nil {
+		t.Fatalf("failed to load scheduled message: %v", err)
+	}
+	ctx = RestoreMessageContext(context.Background(), notification.RequestID, notification.TraceContext)
+	ctx, publish := provider.Tracer("test").Start(ctx, "publish "+notification.Exchange, trace.WithSpanKind(trace.SpanKindProducer))
+	publishing := newPublishing(ctx, []byte("{}"))
+	publish.End()
+
+	if publishing.CorrelationId != "0f52c8dd5872b066e097d65204bc4483" || publishing.Headers[REQUEST_ID_HEADER] != "0f52c8dd5872b066e097d65204bc4483" {
+		t.Errorf("id of request is missing from message: correlation id %q, headers %v", publishing.CorrelationId, publishing.Headers)
+	}
+	consumed := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), headerCarrier(publishing.Headers)))
+	if !consumed.IsValid() || consumed.TraceID() != request.SpanContext().TraceID() {
+		t.Errorf("message does not continue the trace of request: headers %v", publishing.Headers)
+	}
+	if consumed.SpanID() != publish.SpanContext().SpanID() {
+		t.Errorf("parent of consumer is not the publishing span")
+	}
+}
@@ -0,0 +1,108 @@
+// AnhCao 2024
+//
+// Package ratelimit limits how often a client (user or IP address) calls the service with token buckets.
+// Every client has its own bucket which holds up to `burst` tokens and is refilled at a steady rate.
+// Every request takes a token, and requests are rejected while the bucket is empty.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// SWEEP_INTERVAL is how often the buckets which are full again (clients which stopped calling) are removed
+const SWEEP_INTERVAL time.Duration = time.Minute
+
+// Decision is the result of a request, with the state of bucket for `RateLimit-*` headers
+type Decision struct {
+	Allowed    bool          // indicates whether the request may proceed
+	Limit      int           // size of bucket
+	Remaining  int           // amount of requests which client can still make immediately
+	Reset      time.Duration // time until the bucket is full again
+	RetryAfter time.Duration // time until the next request is allowed. Zero when the request was allowed.
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Limiter keeps a token bucket for every client
+type Limiter struct {
+	rate      float64 // tokens which are added per second
+	burst     int
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+	lock      sync.Mutex
+}
+
+// NewLimiter returns a new Limiter which allows `requestsPerMinute` requests per client on average
+// and `burst` requests at once
+func NewLimiter(requestsPerMinute float64, burst int) *Limiter {
+	return &Limiter{
+		rate:    requestsPerMinute / 60,
+		burst:   burst,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of client, if there is one
+func (l *Limiter) Allow(key string) Decision {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{tokens: float64(l.burst), updated: now}
+		l.buckets[key] = b
+	}
+	l.refill(b, now)
+
+	decision := Decision{Limit: l.burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = l.timeUntil(b.tokens, 1)
+	}
+	decision.Remaining = int(math.Floor(b.tokens))
+	decision.Reset = l.timeUntil(b.tokens, float64(l.burst))
+	return decision
+}
+
+// refill adds the tokens which were earned since the last request of client
+func (l *Limiter) refill(b *bucket, now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(l.burst), b.tokens+elapsed*l.rate)
+		b.updated = now
+	}
+}
+
+// timeUntil returns the time until the bucket with `tokens` has `target` tokens
+func (l *Limiter) timeUntil(tokens, target float64) time.Duration {
+	if tokens >= target || l.rate <= 0 {
+		return 0
+	}
+	return time.Duration((target - tokens) / l.rate * float64(time.Second))
+}
+
+// sweep removes the buckets which are full, because they behave the same as new ones
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < SWEEP_INTERVAL {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		l.refill(b, now)
+		if b.tokens >= float64(l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// AnhCao 2024
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAllow(t *testing.T) {
+	now := time.Date(2024, 12, 9, 12, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(60, 2) // a token per second
+	limiter.now = func() time.Time { return now }
+
+	steps := []struct {
+		name              string
+		elapsed           time.Duration
+		key               string
+		expectedAllowed   bool
+		expectedRemaining int
+		expectedRetry     time.Duration
+		expectedReset     time.Duration
+	}{
+		{name: "first request", key: "user:1", expectedAllowed: true, expectedRemaining: 1, expectedReset: time.Second},
+		{name: "burst", key: "user:1", expectedAllowed: true, expectedRemaining: 0, expectedReset: 2 * time.Second},
+		{name: "bucket is empty", key: "user:1", expectedAllowed: false, expectedRemaining: 0, expectedRetry: time.Second, expectedReset: 2 * time.Second},
+		{name: "other client has its own bucket", key: "ip:10.0.0.1", expectedAllowed: true, expectedRemaining: 1, expectedReset: time.Second},
+		{name: "half of token refilled", elapsed: 500 * time.Millisecond, key: "user:1", expectedAllowed: false, expectedRemaining: 0, expectedRetry: 500 * time.Millisecond, expectedReset: 1500 * time.Millisecond},
+		{name: "token refilled", elapsed: 500 * time.Millisecond, key: "user:1", expectedAllowed: true, expectedRemaining: 0, expectedReset: 2 * time.Second},
+		{name: "bucket does not exceed burst", elapsed: time.Hour, key: "user:1", expectedAllowed: true, expectedRemaining: 1, expectedReset: time.Second},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.elapsed)
+		got := limiter.Allow(step.key)
+		if got.Allowed != step.expectedAllowed || got.Remaining != step.expectedRemaining ||
+			got.RetryAfter != step.expectedRetry || got.Reset != step.expectedReset || got.Limit != 2 {
+			t.Errorf("%s: Allow() = %+v, want allowed %v, remaining %d, retry after %s, reset %s",
+				step.name, got, step.expectedAllowed, step.expectedRemaining, step.expectedRetry, step.expectedReset)
+		}
+	}
+}
+
+func TestSweep(t *testing.T) {
+	now := time.Date(2024, 12, 9, 12, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(60, 2)
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("user:1")
+	limiter.Allow("user:2")
+	now = now.Add(SWEEP_INTERVAL)
+	limiter.Allow("user:2")
+
+	if _, exists := limiter.buckets["user:1"]; exists {
+		t.Errorf("full bucket of idle client was not removed")
+	}
+	if _, exists := limiter.buckets["user:2"]; !exists {
+		t.Errorf("bucket of active client was removed")
+	}
+}
@@ -4,19 +4,37 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/AnhCaooo/stormbreaker/internal/cache"
 	"github.com/AnhCaooo/stormbreaker/internal/constants"
 	"github.com/AnhCaooo/stormbreaker/internal/db"
 	"github.com/AnhCaooo/stormbreaker/internal/electric"
+	"github.com/AnhCaooo/stormbreaker/internal/events"
 	"github.com/AnhCaooo/stormbreaker/internal/helpers"
+	"github.com/AnhCaooo/stormbreaker/internal/metrics"
 	"github.com/AnhCaooo/stormbreaker/internal/models"
 	"github.com/AnhCaooo/stormbreaker/internal/rabbitmq"
 	"go.uber.org/zap"
 )
 
+const (
+	// names of scheduling jobs in metrics
+	POLL_PRICE_JOB             string = "poll_price"
+	DISPATCH_NOTIFICATIONS_JOB string = "dispatch_notifications"
+
+	// NOTIFICATION_RETRY_DELAY is how long a scheduled message waits before it is published again after a failed attempt
+	NOTIFICATION_RETRY_DELAY time.Duration = 5 * time.Minute
+	// NOTIFICATION_MAX_ATTEMPTS is how many times a scheduled message is tried to publish before it is given up
+	NOTIFICATION_MAX_ATTEMPTS int = 5
+	// HEARTBEAT_INTERVAL is how often each job reports that it is running, also while it waits
+	HEARTBEAT_INTERVAL time.Duration = time.Minute
+)
+
 // Scheduler is responsible for managing and coordinating scheduled tasks.
 type Scheduler struct {
 	// The logger for logging RabbitMQ-related activities
@@ -29,43 +47,71 @@ type Scheduler struct {
 	mongo *db.Mongo
 	// Configuration settings for the RabbitMQ broker.
 	brokerConfig *models.Broker
-	// A pointer to a sync.WaitGroup to signal when the consumer has finished.
+	// The price settings which are applied for users without stored price settings.
+	defaultPriceSettings models.PriceSettingsDefaults
+	// A pointer to a sync.WaitGroup to signal when the jobs have finished.
 	wg *sync.WaitGroup
+	// The channel which is closed when the jobs should stop.
+	stopChan <-chan struct{}
+	// The hub which passes the publishing of tomorrow's prices to open event streams.
+	events *events.Hub
+	// The time (unix nanoseconds) when the polling job was last seen running.
+	pollHeartbeat atomic.Int64
+	// The time (unix nanoseconds) when the dispatching job was last seen running.
+	dispatchHeartbeat atomic.Int64
 }
 
-// NewScheduler creates a new instance of Scheduler with the provided context, logger, broker configuration, and MongoDB connection.
+// NewScheduler creates a new instance of Scheduler with the provided context, logger, broker configuration, default price settings and MongoDB connection.
 func NewScheduler(
 	ctx context.Context,
 	logger *zap.Logger,
 	brokerConfig *models.Broker,
+	defaultPriceSettings models.PriceSettingsDefaults,
 	cache *cache.Cache,
 	mongo *db.Mongo,
+	hub *events.Hub,
 ) *Scheduler {
 	return &Scheduler{
-		logger:       logger,
-		mongo:        mongo,
-		ctx:          ctx,
-		cache:        cache,
-		brokerConfig: brokerConfig,
+		logger:               logger,
+		mongo:                mongo,
+		ctx:                  ctx,
+		cache:                cache,
+		brokerConfig:         brokerConfig,
+		defaultPriceSettings: defaultPriceSettings,
+		events:               hub,
 	}
 }
 
 // StartJobs initializes and starts the scheduling jobs.
 // It logs the start of the scheduling process, assigns the provided WaitGroup to the scheduler,
-// increments the WaitGroup counter, and starts the PollPrice job in a new goroutine.
+// increments the WaitGroup counter for each job, and starts the PollPrice and DispatchNotifications jobs in new goroutines.
+// The jobs stop when `stopChan` is closed.
 func (s *Scheduler) StartJobs(
 	wg *sync.WaitGroup,
+	stopChan <-chan struct{},
 ) {
 	s.logger.Info("starting scheduling jobs...")
 	s.wg = wg
-	s.wg.Add(1)
+	s.stopChan = stopChan
+	s.wg.Add(2)
 	go s.PollPrice(4)
+	go s.DispatchNotifications(5)
+}
+
+// LastHeartbeat returns the time when all scheduling jobs were last seen running, which is the older heartbeat of the jobs.
+// Each job beats every HEARTBEAT_INTERVAL, so a job which stopped makes the heartbeat of scheduler old.
+// Zero time is returned while any job has not started yet.
+func (s *Scheduler) LastHeartbeat() time.Time {
+	heartbeat := min(s.pollHeartbeat.Load(), s.dispatchHeartbeat.Load())
+	if heartbeat == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, heartbeat)
 }
 
-// StopJobs stops the scheduling jobs by decrementing the WaitGroup counter.
-func (s *Scheduler) StopJobs() {
-	s.logger.Info("stopping scheduling jobs...")
-	s.wg.Done()
+// beat records that the job of heartbeat is running
+func beat(heartbeat *atomic.Int64) {
+	heartbeat.Store(time.Now().UnixNano())
 }
 
 // PollPrice continuously polls for electricity prices
@@ -76,34 +122,55 @@ func (s *Scheduler) StopJobs() {
 //   - workerID: an integer representing the ID of the worker executing the polling job.
 //
 // The method performs the following steps:
-//  1. Initializes a ticker to trigger every 5 seconds.
+//  1. Initializes a ticker to trigger every 10 minutes, and a ticker to report the heartbeat of the job every HEARTBEAT_INTERVAL.
 //  2. Logs the start of the polling job.
 //  3. Continuously checks the current time in Helsinki.
 //  4. If the current time is outside the polling hours, it pauses until the next polling period.
 //  5. Checks if the price for the next day is available.
-//  6. If the price is available and a notification has not been sent yet, it establishes a connection
-//     to RabbitMQ, sends a notification, and then closes the connection.
+//  6. If the price is available and notifications have not been queued yet, it queues the tomorrow-prices message
+//     of each user for their preferred delivery time, establishes a connection to RabbitMQ,
+//     sends price alerts and budget alerts, and then closes the connection.
 //  7. Waits until the next polling period and resets the job status.
+//
+// A step which fails is logged and tried again on the next tick, so the job keeps running until it is stopped.
 func (s *Scheduler) PollPrice(workerID int) {
 	const startTime = 14
 	const endTime = 17
 	ticker := time.NewTicker(10 * time.Minute)
+	heartbeat := time.NewTicker(HEARTBEAT_INTERVAL)
 	isJobDone := false
+	// tomorrow-prices messages are queued once per day, even when sending alerts fails and is tried again
+	isQueued := false
 	defer ticker.Stop()
+	defer heartbeat.Stop()
+	defer s.wg.Done()
 
 	s.logger.Info(fmt.Sprintf("[worker_%d] starting polling job...", workerID))
+	beat(&s.pollHeartbeat)
 	for {
+		select {
+		case <-s.stopChan:
+			s.logger.Info(fmt.Sprintf("[worker_%d] stopping polling job...", workerID))
+			return
+		case <-heartbeat.C:
+			beat(&s.pollHeartbeat)
+			continue
+		case <-ticker.C:
+		}
+		beat(&s.pollHeartbeat)
+
 		currentTime, _, err := helpers.GetCurrentTimeInHelsinki()
 		if err != nil {
 			errMsg := fmt.Errorf("[worker_%d] failed to get current time: %s", workerID, err.Error())
 			s.logger.Error(errMsg.Error())
-			return
+			metrics.ObserveSchedulerJob(POLL_PRICE_JOB, metrics.OUTCOME_FAILURE)
+			continue
 		}
-
-		<-ticker.C
 		if currentTime.Hour() < startTime || currentTime.Hour() >= endTime {
 			s.logger.Info(fmt.Sprintf("[worker_%d] outside polling price hours. Pause polling until next job", workerID), zap.Time("current_time_helsinki", currentTime))
-			s.waitUntilNextPollingPeriod(workerID, startTime, endTime, isJobDone)
+			if !s.waitUntilNextPollingPeriod(workerID, startTime, endTime, isJobDone) {
+				return
+			}
 			continue
 		}
 
@@ -111,39 +178,52 @@ func (s *Scheduler) PollPrice(workerID int) {
 		if err != nil {
 			errMsg := fmt.Errorf("[worker_%d] failed to check if tomorrow price is available: %s", workerID, err.Error())
 			s.logger.Error(errMsg.Error())
+			metrics.ObserveSchedulerJob(POLL_PRICE_JOB, metrics.OUTCOME_FAILURE)
 			continue
 		}
+		if !isPriceAvailableForNotification {
+			metrics.ObserveSchedulerJob(POLL_PRICE_JOB, metrics.OUTCOME_SKIPPED)
+		}
 
 		if isPriceAvailableForNotification && !isJobDone {
 			pricesMessage, exists := s.cache.Get(cache.PlainTodayTomorrowPricesKey)
 			if !exists {
+				// prices are fetched and cached again on the next tick
 				s.logger.Error(fmt.Sprintf("[worker_%d] failed to load plain spot price for today and tomorrow from cache", workerID))
-				return
+				metrics.ObserveSchedulerJob(POLL_PRICE_JOB, metrics.OUTCOME_FAILURE)
+				continue
+			}
+
+			if !isQueued {
+				s.logger.Info(fmt.Sprintf("[worker_%d] tomorrow price is available. Sending notifications...", workerID))
+				s.events.Publish(events.Event{Name: events.TOMORROW_AVAILABLE_EVENT})
+				// queue tomorrow-prices message of each user for their preferred delivery time
+				s.queueTomorrowPricesMessages(workerID, pricesMessage)
+				isQueued = true
 			}
 
-			s.logger.Info(fmt.Sprintf("[worker_%d] tomorrow price is available. Sending notifications...", workerID))
 			rabbit := rabbitmq.NewRabbit(s.ctx, s.brokerConfig, s.logger, s.mongo)
 			if err := rabbit.EstablishConnection(); err != nil {
+				// price alerts and budget alerts are sent on the next tick
 				errMsg := fmt.Errorf("[worker_%d] failed to establish connection with RabbitMQ: %s", workerID, err.Error())
 				s.logger.Error(errMsg.Error())
-				return
+				metrics.ObserveSchedulerJob(POLL_PRICE_JOB, metrics.OUTCOME_FAILURE)
+				continue
 			}
 			s.logger.Info(fmt.Sprintf("[worker_%d] successfully connected to RabbitMQ", workerID))
-			// send notifications for all users
-			jsonMessage, _ := json.Marshal(pricesMessage)
-			if err := rabbit.StartProducer(
-				workerID,
-				rabbitmq.PUSH_NOTIFICATION_EXCHANGE,
-				rabbitmq.PUSH_NOTIFICATION_KEY,
-				jsonMessage,
-			); err != nil {
-				s.logger.Error(err.Error())
-			}
+			// send personalized messages for users whose price alert rules match tomorrow's prices
+			s.sendPriceAlerts(workerID, rabbit, pricesMessage)
+			// warn users whose projected bill crosses their monthly budget
+			s.checkBudgets(workerID, rabbit)
+			metrics.ObserveSchedulerJob(POLL_PRICE_JOB, metrics.OUTCOME_SUCCESS)
 			isJobDone = true
 			// close connection after finish
 			rabbit.CloseConnection()
-			s.waitUntilNextPollingPeriod(workerID, startTime, endTime, isJobDone)
+			if !s.waitUntilNextPollingPeriod(workerID, startTime, endTime, isJobDone) {
+				return
+			}
 			isJobDone = false
+			isQueued = false
 		}
 	}
 
@@ -159,8 +239,9 @@ func (s *Scheduler) PollPrice(workerID int) {
 //   - endTime: an integer representing the hour of the day when polling should end
 //   - isJobDone: a boolean indicating whether the job is completed
 //
-// The function logs the duration of the wait and the time of the next polling period.
-func (s Scheduler) waitUntilNextPollingPeriod(workerID, startTime, endTime int, isJobDone bool) {
+// The function logs the duration of the wait and the time of the next polling period, and keeps beating while waiting.
+// It returns false when the jobs are stopped while waiting.
+func (s *Scheduler) waitUntilNextPollingPeriod(workerID, startTime, endTime int, isJobDone bool) bool {
 	now, location, err := helpers.GetCurrentTimeInHelsinki()
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("[worker_%d] failed to get current time", workerID), zap.Error(err))
@@ -176,7 +257,21 @@ func (s Scheduler) waitUntilNextPollingPeriod(workerID, startTime, endTime int,
 
 	duration := time.Until(nextStart)
 	s.logger.Info(fmt.Sprintf("[worker_%d] on holding for %v until the next polling at %v", workerID, duration, nextStart))
-	time.Sleep(duration)
+	timer := time.NewTimer(duration)
+	heartbeat := time.NewTicker(HEARTBEAT_INTERVAL)
+	defer timer.Stop()
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-timer.C:
+			return true
+		case <-heartbeat.C:
+			beat(&s.pollHeartbeat)
+		case <-s.stopChan:
+			s.logger.Info(fmt.Sprintf("[worker_%d] stopping polling job...", workerID))
+			return false
+		}
+	}
 }
 
 // isTomorrowPriceAvailable checks if the price for tomorrow is available.
@@ -184,7 +279,7 @@ func (s Scheduler) waitUntilNextPollingPeriod(workerID, startTime, endTime int,
 // It returns a boolean indicating the availability of tomorrow's price and an error if any occurs.
 func (s *Scheduler) isTomorrowPriceAvailable(workerID int) (bool, error) {
 	s.logger.Info(fmt.Sprintf("[worker_%d] checking if tomorrow price is available...", workerID))
-	electric := electric.NewElectric(s.logger, s.mongo, "stormbreaker", nil)
+	electric := electric.NewElectric(s.ctx, s.logger, s.mongo, "stormbreaker", nil, nil)
 
 	payloadForTodayTomorrow := electric.BuildTodayTomorrowRequestPayload()
 	prices, _, err := electric.FetchSpotPrice(payloadForTodayTomorrow)
@@ -214,9 +309,335 @@ func (s *Scheduler) isTomorrowPriceAvailable(workerID int) (bool, error) {
 		)
 	}
 	s.cache.SetExpiredAtTime(cache.PlainTodayTomorrowPricesKey, pricesMessage, expiredTime)
+	metrics.SetSpotPrices(todayTomorrowPrices)
 
 	if todayTomorrowPrices.Tomorrow.Available {
 		return true, nil
 	}
 	return false, nil
 }
+
+// checkBudgets projects the monthly bill of every household which has a monthly budget in the price settings.
+// When the projected bill crosses the budget, it publishes a budget alert message so that `notification-service` can warn the user.
+// Each household is warned at most once per month, which is recorded in database so that restarts do not warn it again.
+func (s *Scheduler) checkBudgets(workerID int, rabbit *rabbitmq.RabbitMQ) {
+	allSettings, err := s.mongo.GetPriceSettingsWithBudget()
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("[worker_%d] failed to load price settings with budget", workerID), zap.Error(err))
+		return
+	}
+
+	now, _, err := helpers.GetCurrentTimeInHelsinki()
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("[worker_%d] failed to get current time", workerID), zap.Error(err))
+		return
+	}
+	month := now.Format(helpers.MONTH_FORMAT)
+
+	for _, settings := range allSettings {
+		defaults := s.defaultPriceSettings.For(settings.UserID, settings.HouseholdID)
+		electric := electric.NewElectric(s.ctx, s.logger, s.mongo, settings.HouseholdID, &settings, &defaults)
+		projection, _, err := electric.ProjectMonthlyBill()
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("[worker_%d] failed to project monthly bill", workerID), zap.String("household_id", settings.HouseholdID), zap.Error(err))
+			continue
+		}
+		if !projection.BudgetExceeded {
+			continue
+		}
+
+		claimed, err := s.mongo.ClaimBudgetAlert(settings.HouseholdID, month, now)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("[worker_%d] failed to claim budget alert", workerID), zap.String("household_id", settings.HouseholdID), zap.Error(err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		jsonMessage, _ := json.Marshal(models.BudgetAlertMessage{
+			UserID:      settings.UserID,
+			HouseholdID: settings.HouseholdID,
+			Projection:  *projection,
+			TimeStamp:   now.String(),
+		})
+		if err := rabbit.StartProducer(
+			workerID,
+			rabbitmq.BUDGET_ALERT_EXCHANGE,
+			rabbitmq.BUDGET_ALERT_KEY,
+			jsonMessage,
+		); err != nil {
+			s.logger.Error(err.Error())
+			if err := s.mongo.ReleaseBudgetAlert(settings.HouseholdID, month); err != nil {
+				s.logger.Error(fmt.Sprintf("[worker_%d] failed to release budget alert", workerID), zap.String("household_id", settings.HouseholdID), zap.Error(err))
+			}
+			continue
+		}
+		s.logger.Info(fmt.Sprintf("[worker_%d] sent budget alert", workerID), zap.String("user_id", settings.UserID), zap.String("household_id", settings.HouseholdID))
+	}
+}
+
+// sendPriceAlerts evaluates the enabled price alert rules of all households against tomorrow's prices
+// (with household's price settings applied) and publishes a personalized message for each matching rule.
+func (s *Scheduler) sendPriceAlerts(workerID int, rabbit *rabbitmq.RabbitMQ, plainPrices interface{}) {
+	alerts, err := s.mongo.GetEnabledPriceAlerts()
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("[worker_%d] failed to load price alerts", workerID), zap.Error(err))
+		return
+	}
+
+	now, _, err := helpers.GetCurrentTimeInHelsinki()
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("[worker_%d] failed to get current time", workerID), zap.Error(err))
+	}
+
+	// tomorrow's prices with price settings applied, per household
+	householdPrices := make(map[string]*models.TodayTomorrowPrice)
+	for _, alert := range alerts {
+		prices, exists := householdPrices[alert.HouseholdID]
+		if !exists {
+			// copy plain prices so that mapping price settings does not modify the cached prices
+			pricesMessage, err := helpers.MapInterfaceToStruct[models.NewPricesMessage](plainPrices)
+			if err != nil {
+				s.logger.Error(fmt.Sprintf("[worker_%d] failed to cast cache data to NewPricesMessage", workerID), zap.Error(err))
+				return
+			}
+			// alerts are evaluated with the same prices as the API shows: default price settings when household has none stored
+			settings, statusCode, err := s.mongo.GetPriceSettings(alert.HouseholdID)
+			if statusCode == http.StatusNotFound {
+				defaults := s.defaultPriceSettings.For(alert.UserID, alert.HouseholdID)
+				settings, err = &defaults, nil
+			}
+			if err != nil {
+				s.logger.Error(fmt.Sprintf("[worker_%d] failed to load price settings, skip price alert", workerID), zap.String("household_id", alert.HouseholdID), zap.Error(err))
+				continue
+			}
+			prices = helpers.MapPriceSettingsWithTodayTomorrowSpotPrice(settings, &pricesMessage.Data)
+			householdPrices[alert.HouseholdID] = prices
+		}
+
+		matched := helpers.EvaluatePriceAlert(&alert, prices.Tomorrow.Prices.Data)
+		if len(matched) == 0 {
+			continue
+		}
+
+		jsonMessage, _ := json.Marshal(models.PriceAlertMessage{
+			UserID: alert.UserID,
+			Alert:  alert,
+			Prices: models.PriceSeries{
+				Name: prices.Tomorrow.Prices.Name,
+				Data: matched,
+			},
+			TimeStamp: now.String(),
+		})
+		if err := rabbit.StartProducer(
+			workerID,
+			rabbitmq.PUSH_NOTIFICATION_EXCHANGE,
+			rabbitmq.PRICE_ALERT_KEY,
+			jsonMessage,
+		); err != nil {
+			s.logger.Error(err.Error())
+			continue
+		}
+		s.logger.Info(fmt.Sprintf("[worker_%d] sent price alert", workerID), zap.String("user_id", alert.UserID), zap.String("type", alert.Type))
+	}
+}
+
+// queueTomorrowPricesMessages queues the tomorrow-prices message of each household of users who want it, for their preferred
+// delivery time and outside their quiet hours. The message contains the prices with household's price settings applied
+// and the preferred language. Users without stored notification preferences get the default preferences, and users
+// without stored price settings get the default price settings for their default household.
+// Users who are not known to the service are reached by a message for all users with the default price settings applied,
+// which excludes the users who get their own message or do not want it.
+func (s *Scheduler) queueTomorrowPricesMessages(workerID int, plainPrices interface{}) {
+	allSettings, err := s.mongo.GetAllPriceSettings()
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("[worker_%d] failed to load price settings of users", workerID), zap.Error(err))
+		return
+	}
+	allPreferences, err := s.mongo.GetAllNotificationPreferences()
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("[worker_%d] failed to load notification preferences of users", workerID), zap.Error(err))
+		return
+	}
+	preferencesByUser := make(map[string]*models.NotificationPreferences, len(allPreferences))
+	for i := range allPreferences {
+		preferencesByUser[allPreferences[i].UserID] = &allPreferences[i]
+	}
+	usersWithSettings := make(map[string]bool, len(allSettings))
+	for _, settings := range allSettings {
+		usersWithSettings[settings.UserID] = true
+	}
+	for _, preferences := range allPreferences {
+		if !usersWithSettings[preferences.UserID] {
+			allSettings = append(allSettings, s.defaultPriceSettings.For(preferences.UserID, preferences.UserID))
+			usersWithSettings[preferences.UserID] = true
+		}
+	}
+
+	now, _, err := helpers.GetCurrentTimeInHelsinki()
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("[worker_%d] failed to get current time", workerID), zap.Error(err))
+		return
+	}
+
+	queued := 0
+	for _, settings := range allSettings {
+		preferences, exists := preferencesByUser[settings.UserID]
+		if !exists {
+			preferences = helpers.DefaultNotificationPreferences(settings.UserID)
+		}
+		if !preferences.TomorrowPrices {
+			continue
+		}
+
+		pricesMessage, err := newPricesMessage(plainPrices, &settings)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("[worker_%d] failed to cast cache data to NewPricesMessage", workerID), zap.Error(err))
+			return
+		}
+		household, _, err := s.mongo.GetHousehold(settings.UserID, settings.HouseholdID)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("[worker_%d] failed to load household, skip its tomorrow-prices message", workerID), zap.String("household_id", settings.HouseholdID), zap.Error(err))
+			continue
+		}
+		pricesMessage.UserID = settings.UserID
+		pricesMessage.Household = household
+		pricesMessage.Language = preferences.Language
+		jsonMessage, _ := json.Marshal(pricesMessage)
+
+		if err := s.mongo.ScheduleNotification(models.ScheduledNotification{
+			UserID:      settings.UserID,
+			HouseholdID: settings.HouseholdID,
+			Exchange:    rabbitmq.PUSH_NOTIFICATION_EXCHANGE,
+			RoutingKey:  rabbitmq.PUSH_NOTIFICATION_KEY,
+			Body:        jsonMessage,
+			DeliverAt:   helpers.NextDeliveryTime(now, preferences),
+		}); err != nil {
+			s.logger.Error(fmt.Sprintf("[worker_%d] failed to queue tomorrow-prices message", workerID), zap.String("household_id", settings.HouseholdID), zap.Error(err))
+			continue
+		}
+		queued++
+	}
+	s.logger.Info(fmt.Sprintf("[worker_%d] queued tomorrow-prices messages", workerID), zap.Int("amount", queued))
+
+	// users with price settings or notification preferences either got their own message or do not want it
+	excludedUsers := make([]string, 0, len(usersWithSettings))
+	for userID := range usersWithSettings {
+		excludedUsers = append(excludedUsers, userID)
+	}
+	sort.Strings(excludedUsers)
+	defaultSettings := s.defaultPriceSettings.For("", "")
+	broadcastMessage, err := newPricesMessage(plainPrices, &defaultSettings)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("[worker_%d] failed to cast cache data to NewPricesMessage", workerID), zap.Error(err))
+		return
+	}
+	broadcastMessage.ExcludedUserIDs = excludedUsers
+	jsonMessage, _ := json.Marshal(broadcastMessage)
+	if err := s.mongo.ScheduleNotification(models.ScheduledNotification{
+		Exchange:   rabbitmq.PUSH_NOTIFICATION_EXCHANGE,
+		RoutingKey: rabbitmq.PUSH_NOTIFICATION_KEY,
+		Body:       jsonMessage,
+		DeliverAt:  now,
+	}); err != nil {
+		s.logger.Error(fmt.Sprintf("[worker_%d] failed to queue tomorrow-prices message for all users", workerID), zap.Error(err))
+	}
+}
+
+// newPricesMessage copies the plain prices into a NewPricesMessage, so that mapping the price settings does not modify the cached prices,
+// and applies the given price settings to it.
+func newPricesMessage(plainPrices interface{}, settings *models.PriceSettings) (*models.NewPricesMessage, error) {
+	pricesMessage, err := helpers.MapInterfaceToStruct[models.NewPricesMessage](plainPrices)
+	if err != nil {
+		return nil, err
+	}
+	pricesMessage.Data = *helpers.MapPriceSettingsWithTodayTomorrowSpotPrice(settings, &pricesMessage.Data)
+	return pricesMessage, nil
+}
+
+// DispatchNotifications continuously publishes the scheduled messages whose delivery time has come, until the jobs are stopped.
+// It checks the scheduled messages every minute and opens a RabbitMQ connection only when there is something to publish.
+//
+// Parameters:
+//   - workerID: an integer representing the ID of the worker executing the dispatching job.
+func (s *Scheduler) DispatchNotifications(workerID int) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	defer s.wg.Done()
+
+	s.logger.Info(fmt.Sprintf("[worker_%d] starting dispatching job...", workerID))
+	beat(&s.dispatchHeartbeat)
+	for {
+		select {
+		case <-s.stopChan:
+			s.logger.Info(fmt.Sprintf("[worker_%d] stopping dispatching job...", workerID))
+			return
+		case <-ticker.C:
+		}
+		beat(&s.dispatchHeartbeat)
+		s.dispatchDueNotifications(workerID)
+	}
+}
+
+// dispatchDueNotifications publishes every scheduled message whose delivery time has come. A published message is deleted,
+// while a message which fails stays scheduled and is published again after NOTIFICATION_RETRY_DELAY,
+// until it has failed NOTIFICATION_MAX_ATTEMPTS times.
+func (s *Scheduler) dispatchDueNotifications(workerID int) {
+	var rabbit *rabbitmq.RabbitMQ
+	published, failed := 0, 0
+	defer func() {
+		if rabbit != nil {
+			rabbit.CloseConnection()
+		}
+		if published+failed == 0 {
+			return
+		}
+		outcome := metrics.OUTCOME_SUCCESS
+		if failed > 0 {
+			outcome = metrics.OUTCOME_FAILURE
+		}
+		metrics.ObserveSchedulerJob(DISPATCH_NOTIFICATIONS_JOB, outcome)
+		s.logger.Info(fmt.Sprintf("[worker_%d] dispatched scheduled messages", workerID), zap.Int("published", published), zap.Int("failed", failed))
+	}()
+
+	for {
+		notification, err := s.mongo.ClaimDueNotification(time.Now(), NOTIFICATION_RETRY_DELAY)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("[worker_%d] failed to load scheduled messages", workerID), zap.Error(err))
+			failed++
+			return
+		}
+		if notification == nil {
+			return
+		}
+
+		if rabbit == nil {
+			rabbit = rabbitmq.NewRabbit(s.ctx, s.brokerConfig, s.logger, s.mongo)
+			if err := rabbit.EstablishConnection(); err != nil {
+				// the claimed message is published again after the retry delay, the others on the next tick
+				s.logger.Error(fmt.Sprintf("[worker_%d] failed to establish connection with RabbitMQ", workerID), zap.Error(err))
+				rabbit = nil
+				failed++
+				return
+			}
+		}
+
+		ctx := rabbitmq.RestoreMessageContext(s.ctx, notification.RequestID, notification.TraceContext)
+		if err := rabbit.StartProducerWithContext(ctx, workerID, notification.Exchange, notification.RoutingKey, notification.Body); err != nil {
+			failed++
+			if notification.Attempts < NOTIFICATION_MAX_ATTEMPTS {
+				s.logger.Warn(fmt.Sprintf("[worker_%d] failed to publish scheduled message, retry later", workerID),
+					zap.String("user_id", notification.UserID), zap.Int("attempts", notification.Attempts), zap.Error(err))
+				continue
+			}
+			s.logger.Error(fmt.Sprintf("[worker_%d] failed to publish scheduled message, give up", workerID),
+				zap.String("user_id", notification.UserID), zap.Int("attempts", notification.Attempts), zap.Error(err))
+		} else {
+			published++
+		}
+		if err := s.mongo.DeleteScheduledNotification(notification); err != nil {
+			s.logger.Error(fmt.Sprintf("[worker_%d] failed to delete scheduled message", workerID), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// AnhCao 2024
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestJobsStopWithStopChan(t *testing.T) {
+	scheduler := NewScheduler(context.Background(), zap.NewNop(), nil, models.PriceSettingsDefaults{}, nil, nil, nil)
+	var wg sync.WaitGroup
+	stopChan := make(chan struct{})
+	scheduler.StartJobs(&wg, stopChan)
+	close(stopChan)
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduling jobs did not stop")
+	}
+	if scheduler.LastHeartbeat().IsZero() {
+		t.Error("scheduling jobs did not beat when they started")
+	}
+}
+
+func TestLastHeartbeatIsOfOldestJob(t *testing.T) {
+	scheduler := NewScheduler(context.Background(), zap.NewNop(), nil, models.PriceSettingsDefaults{}, nil, nil, nil)
+	if !scheduler.LastHeartbeat().IsZero() {
+		t.Fatal("expected no heartbeat before jobs start")
+	}
+
+	beat(&scheduler.dispatchHeartbeat)
+	if !scheduler.LastHeartbeat().IsZero() {
+		t.Error("expected no heartbeat while polling job has not started")
+	}
+
+	polled := time.Now().Add(-time.Hour)
+	scheduler.pollHeartbeat.Store(polled.UnixNano())
+	if got := scheduler.LastHeartbeat(); !got.Equal(time.Unix(0, polled.UnixNano())) {
+		t.Errorf("expected heartbeat of polling job %v, got %v", polled, got)
+	}
+}
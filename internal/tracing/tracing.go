@@ -0,0 +1,78 @@
+// AnhCao 2024
+//
+// Package tracing sets up OpenTelemetry tracing. Spans are exported through OTLP (HTTP) when tracing is enabled
+// in configuration, otherwise they are no-op. The trace context is propagated with W3C `traceparent` header.
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// INSTRUMENTATION_NAME is the name of tracer which creates the spans of service
+	INSTRUMENTATION_NAME string = "github.com/AnhCaooo/stormbreaker"
+	DEFAULT_SERVICE_NAME string = "stormbreaker"
+)
+
+// Setup sets the propagator of trace context and, when tracing is enabled, the tracer provider which exports spans through OTLP.
+// The returned function flushes the remaining spans and stops the exporter. It has to be called on shutdown.
+func Setup(ctx context.Context, config models.Tracing) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !config.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	if config.Endpoint == "" {
+		return nil, errors.New("tracing is enabled, but `endpoint` of OTLP collector is empty")
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = DEFAULT_SERVICE_NAME
+	}
+	sampleRatio := config.SampleRatio
+	if sampleRatio <= 0 {
+		sampleRatio = 1
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		// follow the decision of caller, so that a trace is not cut in the middle
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of service from the current tracer provider
+func Tracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer(INSTRUMENTATION_NAME)
+}
+
+// RecordError marks the span as failed because of `err`. It is no-op when `err` is nil.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,35 @@
+// AnhCao 2024
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AnhCaooo/stormbreaker/internal/models"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   models.Tracing
+		hasError bool
+	}{
+		{name: "disabled", config: models.Tracing{Enabled: false}, hasError: false},
+		{name: "enabled without endpoint", config: models.Tracing{Enabled: true}, hasError: true},
+		{name: "enabled", config: models.Tracing{Enabled: true, Endpoint: "localhost:4318", Insecure: true}, hasError: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shutdown, err := Setup(context.Background(), test.config)
+			if (err != nil) != test.hasError {
+				t.Fatalf("Setup() error = %v, want error %v", err, test.hasError)
+			}
+			if err == nil {
+				if err := shutdown(context.Background()); err != nil {
+					t.Errorf("shutdown() error = %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,1169 @@
+// AnhCao 2024
+//
+// gRPC API of stormbreaker for internal backend services (ex: notification-service).
+// It serves the same data as the REST API, with the same authentication: every call sends
+// the access token of user through `authorization` metadata (Ex: "Bearer <access_token>").
+//
+// Generate Go code after changing this file: make proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        v5.29.3
+// source: stormbreaker/v1/stormbreaker.proto
+
+package stormbreakerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PriceSlot is the price of electric at specific time
+type PriceSlot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time       string  `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`                                // start of the slot in Finnish time. Ex: 2024-12-09 00:00:00
+	TimeUtc    string  `protobuf:"bytes,2,opt,name=time_utc,json=timeUtc,proto3" json:"time_utc,omitempty"`           // start of the slot in UTC. Ex: 2024-12-08 22:00:00
+	Price      float64 `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`                            // price of the slot
+	VatFactor  float64 `protobuf:"fixed64,4,opt,name=vat_factor,json=vatFactor,proto3" json:"vat_factor,omitempty"`   // VAT which applies to the price. Ex: 1.255
+	IsToday    bool    `protobuf:"varint,5,opt,name=is_today,json=isToday,proto3" json:"is_today,omitempty"`          // indicates whether the slot is today
+	IncludeVat bool    `protobuf:"varint,6,opt,name=include_vat,json=includeVat,proto3" json:"include_vat,omitempty"` // indicates whether VAT is included to the price
+}
+
+func (x *PriceSlot) Reset() {
+	*x = PriceSlot{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceSlot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceSlot) ProtoMessage() {}
+
+func (x *PriceSlot) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceSlot.ProtoReflect.Descriptor instead.
+func (*PriceSlot) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PriceSlot) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *PriceSlot) GetTimeUtc() string {
+	if x != nil {
+		return x.TimeUtc
+	}
+	return ""
+}
+
+func (x *PriceSlot) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PriceSlot) GetVatFactor() float64 {
+	if x != nil {
+		return x.VatFactor
+	}
+	return 0
+}
+
+func (x *PriceSlot) GetIsToday() bool {
+	if x != nil {
+		return x.IsToday
+	}
+	return false
+}
+
+func (x *PriceSlot) GetIncludeVat() bool {
+	if x != nil {
+		return x.IncludeVat
+	}
+	return false
+}
+
+// PriceSeries is a series of price slots with the unit of price
+type PriceSeries struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Unit  string       `protobuf:"bytes,1,opt,name=unit,proto3" json:"unit,omitempty"` // unit of price. Ex: c/kWh
+	Slots []*PriceSlot `protobuf:"bytes,2,rep,name=slots,proto3" json:"slots,omitempty"`
+}
+
+func (x *PriceSeries) Reset() {
+	*x = PriceSeries{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceSeries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceSeries) ProtoMessage() {}
+
+func (x *PriceSeries) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceSeries.ProtoReflect.Descriptor instead.
+func (*PriceSeries) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PriceSeries) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *PriceSeries) GetSlots() []*PriceSlot {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+// DailyPrice is the prices of a day, if they are available
+type DailyPrice struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Available bool         `protobuf:"varint,1,opt,name=available,proto3" json:"available,omitempty"`
+	Prices    *PriceSeries `protobuf:"bytes,2,opt,name=prices,proto3" json:"prices,omitempty"`
+}
+
+func (x *DailyPrice) Reset() {
+	*x = DailyPrice{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyPrice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyPrice) ProtoMessage() {}
+
+func (x *DailyPrice) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyPrice.ProtoReflect.Descriptor instead.
+func (*DailyPrice) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DailyPrice) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+func (x *DailyPrice) GetPrices() *PriceSeries {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+type GetTodayTomorrowRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HouseholdId string `protobuf:"bytes,1,opt,name=household_id,json=householdId,proto3" json:"household_id,omitempty"`
+}
+
+func (x *GetTodayTomorrowRequest) Reset() {
+	*x = GetTodayTomorrowRequest{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodayTomorrowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodayTomorrowRequest) ProtoMessage() {}
+
+func (x *GetTodayTomorrowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodayTomorrowRequest.ProtoReflect.Descriptor instead.
+func (*GetTodayTomorrowRequest) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTodayTomorrowRequest) GetHouseholdId() string {
+	if x != nil {
+		return x.HouseholdId
+	}
+	return ""
+}
+
+type TodayTomorrowPrice struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Today    *DailyPrice `protobuf:"bytes,1,opt,name=today,proto3" json:"today,omitempty"`
+	Tomorrow *DailyPrice `protobuf:"bytes,2,opt,name=tomorrow,proto3" json:"tomorrow,omitempty"`
+}
+
+func (x *TodayTomorrowPrice) Reset() {
+	*x = TodayTomorrowPrice{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodayTomorrowPrice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodayTomorrowPrice) ProtoMessage() {}
+
+func (x *TodayTomorrowPrice) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodayTomorrowPrice.ProtoReflect.Descriptor instead.
+func (*TodayTomorrowPrice) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TodayTomorrowPrice) GetToday() *DailyPrice {
+	if x != nil {
+		return x.Today
+	}
+	return nil
+}
+
+func (x *TodayTomorrowPrice) GetTomorrow() *DailyPrice {
+	if x != nil {
+		return x.Tomorrow
+	}
+	return nil
+}
+
+type GetMarketPriceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HouseholdId       string `protobuf:"bytes,1,opt,name=household_id,json=householdId,proto3" json:"household_id,omitempty"`
+	StartDate         string `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"` // Ex: 2024-12-11
+	EndDate           string `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`       // Ex: 2024-12-31
+	Group             string `protobuf:"bytes,4,opt,name=group,proto3" json:"group,omitempty"`                          // hour, day, week, month or year
+	CompareToLastYear bool   `protobuf:"varint,5,opt,name=compare_to_last_year,json=compareToLastYear,proto3" json:"compare_to_last_year,omitempty"`
+}
+
+func (x *GetMarketPriceRequest) Reset() {
+	*x = GetMarketPriceRequest{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMarketPriceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMarketPriceRequest) ProtoMessage() {}
+
+func (x *GetMarketPriceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMarketPriceRequest.ProtoReflect.Descriptor instead.
+func (*GetMarketPriceRequest) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetMarketPriceRequest) GetHouseholdId() string {
+	if x != nil {
+		return x.HouseholdId
+	}
+	return ""
+}
+
+func (x *GetMarketPriceRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetMarketPriceRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *GetMarketPriceRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *GetMarketPriceRequest) GetCompareToLastYear() bool {
+	if x != nil {
+		return x.CompareToLastYear
+	}
+	return false
+}
+
+type MarketPrice struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Group  string         `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Series []*PriceSeries `protobuf:"bytes,2,rep,name=series,proto3" json:"series,omitempty"`
+}
+
+func (x *MarketPrice) Reset() {
+	*x = MarketPrice{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarketPrice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarketPrice) ProtoMessage() {}
+
+func (x *MarketPrice) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarketPrice.ProtoReflect.Descriptor instead.
+func (*MarketPrice) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MarketPrice) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *MarketPrice) GetSeries() []*PriceSeries {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+type PriceSettings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId        string  `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // id of the user who owns the household
+	HouseholdId   string  `protobuf:"bytes,2,opt,name=household_id,json=householdId,proto3" json:"household_id,omitempty"`
+	VatIncluded   bool    `protobuf:"varint,3,opt,name=vat_included,json=vatIncluded,proto3" json:"vat_included,omitempty"`
+	Margin        float64 `protobuf:"fixed64,4,opt,name=margin,proto3" json:"margin,omitempty"`                                    // c/kWh
+	MonthlyBudget float64 `protobuf:"fixed64,5,opt,name=monthly_budget,json=monthlyBudget,proto3" json:"monthly_budget,omitempty"` // EUR, 0 means no budget
+	Version       int64   `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`                                   // increases on every change of the settings
+}
+
+func (x *PriceSettings) Reset() {
+	*x = PriceSettings{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceSettings) ProtoMessage() {}
+
+func (x *PriceSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceSettings.ProtoReflect.Descriptor instead.
+func (*PriceSettings) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PriceSettings) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PriceSettings) GetHouseholdId() string {
+	if x != nil {
+		return x.HouseholdId
+	}
+	return ""
+}
+
+func (x *PriceSettings) GetVatIncluded() bool {
+	if x != nil {
+		return x.VatIncluded
+	}
+	return false
+}
+
+func (x *PriceSettings) GetMargin() float64 {
+	if x != nil {
+		return x.Margin
+	}
+	return 0
+}
+
+func (x *PriceSettings) GetMonthlyBudget() float64 {
+	if x != nil {
+		return x.MonthlyBudget
+	}
+	return 0
+}
+
+func (x *PriceSettings) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type GetPriceSettingsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HouseholdId string `protobuf:"bytes,1,opt,name=household_id,json=householdId,proto3" json:"household_id,omitempty"`
+}
+
+func (x *GetPriceSettingsRequest) Reset() {
+	*x = GetPriceSettingsRequest{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPriceSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPriceSettingsRequest) ProtoMessage() {}
+
+func (x *GetPriceSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPriceSettingsRequest.ProtoReflect.Descriptor instead.
+func (*GetPriceSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetPriceSettingsRequest) GetHouseholdId() string {
+	if x != nil {
+		return x.HouseholdId
+	}
+	return ""
+}
+
+// UpdatePriceSettingsRequest changes only the fields which are present
+type UpdatePriceSettingsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HouseholdId     string   `protobuf:"bytes,1,opt,name=household_id,json=householdId,proto3" json:"household_id,omitempty"`
+	VatIncluded     *bool    `protobuf:"varint,2,opt,name=vat_included,json=vatIncluded,proto3,oneof" json:"vat_included,omitempty"`
+	Margin          *float64 `protobuf:"fixed64,3,opt,name=margin,proto3,oneof" json:"margin,omitempty"`
+	MonthlyBudget   *float64 `protobuf:"fixed64,4,opt,name=monthly_budget,json=monthlyBudget,proto3,oneof" json:"monthly_budget,omitempty"`      // 0 removes the budget
+	ExpectedVersion *int64   `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"` // required: version of the settings which client changes, so that changes from other clients are not overwritten
+}
+
+func (x *UpdatePriceSettingsRequest) Reset() {
+	*x = UpdatePriceSettingsRequest{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePriceSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePriceSettingsRequest) ProtoMessage() {}
+
+func (x *UpdatePriceSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePriceSettingsRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePriceSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdatePriceSettingsRequest) GetHouseholdId() string {
+	if x != nil {
+		return x.HouseholdId
+	}
+	return ""
+}
+
+func (x *UpdatePriceSettingsRequest) GetVatIncluded() bool {
+	if x != nil && x.VatIncluded != nil {
+		return *x.VatIncluded
+	}
+	return false
+}
+
+func (x *UpdatePriceSettingsRequest) GetMargin() float64 {
+	if x != nil && x.Margin != nil {
+		return *x.Margin
+	}
+	return 0
+}
+
+func (x *UpdatePriceSettingsRequest) GetMonthlyBudget() float64 {
+	if x != nil && x.MonthlyBudget != nil {
+		return *x.MonthlyBudget
+	}
+	return 0
+}
+
+func (x *UpdatePriceSettingsRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type DeletePriceSettingsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HouseholdId string `protobuf:"bytes,1,opt,name=household_id,json=householdId,proto3" json:"household_id,omitempty"`
+}
+
+func (x *DeletePriceSettingsRequest) Reset() {
+	*x = DeletePriceSettingsRequest{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePriceSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePriceSettingsRequest) ProtoMessage() {}
+
+func (x *DeletePriceSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePriceSettingsRequest.ProtoReflect.Descriptor instead.
+func (*DeletePriceSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeletePriceSettingsRequest) GetHouseholdId() string {
+	if x != nil {
+		return x.HouseholdId
+	}
+	return ""
+}
+
+type SubscribePricesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HouseholdId string `protobuf:"bytes,1,opt,name=household_id,json=householdId,proto3" json:"household_id,omitempty"`
+}
+
+func (x *SubscribePricesRequest) Reset() {
+	*x = SubscribePricesRequest{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribePricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribePricesRequest) ProtoMessage() {}
+
+func (x *SubscribePricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribePricesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribePricesRequest) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SubscribePricesRequest) GetHouseholdId() string {
+	if x != nil {
+		return x.HouseholdId
+	}
+	return ""
+}
+
+// CurrentPrice is the price of the slot which is in force at the moment
+type CurrentPrice struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time    string  `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	TimeUtc string  `protobuf:"bytes,2,opt,name=time_utc,json=timeUtc,proto3" json:"time_utc,omitempty"`
+	Price   float64 `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Unit    string  `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+}
+
+func (x *CurrentPrice) Reset() {
+	*x = CurrentPrice{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CurrentPrice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrentPrice) ProtoMessage() {}
+
+func (x *CurrentPrice) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrentPrice.ProtoReflect.Descriptor instead.
+func (*CurrentPrice) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CurrentPrice) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *CurrentPrice) GetTimeUtc() string {
+	if x != nil {
+		return x.TimeUtc
+	}
+	return ""
+}
+
+func (x *CurrentPrice) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CurrentPrice) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type PriceEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*PriceEvent_Price
+	//	*PriceEvent_TomorrowAvailable
+	//	*PriceEvent_SettingsChanged
+	Event isPriceEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *PriceEvent) Reset() {
+	*x = PriceEvent{}
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceEvent) ProtoMessage() {}
+
+func (x *PriceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_stormbreaker_v1_stormbreaker_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceEvent.ProtoReflect.Descriptor instead.
+func (*PriceEvent) Descriptor() ([]byte, []int) {
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP(), []int{13}
+}
+
+func (m *PriceEvent) GetEvent() isPriceEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *PriceEvent) GetPrice() *CurrentPrice {
+	if x, ok := x.GetEvent().(*PriceEvent_Price); ok {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *PriceEvent) GetTomorrowAvailable() *DailyPrice {
+	if x, ok := x.GetEvent().(*PriceEvent_TomorrowAvailable); ok {
+		return x.TomorrowAvailable
+	}
+	return nil
+}
+
+func (x *PriceEvent) GetSettingsChanged() *PriceSettings {
+	if x, ok := x.GetEvent().(*PriceEvent_SettingsChanged); ok {
+		return x.SettingsChanged
+	}
+	return nil
+}
+
+type isPriceEvent_Event interface {
+	isPriceEvent_Event()
+}
+
+type PriceEvent_Price struct {
+	Price *CurrentPrice `protobuf:"bytes,1,opt,name=price,proto3,oneof"`
+}
+
+type PriceEvent_TomorrowAvailable struct {
+	TomorrowAvailable *DailyPrice `protobuf:"bytes,2,opt,name=tomorrow_available,json=tomorrowAvailable,proto3,oneof"`
+}
+
+type PriceEvent_SettingsChanged struct {
+	SettingsChanged *PriceSettings `protobuf:"bytes,3,opt,name=settings_changed,json=settingsChanged,proto3,oneof"`
+}
+
+func (*PriceEvent_Price) isPriceEvent_Event() {}
+
+func (*PriceEvent_TomorrowAvailable) isPriceEvent_Event() {}
+
+func (*PriceEvent_SettingsChanged) isPriceEvent_Event() {}
+
+var File_stormbreaker_v1_stormbreaker_proto protoreflect.FileDescriptor
+
+var file_stormbreaker_v1_stormbreaker_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2f, 0x76,
+	0x31, 0x2f, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0f, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0xab, 0x01, 0x0a, 0x09, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x6c, 0x6f, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x74, 0x63,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x74, 0x63, 0x12,
+	0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05,
+	0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x76, 0x61, 0x74, 0x5f, 0x66, 0x61, 0x63,
+	0x74, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x76, 0x61, 0x74, 0x46, 0x61,
+	0x63, 0x74, 0x6f, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x74, 0x6f, 0x64, 0x61, 0x79,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x54, 0x6f, 0x64, 0x61, 0x79, 0x12,
+	0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x76, 0x61, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x56, 0x61, 0x74,
+	0x22, 0x53, 0x0a, 0x0b, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12,
+	0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75,
+	0x6e, 0x69, 0x74, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x6c, 0x6f, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x6c, 0x6f, 0x74, 0x52, 0x05,
+	0x73, 0x6c, 0x6f, 0x74, 0x73, 0x22, 0x60, 0x0a, 0x0a, 0x44, 0x61, 0x69, 0x6c, 0x79, 0x50, 0x72,
+	0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c,
+	0x65, 0x12, 0x34, 0x0a, 0x06, 0x70, 0x72, 0x69, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52,
+	0x06, 0x70, 0x72, 0x69, 0x63, 0x65, 0x73, 0x22, 0x3c, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x54, 0x6f,
+	0x64, 0x61, 0x79, 0x54, 0x6f, 0x6d, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x68,
+	0x6f, 0x6c, 0x64, 0x49, 0x64, 0x22, 0x80, 0x01, 0x0a, 0x12, 0x54, 0x6f, 0x64, 0x61, 0x79, 0x54,
+	0x6f, 0x6d, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x31, 0x0a, 0x05,
+	0x74, 0x6f, 0x64, 0x61, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x74,
+	0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x61,
+	0x69, 0x6c, 0x79, 0x50, 0x72, 0x69, 0x63, 0x65, 0x52, 0x05, 0x74, 0x6f, 0x64, 0x61, 0x79, 0x12,
+	0x37, 0x0a, 0x08, 0x74, 0x6f, 0x6d, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x44, 0x61, 0x69, 0x6c, 0x79, 0x50, 0x72, 0x69, 0x63, 0x65, 0x52, 0x08,
+	0x74, 0x6f, 0x6d, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x22, 0xbb, 0x01, 0x0a, 0x15, 0x47, 0x65, 0x74,
+	0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x68,
+	0x6f, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x44, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x44, 0x61, 0x74, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x2f, 0x0a, 0x14, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65,
+	0x5f, 0x74, 0x6f, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x11, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65, 0x54, 0x6f, 0x4c, 0x61,
+	0x73, 0x74, 0x59, 0x65, 0x61, 0x72, 0x22, 0x59, 0x0a, 0x0b, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74,
+	0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x34, 0x0a, 0x06, 0x73,
+	0x65, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x73, 0x74,
+	0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x69, 0x63, 0x65, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x06, 0x73, 0x65, 0x72, 0x69, 0x65,
+	0x73, 0x22, 0xc7, 0x01, 0x0a, 0x0d, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x74, 0x69,
+	0x6e, 0x67, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c,
+	0x68, 0x6f, 0x75, 0x73, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x49, 0x64, 0x12,
+	0x21, 0x0a, 0x0c, 0x76, 0x61, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x76, 0x61, 0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x06, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x6d, 0x6f,
+	0x6e, 0x74, 0x68, 0x6c, 0x79, 0x5f, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0d, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x6c, 0x79, 0x42, 0x75, 0x64, 0x67, 0x65,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x3c, 0x0a, 0x17, 0x47,
+	0x65, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x68,
+	0x6f, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x68, 0x6f,
+	0x75, 0x73, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x49, 0x64, 0x22, 0xa4, 0x02, 0x0a, 0x1a, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x6f, 0x75, 0x73,
+	0x65, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x68, 0x6f, 0x75, 0x73, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0c, 0x76,
+	0x61, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x00, 0x52, 0x0b, 0x76, 0x61, 0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64,
+	0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x06, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x88, 0x01, 0x01,
+	0x12, 0x2a, 0x0a, 0x0e, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x6c, 0x79, 0x5f, 0x62, 0x75, 0x64, 0x67,
+	0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x48, 0x02, 0x52, 0x0d, 0x6d, 0x6f, 0x6e, 0x74,
+	0x68, 0x6c, 0x79, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x10,
+	0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a, 0x0d,
+	0x5f, 0x76, 0x61, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x42, 0x09, 0x0a,
+	0x07, 0x5f, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x6d, 0x6f, 0x6e,
+	0x74, 0x68, 0x6c, 0x79, 0x5f, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x42, 0x13, 0x0a, 0x11, 0x5f,
+	0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x22, 0x3f, 0x0a, 0x1a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53,
+	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21,
+	0x0a, 0x0c, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x49,
+	0x64, 0x22, 0x3b, 0x0a, 0x16, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x50, 0x72,
+	0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x68,
+	0x6f, 0x75, 0x73, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x49, 0x64, 0x22, 0x67,
+	0x0a, 0x0c, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69,
+	0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x74, 0x63, 0x12, 0x14, 0x0a,
+	0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72,
+	0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x22, 0xe7, 0x01, 0x0a, 0x0a, 0x50, 0x72, 0x69, 0x63,
+	0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x35, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65,
+	0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x50,
+	0x72, 0x69, 0x63, 0x65, 0x48, 0x00, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x4c, 0x0a,
+	0x12, 0x74, 0x6f, 0x6d, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x5f, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61,
+	0x62, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x74, 0x6f, 0x72,
+	0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x61, 0x69, 0x6c,
+	0x79, 0x50, 0x72, 0x69, 0x63, 0x65, 0x48, 0x00, 0x52, 0x11, 0x74, 0x6f, 0x6d, 0x6f, 0x72, 0x72,
+	0x6f, 0x77, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x4b, 0x0a, 0x10, 0x73,
+	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65,
+	0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74,
+	0x74, 0x69, 0x6e, 0x67, 0x73, 0x48, 0x00, 0x52, 0x0f, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67,
+	0x73, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x32, 0xc2, 0x04, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b,
+	0x65, 0x72, 0x12, 0x61, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x54, 0x6f, 0x64, 0x61, 0x79, 0x54, 0x6f,
+	0x6d, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x12, 0x28, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72,
+	0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x6f, 0x64, 0x61,
+	0x79, 0x54, 0x6f, 0x6d, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x23, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x6f, 0x64, 0x61, 0x79, 0x54, 0x6f, 0x6d, 0x6f, 0x72, 0x72, 0x6f, 0x77,
+	0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x56, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x72, 0x6b,
+	0x65, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x26, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62,
+	0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x72,
+	0x6b, 0x65, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x5c, 0x0a,
+	0x10, 0x47, 0x65, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67,
+	0x73, 0x12, 0x28, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x74,
+	0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x73, 0x74,
+	0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x62, 0x0a, 0x13, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e,
+	0x67, 0x73, 0x12, 0x2b, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65,
+	0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1e, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12,
+	0x5a, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x2b, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72,
+	0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50,
+	0x72, 0x69, 0x63, 0x65, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x59, 0x0a, 0x0f, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x73, 0x12, 0x27,
+	0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62,
+	0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x47, 0x5a, 0x45, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x41, 0x6e, 0x68, 0x43, 0x61, 0x6f, 0x6f, 0x6f, 0x2f, 0x73, 0x74,
+	0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2f, 0x76, 0x31,
+	0x3b, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x76, 0x31, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_stormbreaker_v1_stormbreaker_proto_rawDescOnce sync.Once
+	file_stormbreaker_v1_stormbreaker_proto_rawDescData = file_stormbreaker_v1_stormbreaker_proto_rawDesc
+)
+
+func file_stormbreaker_v1_stormbreaker_proto_rawDescGZIP() []byte {
+	file_stormbreaker_v1_stormbreaker_proto_rawDescOnce.Do(func() {
+		file_stormbreaker_v1_stormbreaker_proto_rawDescData = protoimpl.X.CompressGZIP(file_stormbreaker_v1_stormbreaker_proto_rawDescData)
+	})
+	return file_stormbreaker_v1_stormbreaker_proto_rawDescData
+}
+
+var file_stormbreaker_v1_stormbreaker_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_stormbreaker_v1_stormbreaker_proto_goTypes = []any{
+	(*PriceSlot)(nil),                  // 0: stormbreaker.v1.PriceSlot
+	(*PriceSeries)(nil),                // 1: stormbreaker.v1.PriceSeries
+	(*DailyPrice)(nil),                 // 2: stormbreaker.v1.DailyPrice
+	(*GetTodayTomorrowRequest)(nil),    // 3: stormbreaker.v1.GetTodayTomorrowRequest
+	(*TodayTomorrowPrice)(nil),         // 4: stormbreaker.v1.TodayTomorrowPrice
+	(*GetMarketPriceRequest)(nil),      // 5: stormbreaker.v1.GetMarketPriceRequest
+	(*MarketPrice)(nil),                // 6: stormbreaker.v1.MarketPrice
+	(*PriceSettings)(nil),              // 7: stormbreaker.v1.PriceSettings
+	(*GetPriceSettingsRequest)(nil),    // 8: stormbreaker.v1.GetPriceSettingsRequest
+	(*UpdatePriceSettingsRequest)(nil), // 9: stormbreaker.v1.UpdatePriceSettingsRequest
+	(*DeletePriceSettingsRequest)(nil), // 10: stormbreaker.v1.DeletePriceSettingsRequest
+	(*SubscribePricesRequest)(nil),     // 11: stormbreaker.v1.SubscribePricesRequest
+	(*CurrentPrice)(nil),               // 12: stormbreaker.v1.CurrentPrice
+	(*PriceEvent)(nil),                 // 13: stormbreaker.v1.PriceEvent
+	(*emptypb.Empty)(nil),              // 14: google.protobuf.Empty
+}
+var file_stormbreaker_v1_stormbreaker_proto_depIdxs = []int32{
+	0,  // 0: stormbreaker.v1.PriceSeries.slots:type_name -> stormbreaker.v1.PriceSlot
+	1,  // 1: stormbreaker.v1.DailyPrice.prices:type_name -> stormbreaker.v1.PriceSeries
+	2,  // 2: stormbreaker.v1.TodayTomorrowPrice.today:type_name -> stormbreaker.v1.DailyPrice
+	2,  // 3: stormbreaker.v1.TodayTomorrowPrice.tomorrow:type_name -> stormbreaker.v1.DailyPrice
+	1,  // 4: stormbreaker.v1.MarketPrice.series:type_name -> stormbreaker.v1.PriceSeries
+	12, // 5: stormbreaker.v1.PriceEvent.price:type_name -> stormbreaker.v1.CurrentPrice
+	2,  // 6: stormbreaker.v1.PriceEvent.tomorrow_available:type_name -> stormbreaker.v1.DailyPrice
+	7,  // 7: stormbreaker.v1.PriceEvent.settings_changed:type_name -> stormbreaker.v1.PriceSettings
+	3,  // 8: stormbreaker.v1.Stormbreaker.GetTodayTomorrow:input_type -> stormbreaker.v1.GetTodayTomorrowRequest
+	5,  // 9: stormbreaker.v1.Stormbreaker.GetMarketPrice:input_type -> stormbreaker.v1.GetMarketPriceRequest
+	8,  // 10: stormbreaker.v1.Stormbreaker.GetPriceSettings:input_type -> stormbreaker.v1.GetPriceSettingsRequest
+	9,  // 11: stormbreaker.v1.Stormbreaker.UpdatePriceSettings:input_type -> stormbreaker.v1.UpdatePriceSettingsRequest
+	10, // 12: stormbreaker.v1.Stormbreaker.DeletePriceSettings:input_type -> stormbreaker.v1.DeletePriceSettingsRequest
+	11, // 13: stormbreaker.v1.Stormbreaker.SubscribePrices:input_type -> stormbreaker.v1.SubscribePricesRequest
+	4,  // 14: stormbreaker.v1.Stormbreaker.GetTodayTomorrow:output_type -> stormbreaker.v1.TodayTomorrowPrice
+	6,  // 15: stormbreaker.v1.Stormbreaker.GetMarketPrice:output_type -> stormbreaker.v1.MarketPrice
+	7,  // 16: stormbreaker.v1.Stormbreaker.GetPriceSettings:output_type -> stormbreaker.v1.PriceSettings
+	7,  // 17: stormbreaker.v1.Stormbreaker.UpdatePriceSettings:output_type -> stormbreaker.v1.PriceSettings
+	14, // 18: stormbreaker.v1.Stormbreaker.DeletePriceSettings:output_type -> google.protobuf.Empty
+	13, // 19: stormbreaker.v1.Stormbreaker.SubscribePrices:output_type -> stormbreaker.v1.PriceEvent
+	14, // [14:20] is the sub-list for method output_type
+	8,  // [8:14] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_stormbreaker_v1_stormbreaker_proto_init() }
+func file_stormbreaker_v1_stormbreaker_proto_init() {
+	if File_stormbreaker_v1_stormbreaker_proto != nil {
+		return
+	}
+	file_stormbreaker_v1_stormbreaker_proto_msgTypes[9].OneofWrappers = []any{}
+	file_stormbreaker_v1_stormbreaker_proto_msgTypes[13].OneofWrappers = []any{
+		(*PriceEvent_Price)(nil),
+		(*PriceEvent_TomorrowAvailable)(nil),
+		(*PriceEvent_SettingsChanged)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_stormbreaker_v1_stormbreaker_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_stormbreaker_v1_stormbreaker_proto_goTypes,
+		DependencyIndexes: file_stormbreaker_v1_stormbreaker_proto_depIdxs,
+		MessageInfos:      file_stormbreaker_v1_stormbreaker_proto_msgTypes,
+	}.Build()
+	File_stormbreaker_v1_stormbreaker_proto = out.File
+	file_stormbreaker_v1_stormbreaker_proto_rawDesc = nil
+	file_stormbreaker_v1_stormbreaker_proto_goTypes = nil
+	file_stormbreaker_v1_stormbreaker_proto_depIdxs = nil
+}
@@ -0,0 +1,344 @@
+// AnhCao 2024
+//
+// gRPC API of stormbreaker for internal backend services (ex: notification-service).
+// It serves the same data as the REST API, with the same authentication: every call sends
+// the access token of user through `authorization` metadata (Ex: "Bearer <access_token>").
+//
+// Generate Go code after changing this file: make proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: stormbreaker/v1/stormbreaker.proto
+
+package stormbreakerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Stormbreaker_GetTodayTomorrow_FullMethodName    = "/stormbreaker.v1.Stormbreaker/GetTodayTomorrow"
+	Stormbreaker_GetMarketPrice_FullMethodName      = "/stormbreaker.v1.Stormbreaker/GetMarketPrice"
+	Stormbreaker_GetPriceSettings_FullMethodName    = "/stormbreaker.v1.Stormbreaker/GetPriceSettings"
+	Stormbreaker_UpdatePriceSettings_FullMethodName = "/stormbreaker.v1.Stormbreaker/UpdatePriceSettings"
+	Stormbreaker_DeletePriceSettings_FullMethodName = "/stormbreaker.v1.Stormbreaker/DeletePriceSettings"
+	Stormbreaker_SubscribePrices_FullMethodName     = "/stormbreaker.v1.Stormbreaker/SubscribePrices"
+)
+
+// StormbreakerClient is the client API for Stormbreaker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Stormbreaker serves market electric prices in Finland and price settings of user.
+// Every request selects the household through `household_id`. Empty value means the default household of user.
+type StormbreakerClient interface {
+	// GetTodayTomorrow returns the prices of today and tomorrow with price settings of household applied.
+	GetTodayTomorrow(ctx context.Context, in *GetTodayTomorrowRequest, opts ...grpc.CallOption) (*TodayTomorrowPrice, error)
+	// GetMarketPrice returns the prices in any time range. The price settings which were in force at each slot are applied.
+	GetMarketPrice(ctx context.Context, in *GetMarketPriceRequest, opts ...grpc.CallOption) (*MarketPrice, error)
+	// GetPriceSettings returns the price settings of household.
+	GetPriceSettings(ctx context.Context, in *GetPriceSettingsRequest, opts ...grpc.CallOption) (*PriceSettings, error)
+	// UpdatePriceSettings changes only the fields which are present in request and returns the changed settings.
+	UpdatePriceSettings(ctx context.Context, in *UpdatePriceSettingsRequest, opts ...grpc.CallOption) (*PriceSettings, error)
+	// DeletePriceSettings deletes the price settings of household.
+	DeletePriceSettings(ctx context.Context, in *DeletePriceSettingsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// SubscribePrices streams the current price when a new price slot begins, tomorrow's prices once they are published
+	// and the price settings after they were changed.
+	SubscribePrices(ctx context.Context, in *SubscribePricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PriceEvent], error)
+}
+
+type stormbreakerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStormbreakerClient(cc grpc.ClientConnInterface) StormbreakerClient {
+	return &stormbreakerClient{cc}
+}
+
+func (c *stormbreakerClient) GetTodayTomorrow(ctx context.Context, in *GetTodayTomorrowRequest, opts ...grpc.CallOption) (*TodayTomorrowPrice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodayTomorrowPrice)
+	err := c.cc.Invoke(ctx, Stormbreaker_GetTodayTomorrow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stormbreakerClient) GetMarketPrice(ctx context.Context, in *GetMarketPriceRequest, opts ...grpc.CallOption) (*MarketPrice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarketPrice)
+	err := c.cc.Invoke(ctx, Stormbreaker_GetMarketPrice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stormbreakerClient) GetPriceSettings(ctx context.Context, in *GetPriceSettingsRequest, opts ...grpc.CallOption) (*PriceSettings, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PriceSettings)
+	err := c.cc.Invoke(ctx, Stormbreaker_GetPriceSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stormbreakerClient) UpdatePriceSettings(ctx context.Context, in *UpdatePriceSettingsRequest, opts ...grpc.CallOption) (*PriceSettings, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PriceSettings)
+	err := c.cc.Invoke(ctx, Stormbreaker_UpdatePriceSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stormbreakerClient) DeletePriceSettings(ctx context.Context, in *DeletePriceSettingsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Stormbreaker_DeletePriceSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stormbreakerClient) SubscribePrices(ctx context.Context, in *SubscribePricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PriceEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Stormbreaker_ServiceDesc.Streams[0], Stormbreaker_SubscribePrices_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribePricesRequest, PriceEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Stormbreaker_SubscribePricesClient = grpc.ServerStreamingClient[PriceEvent]
+
+// StormbreakerServer is the server API for Stormbreaker service.
+// All implementations must embed UnimplementedStormbreakerServer
+// for forward compatibility.
+//
+// Stormbreaker serves market electric prices in Finland and price settings of user.
+// Every request selects the household through `household_id`. Empty value means the default household of user.
+type StormbreakerServer interface {
+	// GetTodayTomorrow returns the prices of today and tomorrow with price settings of household applied.
+	GetTodayTomorrow(context.Context, *GetTodayTomorrowRequest) (*TodayTomorrowPrice, error)
+	// GetMarketPrice returns the prices in any time range. The price settings which were in force at each slot are applied.
+	GetMarketPrice(context.Context, *GetMarketPriceRequest) (*MarketPrice, error)
+	// GetPriceSettings returns the price settings of household.
+	GetPriceSettings(context.Context, *GetPriceSettingsRequest) (*PriceSettings, error)
+	// UpdatePriceSettings changes only the fields which are present in request and returns the changed settings.
+	UpdatePriceSettings(context.Context, *UpdatePriceSettingsRequest) (*PriceSettings, error)
+	// DeletePriceSettings deletes the price settings of household.
+	DeletePriceSettings(context.Context, *DeletePriceSettingsRequest) (*emptypb.Empty, error)
+	// SubscribePrices streams the current price when a new price slot begins, tomorrow's prices once they are published
+	// and the price settings after they were changed.
+	SubscribePrices(*SubscribePricesRequest, grpc.ServerStreamingServer[PriceEvent]) error
+	mustEmbedUnimplementedStormbreakerServer()
+}
+
+// UnimplementedStormbreakerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStormbreakerServer struct{}
+
+func (UnimplementedStormbreakerServer) GetTodayTomorrow(context.Context, *GetTodayTomorrowRequest) (*TodayTomorrowPrice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTodayTomorrow not implemented")
+}
+func (UnimplementedStormbreakerServer) GetMarketPrice(context.Context, *GetMarketPriceRequest) (*MarketPrice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMarketPrice not implemented")
+}
+func (UnimplementedStormbreakerServer) GetPriceSettings(context.Context, *GetPriceSettingsRequest) (*PriceSettings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPriceSettings not implemented")
+}
+func (UnimplementedStormbreakerServer) UpdatePriceSettings(context.Context, *UpdatePriceSettingsRequest) (*PriceSettings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePriceSettings not implemented")
+}
+func (UnimplementedStormbreakerServer) DeletePriceSettings(context.Context, *DeletePriceSettingsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePriceSettings not implemented")
+}
+func (UnimplementedStormbreakerServer) SubscribePrices(*SubscribePricesRequest, grpc.ServerStreamingServer[PriceEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribePrices not implemented")
+}
+func (UnimplementedStormbreakerServer) mustEmbedUnimplementedStormbreakerServer() {}
+func (UnimplementedStormbreakerServer) testEmbeddedByValue()                      {}
+
+// UnsafeStormbreakerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StormbreakerServer will
+// result in compilation errors.
+type UnsafeStormbreakerServer interface {
+	mustEmbedUnimplementedStormbreakerServer()
+}
+
+func RegisterStormbreakerServer(s grpc.ServiceRegistrar, srv StormbreakerServer) {
+	// If the following call pancis, it indicates UnimplementedStormbreakerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Stormbreaker_ServiceDesc, srv)
+}
+
+func _Stormbreaker_GetTodayTomorrow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTodayTomorrowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StormbreakerServer).GetTodayTomorrow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stormbreaker_GetTodayTomorrow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StormbreakerServer).GetTodayTomorrow(ctx, req.(*GetTodayTomorrowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stormbreaker_GetMarketPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMarketPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StormbreakerServer).GetMarketPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stormbreaker_GetMarketPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StormbreakerServer).GetMarketPrice(ctx, req.(*GetMarketPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stormbreaker_GetPriceSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPriceSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StormbreakerServer).GetPriceSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stormbreaker_GetPriceSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StormbreakerServer).GetPriceSettings(ctx, req.(*GetPriceSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stormbreaker_UpdatePriceSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePriceSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StormbreakerServer).UpdatePriceSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stormbreaker_UpdatePriceSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StormbreakerServer).UpdatePriceSettings(ctx, req.(*UpdatePriceSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stormbreaker_DeletePriceSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePriceSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StormbreakerServer).DeletePriceSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stormbreaker_DeletePriceSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StormbreakerServer).DeletePriceSettings(ctx, req.(*DeletePriceSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stormbreaker_SubscribePrices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribePricesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StormbreakerServer).SubscribePrices(m, &grpc.GenericServerStream[SubscribePricesRequest, PriceEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Stormbreaker_SubscribePricesServer = grpc.ServerStreamingServer[PriceEvent]
+
+// Stormbreaker_ServiceDesc is the grpc.ServiceDesc for Stormbreaker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Stormbreaker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "stormbreaker.v1.Stormbreaker",
+	HandlerType: (*StormbreakerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTodayTomorrow",
+			Handler:    _Stormbreaker_GetTodayTomorrow_Handler,
+		},
+		{
+			MethodName: "GetMarketPrice",
+			Handler:    _Stormbreaker_GetMarketPrice_Handler,
+		},
+		{
+			MethodName: "GetPriceSettings",
+			Handler:    _Stormbreaker_GetPriceSettings_Handler,
+		},
+		{
+			MethodName: "UpdatePriceSettings",
+			Handler:    _Stormbreaker_UpdatePriceSettings_Handler,
+		},
+		{
+			MethodName: "DeletePriceSettings",
+			Handler:    _Stormbreaker_DeletePriceSettings_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribePrices",
+			Handler:       _Stormbreaker_SubscribePrices_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "stormbreaker/v1/stormbreaker.proto",
+}